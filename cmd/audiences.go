@@ -4,18 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"os"
 	"time"
 
+	"github.com/135yshr/devcycle-cli/internal/listfilter"
 	"github.com/135yshr/devcycle-cli/internal/output"
 	"github.com/135yshr/devcycle-cli/pkg/api"
 	"github.com/spf13/cobra"
 )
 
-// maxAudienceFileSize is the maximum file size allowed for JSON audience files (10MB)
-const maxAudienceFileSize = 10 * 1024 * 1024
-
 var audiencesCmd = &cobra.Command{
 	Use:   "audiences",
 	Short: "Manage audiences",
@@ -25,8 +21,16 @@ var audiencesCmd = &cobra.Command{
 var audiencesListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all audiences",
-	Long:  `List all audiences in a project.`,
-	RunE:  runAudiencesList,
+	Long: `List all audiences in a project.
+
+Audiences can be filtered by text and dates, and sorted. --search matches the
+key, name or description and is treated as a regular expression when written
+as /expr/.
+
+Examples:
+  dvcx audiences list --search beta --sort name
+  dvcx audiences list --updated-after 7d --sort updated --reverse`,
+	RunE: runAudiencesList,
 }
 
 var audiencesGetCmd = &cobra.Command{
@@ -40,14 +44,29 @@ var audiencesGetCmd = &cobra.Command{
 var audiencesCreateCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create a new audience",
-	Long:  `Create a new reusable audience.`,
-	RunE:  runAudiencesCreate,
+	Long: `Create a new reusable audience.
+
+Filters can be given as JSON with --filters or as an expression with --where,
+for example:
+
+  dvcx audiences create -n "NA Gold" -k na-gold \
+    --where 'country in ("CA", "US") and (customData.tier = "gold" or audience("beta"))'
+
+--from-file reads the audience from a JSON, JSONC or YAML file, in which
+${NAME} and ${NAME:-default} are replaced by environment variables. A YAML
+file may define several audiences as documents separated by "---":
+
+  dvcx audiences create --from-file audiences.yaml
+
+When stdin is a terminal and --name or --key is missing, the missing fields
+and the filter are asked for.`,
+	RunE: runAudiencesCreate,
 }
 
 var audiencesUpdateCmd = &cobra.Command{
 	Use:   "update [audience-key]",
 	Short: "Update an audience",
-	Long:  `Update an existing audience.`,
+	Long:  `Update an existing audience. Filters can be replaced with --filters (JSON) or --where (expression).`,
 	Args:  cobra.ExactArgs(1),
 	RunE:  runAudiencesUpdate,
 }
@@ -65,8 +84,10 @@ var audienceName string
 var audienceKey string
 var audienceDescription string
 var audienceFilters string
+var audienceWhere string
 var audienceFromFile string
 var audienceForce bool
+var audienceListFilter listFilterFlags
 
 func init() {
 	rootCmd.AddCommand(audiencesCmd)
@@ -79,18 +100,26 @@ func init() {
 	// Persistent flags for all audiences commands
 	audiencesCmd.PersistentFlags().StringVarP(&audienceProject, "project", "p", "", "project key (uses config default if not specified)")
 
+	// List command flags
+	addListFilterFlags(audiencesListCmd, &audienceListFilter, audienceListFields)
+	addListSortFlags(audiencesListCmd, &audienceListFilter)
+
 	// Create command flags
 	audiencesCreateCmd.Flags().StringVarP(&audienceName, "name", "n", "", "audience name (required)")
 	audiencesCreateCmd.Flags().StringVarP(&audienceKey, "key", "k", "", "audience key (required)")
 	audiencesCreateCmd.Flags().StringVar(&audienceDescription, "description", "", "audience description")
 	audiencesCreateCmd.Flags().StringVar(&audienceFilters, "filters", "", "audience filters as JSON")
-	audiencesCreateCmd.Flags().StringVarP(&audienceFromFile, "from-file", "F", "", "JSON file containing audience definition")
+	audiencesCreateCmd.Flags().StringVar(&audienceWhere, "where", "", "audience filters as an expression")
+	audiencesCreateCmd.MarkFlagsMutuallyExclusive("filters", "where")
+	audiencesCreateCmd.Flags().StringVarP(&audienceFromFile, "from-file", "F", "", "JSON or YAML file defining one or more audiences, use '-' for stdin")
 
 	// Update command flags
 	audiencesUpdateCmd.Flags().StringVarP(&audienceName, "name", "n", "", "audience name")
 	audiencesUpdateCmd.Flags().StringVar(&audienceDescription, "description", "", "audience description")
 	audiencesUpdateCmd.Flags().StringVar(&audienceFilters, "filters", "", "audience filters as JSON")
-	audiencesUpdateCmd.Flags().StringVarP(&audienceFromFile, "from-file", "F", "", "JSON file containing audience definition")
+	audiencesUpdateCmd.Flags().StringVar(&audienceWhere, "where", "", "audience filters as an expression")
+	audiencesUpdateCmd.MarkFlagsMutuallyExclusive("filters", "where")
+	audiencesUpdateCmd.Flags().StringVarP(&audienceFromFile, "from-file", "F", "", "JSON or YAML file containing audience definition, use '-' for stdin")
 
 	// Delete command flags
 	audiencesDeleteCmd.Flags().BoolVar(&audienceForce, "force", false, "skip confirmation prompt")
@@ -107,18 +136,12 @@ func (d audiencesTableData) Headers() []string {
 func (d audiencesTableData) Rows() [][]string {
 	rows := make([][]string, len(d.audiences))
 	for i, a := range d.audiences {
-		desc := a.Description
-		if len(desc) > 30 {
-			desc = desc[:27] + "..."
-		}
+		desc := truncate(a.Description, 30)
 		if desc == "" {
 			desc = "-"
 		}
 
-		filtersStr := "-"
-		if len(a.Filters.Filters) > 0 {
-			filtersStr = fmt.Sprintf("%d filter(s)", len(a.Filters.Filters))
-		}
+		filtersStr := truncate(describeFilters(a.Filters), 50)
 
 		rows[i] = []string{
 			a.Key,
@@ -142,6 +165,10 @@ func runAudiencesList(cmd *cobra.Command, args []string) error {
 	if projectKey == "" {
 		return errProjectRequired
 	}
+	filter, err := audienceListFilter.options()
+	if err != nil {
+		return err
+	}
 
 	client, err := getClient()
 	if err != nil {
@@ -155,6 +182,10 @@ func runAudiencesList(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	audiences, err = listfilter.Apply(audiences, audienceListFields, filter)
+	if err != nil {
+		return err
+	}
 
 	printer := output.NewPrinter(output.ParseFormat(GetOutput()))
 
@@ -193,33 +224,86 @@ func runAudiencesCreate(cmd *cobra.Command, args []string) error {
 		return errProjectRequired
 	}
 
-	var req api.CreateAudienceRequest
+	reqs := []api.CreateAudienceRequest{{}}
 
-	// Load from file if specified
+	// Load from file if specified; a YAML file may define several audiences
 	if audienceFromFile != "" {
-		file, err := os.Open(audienceFromFile)
+		data, err := api.ReadInputFile(audienceFromFile)
 		if err != nil {
-			return fmt.Errorf("failed to open file: %w", err)
+			return err
 		}
-		defer file.Close()
+		if reqs, err = api.DecodeInputAll[api.CreateAudienceRequest](data, audienceFromFile); err != nil {
+			return err
+		}
+	}
+	if len(reqs) > 1 && (audienceName != "" || audienceKey != "" || audienceDescription != "" || audienceFilters != "" || audienceWhere != "") {
+		return fmt.Errorf("flags cannot override a file that defines %d audiences", len(reqs))
+	}
 
-		info, err := file.Stat()
+	// Ask for the missing fields on a terminal
+	if audienceFromFile == "" && (audienceName == "" || audienceKey == "") && canPrompt() {
+		ok, err := askAudienceCreateFlags(cmd)
 		if err != nil {
-			return fmt.Errorf("failed to stat file: %w", err)
+			return err
+		}
+		if !ok {
+			cmd.Println("Creation cancelled")
+			return nil
+		}
+	}
+
+	for i := range reqs {
+		if err := applyAudienceCreateFlags(&reqs[i]); err != nil {
+			return err
 		}
-		if info.Size() > maxAudienceFileSize {
-			return fmt.Errorf("file %s exceeds maximum allowed size (%d bytes)", audienceFromFile, maxAudienceFileSize)
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	for i := range reqs {
+		if err := resolveAudienceRefs(ctx, client, projectKey, &reqs[i].Filters); err != nil {
+			return err
 		}
-		data, err := io.ReadAll(file)
+	}
+
+	// Each audience gets its own timeout, so that a long file does not run
+	// out of time halfway through
+	create := func(req *api.CreateAudienceRequest) (*api.AudienceDefinition, error) {
+		return withTimeout(context.Background(), func(ctx context.Context) (*api.AudienceDefinition, error) {
+			return client.CreateAudience(ctx, projectKey, req)
+		})
+	}
+
+	printer := output.NewPrinter(output.ParseFormat(GetOutput()))
+	if len(reqs) == 1 {
+		audience, err := create(&reqs[0])
 		if err != nil {
-			return fmt.Errorf("failed to read file: %w", err)
+			return err
 		}
-		if err := json.Unmarshal(data, &req); err != nil {
-			return fmt.Errorf("invalid JSON in file: %w", err)
+		return printer.Print(audience)
+	}
+
+	audiences := make([]*api.AudienceDefinition, 0, len(reqs))
+	for i := range reqs {
+		audience, err := create(&reqs[i])
+		if err != nil {
+			return fmt.Errorf("audience '%s': %w", reqs[i].Key, err)
 		}
+		audiences = append(audiences, audience)
+		cmd.Printf("Audience '%s' created successfully\n", reqs[i].Key)
 	}
+	return printer.Print(audiences)
+}
 
-	// Override with flags if provided
+// applyAudienceCreateFlags overrides req with the create flags, checks the
+// required fields and defaults the filters to all users.
+func applyAudienceCreateFlags(req *api.CreateAudienceRequest) error {
 	if audienceName != "" {
 		req.Name = audienceName
 	}
@@ -236,6 +320,13 @@ func runAudiencesCreate(cmd *cobra.Command, args []string) error {
 		}
 		req.Filters = filters
 	}
+	if audienceWhere != "" {
+		filters, err := parseWhere(audienceWhere)
+		if err != nil {
+			return err
+		}
+		req.Filters = filters
+	}
 
 	// Validate required fields
 	if req.Name == "" {
@@ -251,22 +342,7 @@ func runAudiencesCreate(cmd *cobra.Command, args []string) error {
 			Filters:  []api.Filter{{Type: "all"}},
 		}
 	}
-
-	client, err := getClient()
-	if err != nil {
-		return err
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	audience, err := client.CreateAudience(ctx, projectKey, &req)
-	if err != nil {
-		return err
-	}
-
-	printer := output.NewPrinter(output.ParseFormat(GetOutput()))
-	return printer.Print(audience)
+	return nil
 }
 
 func runAudiencesUpdate(cmd *cobra.Command, args []string) error {
@@ -279,25 +355,12 @@ func runAudiencesUpdate(cmd *cobra.Command, args []string) error {
 
 	// Load from file if specified
 	if audienceFromFile != "" {
-		file, err := os.Open(audienceFromFile)
+		data, err := api.ReadInputFile(audienceFromFile)
 		if err != nil {
-			return fmt.Errorf("failed to open file: %w", err)
+			return err
 		}
-		defer file.Close()
-
-		info, err := file.Stat()
-		if err != nil {
-			return fmt.Errorf("failed to stat file: %w", err)
-		}
-		if info.Size() > maxAudienceFileSize {
-			return fmt.Errorf("file %s exceeds maximum allowed size (%d bytes)", audienceFromFile, maxAudienceFileSize)
-		}
-		data, err := io.ReadAll(file)
-		if err != nil {
-			return fmt.Errorf("failed to read file: %w", err)
-		}
-		if err := json.Unmarshal(data, &req); err != nil {
-			return fmt.Errorf("invalid JSON in file: %w", err)
+		if err := api.DecodeInput(data, audienceFromFile, &req); err != nil {
+			return err
 		}
 	}
 
@@ -315,6 +378,13 @@ func runAudiencesUpdate(cmd *cobra.Command, args []string) error {
 		}
 		req.Filters = &filters
 	}
+	if audienceWhere != "" {
+		filters, err := parseWhere(audienceWhere)
+		if err != nil {
+			return err
+		}
+		req.Filters = &filters
+	}
 
 	client, err := getClient()
 	if err != nil {
@@ -324,6 +394,12 @@ func runAudiencesUpdate(cmd *cobra.Command, args []string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	if req.Filters != nil {
+		if err := resolveAudienceRefs(ctx, client, projectKey, req.Filters); err != nil {
+			return err
+		}
+	}
+
 	audience, err := client.UpdateAudience(ctx, projectKey, args[0], &req)
 	if err != nil {
 		return err
@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/135yshr/devcycle-cli/internal/bundle"
+	"github.com/135yshr/devcycle-cli/internal/config"
+	"github.com/135yshr/devcycle-cli/internal/workpool"
+	"github.com/135yshr/devcycle-cli/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+var bundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Build an offline SDK config bundle",
+	Long: `Build a self-contained SDK configuration for one environment.
+
+The bundle has the shape DevCycle's local-bucketing SDKs download from the
+CDN: the project and environment, every feature active in the environment
+with its variations and targeting rules, the variables those features serve
+and the project's audiences. Entries are sorted by key so bundles can be
+committed and diffed.
+
+The bundle is written to stdout, or to the file given with --out.`,
+	RunE: runBundle,
+}
+
+var bundleServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve an SDK config bundle over HTTP",
+	Long: `Serve an SDK config bundle on a local port so SDKs can be pointed at it.
+
+With --file the bundle is read from disk on every request, so edits are
+served without a restart. Otherwise it is built from the API once at
+startup. Every GET request for a path ending in .json, such as
+/config/v1/server/<sdk-key>.json, returns the bundle. Point the SDK's config
+CDN URI at the printed address and disable event logging.`,
+	RunE: runBundleServe,
+}
+
+var bundleProject string
+var bundleEnvironment string
+var bundleOut string
+var bundleFile string
+var bundleHost string
+var bundlePort int
+
+func init() {
+	rootCmd.AddCommand(bundleCmd)
+	bundleCmd.AddCommand(bundleServeCmd)
+
+	bundleCmd.PersistentFlags().StringVarP(&bundleProject, "project", "p", "", "project key (uses config default if not specified)")
+	bundleCmd.PersistentFlags().StringVarP(&bundleEnvironment, "environment", "e", "", "environment key (uses config default if not specified)")
+	bundleCmd.Flags().StringVar(&bundleOut, "out", "", "file to write the bundle to (default: stdout)")
+
+	bundleServeCmd.Flags().StringVar(&bundleFile, "file", "", "bundle file to serve (default: build from the API)")
+	bundleServeCmd.Flags().StringVar(&bundleHost, "host", "127.0.0.1", "address to listen on")
+	bundleServeCmd.Flags().IntVar(&bundlePort, "port", 8787, "port to listen on")
+}
+
+// buildBundle fetches everything needed for the bundle of one environment.
+func buildBundle(ctx context.Context) (*bundle.Config, error) {
+	projectKey := bundleProject
+	if projectKey == "" {
+		projectKey = getProjectKey()
+	}
+	if projectKey == "" {
+		return nil, errProjectRequired
+	}
+	envKey := bundleEnvironment
+	if envKey == "" {
+		envKey = config.Environment()
+	}
+	if envKey == "" {
+		return nil, errEnvironmentRequired
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := client.Project(ctx, projectKey)
+	if err != nil {
+		return nil, err
+	}
+	environment, err := client.Environment(ctx, projectKey, envKey)
+	if err != nil {
+		return nil, err
+	}
+	variables, err := client.Variables(ctx, projectKey)
+	if err != nil {
+		return nil, err
+	}
+	audiences, err := client.Audiences(ctx, projectKey)
+	if err != nil {
+		return nil, err
+	}
+	features, err := client.Features(ctx, projectKey)
+	if err != nil {
+		return nil, err
+	}
+
+	src := bundle.Source{
+		Project:     *project,
+		Environment: *environment,
+		Variables:   variables,
+		Audiences:   audiences,
+	}
+	sources, err := workpool.Map(ctx, featureKeys(features), fetchWorkers, func(ctx context.Context, key string) (*bundle.FeatureSource, error) {
+		configs, err := client.FeatureConfigurations(ctx, projectKey, key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get configuration for feature '%s': %w", key, err)
+		}
+		cfg := configs[envKey]
+		if cfg == nil || cfg.Status != "active" {
+			return nil, nil
+		}
+		variations, err := client.Variations(ctx, projectKey, key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get variations for feature '%s': %w", key, err)
+		}
+		return &bundle.FeatureSource{Variations: variations, Config: cfg}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range features {
+		if fs := sources[f.Key]; fs != nil {
+			fs.Feature = f
+			src.Features = append(src.Features, *fs)
+		}
+	}
+
+	return bundle.Build(src)
+}
+
+func runBundle(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	cfg, err := buildBundle(ctx)
+	if err != nil {
+		return err
+	}
+	data, err := bundle.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+
+	if bundleOut == "" {
+		_, err := cmd.OutOrStdout().Write(data)
+		return err
+	}
+	if err := os.WriteFile(bundleOut, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", bundleOut, err)
+	}
+	cmd.PrintErrf("Bundle with %d feature(s) written to %s\n", len(cfg.Features), bundleOut)
+	return nil
+}
+
+func runBundleServe(cmd *cobra.Command, args []string) error {
+	var load func() ([]byte, error)
+	if bundleFile == "-" {
+		return fmt.Errorf("--file must be a path; the bundle is re-read on every request")
+	}
+	if bundleFile != "" {
+		if _, err := api.ReadInputFile(bundleFile); err != nil {
+			return err
+		}
+		load = func() ([]byte, error) {
+			return api.ReadInputFile(bundleFile)
+		}
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		cfg, err := buildBundle(ctx)
+		cancel()
+		if err != nil {
+			return err
+		}
+		data, err := bundle.Marshal(cfg)
+		if err != nil {
+			return err
+		}
+		load = func() ([]byte, error) { return data, nil }
+	}
+
+	addr := net.JoinHostPort(bundleHost, strconv.Itoa(bundlePort))
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	server := &http.Server{
+		Handler:           bundle.Handler(load),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	cmd.Printf("Serving SDK config on http://%s (press Ctrl+C to stop)\n", listener.Addr())
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
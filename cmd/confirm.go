@@ -8,11 +8,17 @@ import (
 )
 
 func confirmDelete(resourceType, resourceKey string, force bool) bool {
+	return confirmAction(fmt.Sprintf("Are you sure you want to delete %s '%s'?", resourceType, resourceKey), force)
+}
+
+func confirmAction(prompt string, force bool) bool {
 	if force {
 		return true
 	}
 
-	fmt.Printf("Are you sure you want to delete %s '%s'? [y/N]: ", resourceType, resourceKey)
+	// The prompt goes to stderr so that it stays visible when the output is
+	// redirected
+	fmt.Fprintf(os.Stderr, "%s [y/N]: ", prompt)
 	reader := bufio.NewReader(os.Stdin)
 	response, _ := reader.ReadString('\n')
 	response = strings.TrimSpace(strings.ToLower(response))
@@ -0,0 +1,346 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/135yshr/devcycle-cli/internal/output"
+	"github.com/135yshr/devcycle-cli/internal/usages"
+	"github.com/135yshr/devcycle-cli/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+var diffUsagesCmd = &cobra.Command{
+	Use:   "diff-usages",
+	Short: "Report variable references added or removed by a change",
+	Long: `Report the variable keys that a change starts or stops referencing.
+
+The change is read from one of:
+  --base <ref> [--head <ref>]        run git diff against the merge base with <ref>
+                                     (the working tree is compared when --head is omitted)
+  --diff <file>                      read a unified diff ('-' for stdin)
+  --base-dir <dir> [--head-dir <dir>] compare two source trees
+
+Keys are found with the same patterns as 'dvcx usages'. A key only counts as
+added or removed when it does not appear on the other side, so moved code is
+not reported.
+
+Added keys are checked against the project and reported as missing when the
+variable does not exist. Removed keys are reported as active when their
+variable belongs to a feature that is still active in a production
+environment.
+
+With --annotations github or gitlab, the result is written as GitHub Actions
+workflow commands or a GitLab Code Quality report instead of a table.
+
+Examples:
+  dvcx diff-usages --base origin/main
+  git diff main... | dvcx diff-usages --diff - --strict
+  dvcx diff-usages --base origin/main --annotations github`,
+	RunE: runDiffUsages,
+}
+
+var diffUsagesProject string
+var diffUsagesBase string
+var diffUsagesHead string
+var diffUsagesFile string
+var diffUsagesBaseDir string
+var diffUsagesHeadDir string
+var diffUsagesAnnotations string
+var diffUsagesStrict bool
+var diffUsagesPatterns []string
+var diffUsagesExclude []string
+
+func init() {
+	rootCmd.AddCommand(diffUsagesCmd)
+
+	diffUsagesCmd.Flags().StringVarP(&diffUsagesProject, "project", "p", "", "project key (uses config default if not specified)")
+	diffUsagesCmd.Flags().StringVar(&diffUsagesBase, "base", "", "git ref to compare against")
+	diffUsagesCmd.Flags().StringVar(&diffUsagesHead, "head", "", "git ref to compare (default: the working tree)")
+	diffUsagesCmd.Flags().StringVar(&diffUsagesFile, "diff", "", "unified diff file to read ('-' for stdin)")
+	diffUsagesCmd.Flags().StringVar(&diffUsagesBaseDir, "base-dir", "", "source tree before the change")
+	diffUsagesCmd.Flags().StringVar(&diffUsagesHeadDir, "head-dir", ".", "source tree after the change (with --base-dir)")
+	diffUsagesCmd.Flags().StringVar(&diffUsagesAnnotations, "annotations", "", "write annotations instead of a table: github or gitlab")
+	diffUsagesCmd.Flags().BoolVar(&diffUsagesStrict, "strict", false, "exit with an error if an added key does not exist in the project")
+	diffUsagesCmd.Flags().StringArrayVar(&diffUsagesPatterns, "pattern", nil, "additional pattern as lang=regexp (can be repeated)")
+	diffUsagesCmd.Flags().StringSliceVar(&diffUsagesExclude, "exclude", nil, "glob of files or directories to skip (can be repeated)")
+	diffUsagesCmd.MarkFlagsMutuallyExclusive("base", "diff", "base-dir")
+}
+
+// Statuses of changed keys.
+const (
+	usageStatusOK      = "ok"
+	usageStatusMissing = "missing"
+	usageStatusActive  = "active"
+)
+
+// usageChange is a changed key together with what the project says about
+// it.
+type usageChange struct {
+	usages.KeyChange
+	Status  string `json:"status"`
+	Feature string `json:"feature,omitempty"`
+	Message string `json:"message"`
+}
+
+type usageChangesTableData struct {
+	changes []usageChange
+}
+
+func (d usageChangesTableData) Headers() []string {
+	return []string{"KEY", "CHANGE", "STATUS", "LOCATIONS"}
+}
+
+func (d usageChangesTableData) Rows() [][]string {
+	rows := make([][]string, len(d.changes))
+	for i, c := range d.changes {
+		rows[i] = []string{c.Key, c.Change, c.Status, formatUsageLocations(c.Locations)}
+	}
+	return rows
+}
+
+func runDiffUsages(cmd *cobra.Command, args []string) error {
+	projectKey := diffUsagesProject
+	if projectKey == "" {
+		projectKey = getProjectKey()
+	}
+	if projectKey == "" {
+		return errProjectRequired
+	}
+	switch diffUsagesAnnotations {
+	case "", "github", "gitlab":
+	default:
+		return fmt.Errorf("unsupported annotations format %q (must be github or gitlab)", diffUsagesAnnotations)
+	}
+	if diffUsagesBase == "" && diffUsagesFile == "" && diffUsagesBaseDir == "" {
+		return fmt.Errorf("one of --base, --diff or --base-dir is required")
+	}
+	if diffUsagesHead != "" && diffUsagesBase == "" {
+		return fmt.Errorf("--head requires --base")
+	}
+
+	removed, added, err := diffUsages()
+	if err != nil {
+		return err
+	}
+	keyChanges := usages.Compare(removed, added)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	changes, err := checkUsageChanges(ctx, projectKey, keyChanges)
+	if err != nil {
+		return err
+	}
+
+	switch diffUsagesAnnotations {
+	case "github":
+		err = usages.WriteGitHubAnnotations(cmd.OutOrStdout(), usageAnnotations(changes))
+	case "gitlab":
+		err = usages.WriteGitLabReport(cmd.OutOrStdout(), usageAnnotations(changes))
+	default:
+		printer := output.NewPrinter(output.ParseFormat(GetOutput()))
+		if output.ParseFormat(GetOutput()) == output.FormatTable {
+			if len(changes) == 0 {
+				cmd.Println("No variable references were added or removed")
+				return nil
+			}
+			err = printer.Print(usageChangesTableData{changes: changes})
+		} else {
+			err = printer.Print(changes)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if diffUsagesStrict {
+		var missing []string
+		for _, c := range changes {
+			if c.Status == usageStatusMissing {
+				missing = append(missing, c.Key)
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("variables not defined in project '%s': %s", projectKey, strings.Join(missing, ", "))
+		}
+	}
+	return nil
+}
+
+// diffUsages returns the usages on the removed and added side of the
+// change selected by the flags.
+func diffUsages() (removed, added []usages.Usage, err error) {
+	if diffUsagesBaseDir != "" {
+		before, err := newUsagesScanner(diffUsagesPatterns, diffUsagesExclude)
+		if err != nil {
+			return nil, nil, err
+		}
+		after, err := newUsagesScanner(diffUsagesPatterns, diffUsagesExclude)
+		if err != nil {
+			return nil, nil, err
+		}
+		if removed, err = before.Scan([]string{diffUsagesBaseDir}); err != nil {
+			return nil, nil, err
+		}
+		if added, err = after.Scan([]string{diffUsagesHeadDir}); err != nil {
+			return nil, nil, err
+		}
+		return removed, added, nil
+	}
+
+	scanner, err := newUsagesScanner(diffUsagesPatterns, diffUsagesExclude)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Generated accessors are resolved from the current tree, where the
+	// diff paths are relative to.
+	if err := scanner.LoadGenerated([]string{"."}); err != nil {
+		return nil, nil, err
+	}
+
+	var diff []byte
+	if diffUsagesFile != "" {
+		diff, err = api.ReadInputFile(diffUsagesFile)
+	} else {
+		diff, err = gitDiff(diffUsagesBase, diffUsagesHead)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return scanner.ScanDiff(bytes.NewReader(diff))
+}
+
+// gitDiff returns the diff between the merge base of base and head and
+// head, or the working tree when head is empty. Paths are relative to the
+// current directory.
+func gitDiff(base, head string) ([]byte, error) {
+	// Refs are passed to git as arguments, where a leading "-" would make
+	// them options
+	for _, ref := range []string{base, head} {
+		if strings.HasPrefix(ref, "-") {
+			return nil, fmt.Errorf("invalid git ref %q", ref)
+		}
+	}
+	args := []string{"diff", "--no-color", "--no-ext-diff", "--relative", "-U0"}
+	if head != "" {
+		args = append(args, base+"..."+head)
+	} else {
+		args = append(args, "--merge-base", base)
+	}
+	var stderr bytes.Buffer
+	c := exec.Command("git", args...)
+	c.Stderr = &stderr
+	out, err := c.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && stderr.Len() > 0 {
+			return nil, fmt.Errorf("git diff failed: %s", strings.TrimSpace(stderr.String()))
+		}
+		return nil, fmt.Errorf("failed to run git diff: %w", err)
+	}
+	return out, nil
+}
+
+// checkUsageChanges looks up the variable of each changed key. Added keys
+// must exist; removed keys are flagged while their feature is still active
+// in a production environment.
+func checkUsageChanges(ctx context.Context, projectKey string, keyChanges []usages.KeyChange) ([]usageChange, error) {
+	changes := make([]usageChange, 0, len(keyChanges))
+	if len(keyChanges) == 0 {
+		return changes, nil
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return nil, err
+	}
+
+	var production []string
+	envsLoaded := false
+	features := make(map[string]*api.Feature)
+	activeIn := make(map[string][]string)
+
+	for _, kc := range keyChanges {
+		c := usageChange{KeyChange: kc, Status: usageStatusOK}
+		variable, err := client.Variable(ctx, projectKey, kc.Key)
+		if err != nil && !api.IsNotFound(err) {
+			return nil, err
+		}
+
+		if kc.Change == usages.ChangeAdded {
+			if variable == nil {
+				c.Status = usageStatusMissing
+				c.Message = fmt.Sprintf("Variable '%s' does not exist in project '%s'", kc.Key, projectKey)
+			} else {
+				c.Message = fmt.Sprintf("Adds a reference to variable '%s'", kc.Key)
+			}
+			changes = append(changes, c)
+			continue
+		}
+
+		c.Message = fmt.Sprintf("Removes the last reference to variable '%s'", kc.Key)
+		if variable == nil || variable.Feature == "" {
+			changes = append(changes, c)
+			continue
+		}
+
+		if !envsLoaded {
+			if production, err = productionEnvironments(ctx, client, projectKey); err != nil {
+				return nil, err
+			}
+			envsLoaded = true
+		}
+
+		feature, ok := features[variable.Feature]
+		if !ok {
+			feature, err = client.Feature(ctx, projectKey, variable.Feature)
+			if err != nil {
+				return nil, err
+			}
+			configs, err := client.FeatureConfigurations(ctx, projectKey, feature.Key)
+			if err != nil {
+				return nil, err
+			}
+			activeIn[feature.Key] = activeEnvironments(configs, production)
+			features[variable.Feature] = feature
+		}
+
+		c.Feature = feature.Key
+		if envs := activeIn[feature.Key]; len(envs) > 0 {
+			c.Status = usageStatusActive
+			c.Message = fmt.Sprintf("Variable '%s' is removed but feature '%s' is still active in production (%s)", kc.Key, feature.Key, strings.Join(envs, ", "))
+		}
+		changes = append(changes, c)
+	}
+	return changes, nil
+}
+
+// usageAnnotations returns an annotation for each location of each change.
+func usageAnnotations(changes []usageChange) []usages.Annotation {
+	var annotations []usages.Annotation
+	for _, c := range changes {
+		severity, check := usages.SeverityNotice, "dvcx/"+c.Change+"-variable"
+		switch c.Status {
+		case usageStatusMissing:
+			severity, check = usages.SeverityError, "dvcx/missing-variable"
+		case usageStatusActive:
+			severity, check = usages.SeverityWarning, "dvcx/active-feature"
+		}
+		for _, loc := range c.Locations {
+			annotations = append(annotations, usages.Annotation{
+				Severity: severity,
+				Check:    check,
+				File:     loc.File,
+				Line:     loc.Line,
+				Message:  c.Message,
+			})
+		}
+	}
+	return annotations
+}
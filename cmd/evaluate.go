@@ -0,0 +1,298 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/135yshr/devcycle-cli/internal/config"
+	"github.com/135yshr/devcycle-cli/internal/evaluate"
+	"github.com/135yshr/devcycle-cli/internal/output"
+	"github.com/135yshr/devcycle-cli/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+var evaluateCmd = &cobra.Command{
+	Use:   "evaluate",
+	Short: "Simulate feature evaluation for a user",
+	Long: `Simulate how a feature is evaluated for a user in an environment.
+
+The targeting rules are fetched and tried in order against the user given
+with --user. The first rule whose audience matches decides the variation,
+using the same bucketing as the DevCycle SDKs. The output explains each
+rule and condition that was checked, the matched rule, the variation served
+and its variable values.
+
+With --users, every user in a newline-delimited JSON file is evaluated and
+the number of users served each variation is reported per environment and
+per rule. All environments are evaluated unless --environment is given.
+--compare takes a proposed targeting file, in the same format as
+"targeting update --from-file" or a single environment configuration, and
+reports how many users would switch variation.
+
+Opt-in filters cannot be checked locally and never match.
+
+Examples:
+  dvcx evaluate -f dark-mode -e production \
+    --user '{"user_id":"u1","email":"jane@acme.com","customData":{"plan":"pro"}}'
+
+  dvcx evaluate -f dark-mode -e production --users users.ndjson --compare proposed.json`,
+	RunE: runEvaluate,
+}
+
+var evaluateProject string
+var evaluateFeature string
+var evaluateEnvironments []string
+var evaluateUser string
+var evaluateUsersFile string
+var evaluateCompareFile string
+
+func init() {
+	rootCmd.AddCommand(evaluateCmd)
+
+	evaluateCmd.Flags().StringVarP(&evaluateProject, "project", "p", "", "project key (uses config default if not specified)")
+	evaluateCmd.Flags().StringVarP(&evaluateFeature, "feature", "f", "", "feature key (required)")
+	evaluateCmd.Flags().StringSliceVarP(&evaluateEnvironments, "environment", "e", nil, "environment key; repeatable with --users (uses config default if not specified)")
+	evaluateCmd.Flags().StringVar(&evaluateUser, "user", "", "user as JSON, e.g. '{\"user_id\":\"u1\"}'")
+	evaluateCmd.Flags().StringVar(&evaluateUsersFile, "users", "", "newline-delimited JSON file of users to evaluate (- for stdin)")
+	evaluateCmd.Flags().StringVar(&evaluateCompareFile, "compare", "", "proposed targeting JSON file to compare against the current configuration")
+	evaluateCmd.MarkFlagsMutuallyExclusive("user", "users")
+	evaluateCmd.MarkFlagsOneRequired("user", "users")
+}
+
+// evaluationContext holds everything needed to evaluate one feature.
+type evaluationContext struct {
+	configs   map[string]*api.EnvironmentConfig
+	evaluator *evaluate.Evaluator
+}
+
+func loadEvaluationContext(ctx context.Context, client *api.Client, projectKey, featureKey string) (*evaluationContext, error) {
+	configs, err := client.FeatureConfigurations(ctx, projectKey, featureKey)
+	if err != nil {
+		return nil, err
+	}
+	variations, err := client.Variations(ctx, projectKey, featureKey)
+	if err != nil {
+		return nil, err
+	}
+	audiences, err := client.Audiences(ctx, projectKey)
+	if err != nil {
+		return nil, err
+	}
+	return &evaluationContext{
+		configs:   configs,
+		evaluator: evaluate.New(variations, audiences),
+	}, nil
+}
+
+func (c *evaluationContext) environment(envKey, featureKey string) (*api.EnvironmentConfig, error) {
+	cfg, ok := c.configs[envKey]
+	if !ok {
+		return nil, fmt.Errorf("environment '%s' not found for feature '%s'", envKey, featureKey)
+	}
+	return cfg, nil
+}
+
+func runEvaluate(cmd *cobra.Command, args []string) error {
+	projectKey := evaluateProject
+	if projectKey == "" {
+		projectKey = getProjectKey()
+	}
+	if projectKey == "" {
+		return errProjectRequired
+	}
+	if evaluateFeature == "" {
+		return fmt.Errorf("required flag \"feature\" not set")
+	}
+	if evaluateUsersFile != "" {
+		return runEvaluateUsers(cmd, projectKey)
+	}
+	if evaluateCompareFile != "" {
+		return fmt.Errorf("--compare requires --users")
+	}
+
+	envKey := config.Environment()
+	switch len(evaluateEnvironments) {
+	case 0:
+	case 1:
+		envKey = evaluateEnvironments[0]
+	default:
+		return fmt.Errorf("only one environment can be evaluated with --user")
+	}
+	if envKey == "" {
+		return errEnvironmentRequired
+	}
+	user, err := evaluate.ParseUser([]byte(evaluateUser))
+	if err != nil {
+		return err
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	ec, err := loadEvaluationContext(ctx, client, projectKey, evaluateFeature)
+	if err != nil {
+		return err
+	}
+	cfg, err := ec.environment(envKey, evaluateFeature)
+	if err != nil {
+		return err
+	}
+
+	result, err := ec.evaluator.Evaluate(cfg, user)
+	if err != nil {
+		return err
+	}
+
+	if output.ParseFormat(GetOutput()) == output.FormatTable {
+		result.WriteText(cmd.OutOrStdout(), useColor())
+		return nil
+	}
+	printer := output.NewPrinter(output.ParseFormat(GetOutput()))
+	return printer.Print(result)
+}
+
+// evaluationReport is the machine-readable result of evaluate --users.
+type evaluationReport struct {
+	Feature      string                       `json:"feature"`
+	Users        int                          `json:"users"`
+	Environments []evaluate.EnvironmentReport `json:"environments"`
+}
+
+func runEvaluateUsers(cmd *cobra.Command, projectKey string) error {
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	ec, err := loadEvaluationContext(ctx, client, projectKey, evaluateFeature)
+	if err != nil {
+		return err
+	}
+
+	envKeys := evaluateEnvironments
+	if len(envKeys) == 0 {
+		for k := range ec.configs {
+			envKeys = append(envKeys, k)
+		}
+		sort.Strings(envKeys)
+	}
+	current := make([]*api.EnvironmentConfig, len(envKeys))
+	for i, k := range envKeys {
+		if current[i], err = ec.environment(k, evaluateFeature); err != nil {
+			return err
+		}
+	}
+
+	var proposed []*api.EnvironmentConfig
+	if evaluateCompareFile != "" {
+		if proposed, err = loadProposedConfigs(evaluateCompareFile, envKeys, current); err != nil {
+			return err
+		}
+	}
+
+	var in io.Reader = os.Stdin
+	if evaluateUsersFile != "-" {
+		file, err := os.Open(evaluateUsersFile)
+		if err != nil {
+			return fmt.Errorf("failed to open file %s: %w", evaluateUsersFile, err)
+		}
+		defer file.Close()
+		in = file
+	}
+
+	tallies := make([]*evaluate.Tally, len(envKeys))
+	for i := range tallies {
+		tallies[i] = evaluate.NewTally()
+	}
+	users := 0
+	err = evaluate.ReadUsers(in, func(user evaluate.User) error {
+		users++
+		for i, cfg := range current {
+			result, err := ec.evaluator.Evaluate(cfg, user)
+			if err != nil {
+				return fmt.Errorf("environment '%s': %w", envKeys[i], err)
+			}
+			tallies[i].Add(result)
+			if proposed == nil {
+				continue
+			}
+			next, err := ec.evaluator.Evaluate(proposed[i], user)
+			if err != nil {
+				return fmt.Errorf("environment '%s' (proposed): %w", envKeys[i], err)
+			}
+			tallies[i].AddComparison(result, next)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", evaluateUsersFile, err)
+	}
+
+	report := evaluationReport{Feature: evaluateFeature, Users: users}
+	for i, k := range envKeys {
+		report.Environments = append(report.Environments, tallies[i].Report(k))
+	}
+
+	if output.ParseFormat(GetOutput()) == output.FormatTable {
+		return evaluate.WriteReports(cmd.OutOrStdout(), report.Environments, useColor())
+	}
+	printer := output.NewPrinter(output.ParseFormat(GetOutput()))
+	return printer.Print(report)
+}
+
+// loadProposedConfigs reads a proposed targeting file and returns the
+// configuration to compare for each environment. The file holds either a
+// map of environment keys to configurations, like the file accepted by
+// targeting update, or a single configuration when one environment is
+// evaluated. Environments missing from a map keep their current
+// configuration.
+func loadProposedConfigs(path string, envKeys []string, current []*api.EnvironmentConfig) ([]*api.EnvironmentConfig, error) {
+	data, err := api.ReadInputFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := api.DecodeInput(data, path, &fields); err != nil {
+		return nil, err
+	}
+	_, hasStatus := fields["status"]
+	_, hasTargets := fields["targets"]
+	if hasStatus || hasTargets {
+		if len(envKeys) != 1 {
+			return nil, fmt.Errorf("%s holds a single configuration; select one environment with --environment", path)
+		}
+		var cfg api.EnvironmentConfig
+		if err := api.DecodeInput(data, path, &cfg); err != nil {
+			return nil, err
+		}
+		return []*api.EnvironmentConfig{&cfg}, nil
+	}
+
+	var configs map[string]*api.EnvironmentConfig
+	if err := api.DecodeInput(data, path, &configs); err != nil {
+		return nil, err
+	}
+	proposed := make([]*api.EnvironmentConfig, len(envKeys))
+	for i, k := range envKeys {
+		if cfg, ok := configs[k]; ok {
+			proposed[i] = cfg
+		} else {
+			proposed[i] = current[i]
+		}
+	}
+	return proposed, nil
+}
@@ -2,11 +2,17 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/135yshr/devcycle-cli/internal/config"
+	"github.com/135yshr/devcycle-cli/internal/inputs"
+	"github.com/135yshr/devcycle-cli/internal/jsondiff"
+	"github.com/135yshr/devcycle-cli/internal/listfilter"
 	"github.com/135yshr/devcycle-cli/internal/output"
+	"github.com/135yshr/devcycle-cli/internal/templates"
 	"github.com/135yshr/devcycle-cli/pkg/api"
 	"github.com/spf13/cobra"
 )
@@ -20,31 +26,91 @@ var featuresCmd = &cobra.Command{
 var featuresListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all features",
-	Long:  `List all features in a project.`,
-	RunE:  runFeaturesList,
+	Long: `List all features in a project.
+
+Features can be filtered by type, status, tag, text and dates, and sorted.
+Only the status filter and the sort order are sent to the server; the other
+filters are applied locally. --search matches the key, name or description and is treated as a
+regular expression when written as /expr/.
+
+Examples:
+  dvcx features list --status active --type release
+  dvcx features list --search checkout --sort updated --reverse
+  dvcx features list --search '/^exp-/' --created-after 30d
+  dvcx features list --tag payments --updated-before 2026-01-01`,
+	RunE: runFeaturesList,
 }
 
 var featuresGetCmd = &cobra.Command{
 	Use:   "get [feature-key]",
 	Short: "Get feature details",
-	Long:  `Get detailed information about a specific feature.`,
-	Args:  cobra.ExactArgs(1),
-	RunE:  runFeaturesGet,
+	Long: `Get detailed information about a specific feature.
+
+With --expand, the feature is shown with its variables, variations and their
+values, SDK visibility, settings and a summary of its configuration in each
+environment.
+
+Examples:
+  dvcx features get dark-mode
+  dvcx features get dark-mode --expand
+  dvcx features get dark-mode --expand -o json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFeaturesGet,
 }
 
 var featuresCreateCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create a new feature",
-	Long:  `Create a new feature in a project.`,
-	RunE:  runFeaturesCreate,
+	Long: `Create a new feature in a project.
+
+--name and --key create a simple feature through the v1 API. --from-file
+creates a feature with its variables, variations and configurations through
+the v2 API from a JSON, JSONC or YAML file. A YAML file may define several
+features as documents separated by "---"; every document is validated before
+any feature is created.
+
+--template renders the feature from a template instead of a file (see 'dvcx
+templates list'). Its parameters are given with --set name=value; --key,
+--name and --description set the parameters of the same name.
+
+When stdin is a terminal and --name or --key is missing, the feature is
+asked for step by step: its name and key, type, variables, variations and
+their values, and its status in each environment of the project. The result
+is shown for confirmation before it is created through the v2 API.
+
+References to environment variables, ${NAME} or ${NAME:-default}, are
+replaced in the file before it is parsed.
+
+Examples:
+  dvcx features create --name "Dark Mode" --key dark-mode
+  dvcx features create --from-file dark-mode.yaml
+  dvcx features create --from-file features.yaml --dry-run
+  dvcx features create --template ab-test --key checkout-v2 --set variable=checkout_v2_enabled
+  cat dark-mode.json | dvcx features create -F -`,
+	RunE: runFeaturesCreate,
 }
 
 var featuresUpdateCmd = &cobra.Command{
 	Use:   "update [feature-key]",
 	Short: "Update a feature",
-	Long:  `Update an existing feature.`,
-	Args:  cobra.ExactArgs(1),
-	RunE:  runFeaturesUpdate,
+	Long: `Update an existing feature.
+
+--name and --description update those fields through the v1 API. --from-file
+sends a full feature definition (JSON or YAML, as for 'features create') to
+the v2 API instead. With --merge the file only needs the fields to change:
+it is merged onto the current feature as a JSON Merge Patch (RFC 7386).
+
+The changes are shown as a diff against the current feature and confirmed
+before they are applied. --dry-run validates the file and shows the diff
+without applying it.
+
+Examples:
+  dvcx features update dark-mode --name "Dark Mode v2"
+  dvcx features update dark-mode --from-file dark-mode.yaml
+  dvcx features update dark-mode --from-file patch.json --merge --dry-run
+  cat dark-mode.json | dvcx features update dark-mode -F - -f`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFeaturesUpdate,
 }
 
 var featuresDeleteCmd = &cobra.Command{
@@ -63,6 +129,11 @@ var featureType string
 var featureForce bool
 var featureFromFile string
 var featureDryRun bool
+var featureListFilter listFilterFlags
+var featureGetExpand bool
+var featureMerge bool
+var featureTemplate string
+var featureSets []string
 
 func init() {
 	rootCmd.AddCommand(featuresCmd)
@@ -74,17 +145,30 @@ func init() {
 
 	featuresCmd.PersistentFlags().StringVarP(&featureProject, "project", "p", "", "project key (uses config default if not specified)")
 
+	// List command flags
+	addListFilterFlags(featuresListCmd, &featureListFilter, featureListFields)
+	addListSortFlags(featuresListCmd, &featureListFilter)
+
+	// Get command flags
+	featuresGetCmd.Flags().BoolVar(&featureGetExpand, "expand", false, "include variables, variations and environment configuration")
+
 	// Create command flags
 	featuresCreateCmd.Flags().StringVarP(&featureName, "name", "n", "", "feature name (required for simple create)")
 	featuresCreateCmd.Flags().StringVarP(&featureKey, "key", "k", "", "feature key (required for simple create)")
 	featuresCreateCmd.Flags().StringVarP(&featureDescription, "description", "d", "", "feature description")
 	featuresCreateCmd.Flags().StringVarP(&featureType, "type", "t", "release", "feature type (release, experiment, permission, ops)")
-	featuresCreateCmd.Flags().StringVarP(&featureFromFile, "from-file", "F", "", "JSON input file for feature creation (uses v2 API), use '-' for stdin")
+	featuresCreateCmd.Flags().StringVarP(&featureFromFile, "from-file", "F", "", "JSON or YAML feature definition (uses v2 API), use '-' for stdin")
+	featuresCreateCmd.Flags().StringVar(&featureTemplate, "template", "", "render the feature from a template (uses v2 API), see 'dvcx templates list'")
+	featuresCreateCmd.Flags().StringArrayVar(&featureSets, "set", nil, "template parameter as name=value (repeatable)")
 	featuresCreateCmd.Flags().BoolVar(&featureDryRun, "dry-run", false, "validate configuration without creating")
 
 	// Update command flags
 	featuresUpdateCmd.Flags().StringVarP(&featureName, "name", "n", "", "feature name")
 	featuresUpdateCmd.Flags().StringVarP(&featureDescription, "description", "d", "", "feature description")
+	featuresUpdateCmd.Flags().StringVarP(&featureFromFile, "from-file", "F", "", "JSON or YAML feature definition to apply (uses v2 API), use '-' for stdin")
+	featuresUpdateCmd.Flags().BoolVar(&featureMerge, "merge", false, "only change the fields present in --from-file")
+	featuresUpdateCmd.Flags().BoolVar(&featureDryRun, "dry-run", false, "validate and show the changes without applying them")
+	featuresUpdateCmd.Flags().BoolVarP(&featureForce, "force", "f", false, "skip confirmation prompt")
 
 	// Delete command flags
 	featuresDeleteCmd.Flags().BoolVarP(&featureForce, "force", "f", false, "skip confirmation prompt")
@@ -95,7 +179,7 @@ type featuresTableData struct {
 }
 
 func (d featuresTableData) Headers() []string {
-	return []string{"KEY", "NAME", "TYPE", "STATUS", "CREATED"}
+	return []string{"KEY", "NAME", "TYPE", "STATUS", "TAGS", "CREATED"}
 }
 
 func (d featuresTableData) Rows() [][]string {
@@ -106,6 +190,7 @@ func (d featuresTableData) Rows() [][]string {
 			f.Name,
 			f.Type,
 			f.Status,
+			strings.Join(f.Tags, ","),
 			f.CreatedAt.Format("2006-01-02"),
 		}
 	}
@@ -117,6 +202,15 @@ func runFeaturesList(cmd *cobra.Command, args []string) error {
 	if projectKey == "" {
 		return errProjectRequired
 	}
+	switch featureListFilter.Status {
+	case "", api.FeatureStatusActive, api.FeatureStatusComplete, api.FeatureStatusArchived:
+	default:
+		return fmt.Errorf("invalid status %q (must be active, complete or archived)", featureListFilter.Status)
+	}
+	filter, err := featureListFilter.options()
+	if err != nil {
+		return err
+	}
 
 	client, err := getClient()
 	if err != nil {
@@ -126,7 +220,11 @@ func runFeaturesList(cmd *cobra.Command, args []string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	features, err := client.Features(ctx, projectKey)
+	features, err := client.FeaturesWithOptions(ctx, projectKey, featureListOptions(filter))
+	if err != nil {
+		return err
+	}
+	features, err = listfilter.Apply(features, featureListFields, filter)
 	if err != nil {
 		return err
 	}
@@ -139,6 +237,29 @@ func runFeaturesList(cmd *cobra.Command, args []string) error {
 	return printer.Print(features)
 }
 
+// featureListOptions returns the status filter and sort order, which the
+// features API applies itself. The full filter is still applied locally to
+// the result.
+func featureListOptions(filter listfilter.Options) *api.FeatureListOptions {
+	opts := &api.FeatureListOptions{
+		Status: filter.Status,
+	}
+	sortBy := map[string]string{
+		listfilter.SortKey:     "key",
+		listfilter.SortName:    "name",
+		listfilter.SortCreated: "createdAt",
+		listfilter.SortUpdated: "updatedAt",
+	}
+	if by, ok := sortBy[filter.Sort]; ok {
+		opts.SortBy = by
+		opts.SortOrder = "asc"
+		if filter.Reverse {
+			opts.SortOrder = "desc"
+		}
+	}
+	return opts
+}
+
 func runFeaturesGet(cmd *cobra.Command, args []string) error {
 	projectKey := getProjectKey()
 	if projectKey == "" {
@@ -153,12 +274,23 @@ func runFeaturesGet(cmd *cobra.Command, args []string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	printer := output.NewPrinter(output.ParseFormat(GetOutput()))
+
+	if featureGetExpand {
+		expanded, err := fetchExpandedFeature(ctx, client, projectKey, args[0])
+		if err != nil {
+			return err
+		}
+		if output.ParseFormat(GetOutput()) == output.FormatTable {
+			return printExpandedFeature(cmd.OutOrStdout(), expanded)
+		}
+		return printer.Print(expanded)
+	}
+
 	feature, err := client.Feature(ctx, projectKey, args[0])
 	if err != nil {
 		return err
 	}
-
-	printer := output.NewPrinter(output.ParseFormat(GetOutput()))
 	return printer.Print(feature)
 }
 
@@ -170,10 +302,18 @@ func getProjectKey() string {
 }
 
 func runFeaturesCreate(cmd *cobra.Command, args []string) error {
-	// Use v2 API when --from-file is specified
-	if featureFromFile != "" {
+	// Use v2 API when --from-file or --template is specified
+	if featureFromFile != "" || featureTemplate != "" {
 		return runFeaturesCreateV2(cmd, args)
 	}
+	if len(featureSets) > 0 {
+		return fmt.Errorf("--set requires --template")
+	}
+
+	// Ask for the feature when required flags are missing on a terminal
+	if (featureName == "" || featureKey == "") && canPrompt() {
+		return runFeaturesCreateWizard(cmd)
+	}
 
 	// Validate required flags for simple create
 	if featureName == "" {
@@ -218,20 +358,32 @@ func runFeaturesCreateV2(cmd *cobra.Command, args []string) error {
 		return errProjectRequired
 	}
 
-	// Load feature request from file
-	req, err := api.LoadFeatureRequestFromFile(featureFromFile)
+	// Load feature requests from file, where a YAML file may define
+	// several, or render one from a template
+	var reqs []*api.CreateFeatureV2Request
+	var err error
+	if featureTemplate != "" {
+		reqs, err = renderFeatureTemplate(cmd)
+	} else {
+		reqs, err = api.LoadFeatureRequestsFromFile(featureFromFile)
+	}
 	if err != nil {
 		return err
 	}
 
-	// Validate the request
-	if err := api.ValidateFeatureRequest(req); err != nil {
-		return fmt.Errorf("validation error: %w", err)
+	// Validate every request before creating any feature
+	for i, req := range reqs {
+		if err := validateFeatureRequest(req); err != nil {
+			if len(reqs) > 1 {
+				return fmt.Errorf("validation error in document %d: %w", i+1, err)
+			}
+			return fmt.Errorf("validation error: %w", err)
+		}
 	}
 
 	// Dry-run mode: validate and show preview without creating
 	if featureDryRun {
-		return printDryRunPreview(cmd, req)
+		return printDryRunPreview(cmd, reqs)
 	}
 
 	client, err := getClient()
@@ -239,24 +391,82 @@ func runFeaturesCreateV2(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+	// Each feature gets its own timeout, so that a long file does not run
+	// out of time halfway through
+	create := func(req *api.CreateFeatureV2Request) (*api.FeatureV2, error) {
+		return withTimeout(context.Background(), func(ctx context.Context) (*api.FeatureV2, error) {
+			return client.CreateFeatureV2(ctx, projectKey, req)
+		})
+	}
 
-	feature, err := client.CreateFeatureV2(ctx, projectKey, req)
+	printer := output.NewPrinter(output.ParseFormat(GetOutput()))
+	if len(reqs) == 1 {
+		feature, err := create(reqs[0])
+		if err != nil {
+			return err
+		}
+		return printer.Print(feature)
+	}
+
+	features := make([]*api.FeatureV2, 0, len(reqs))
+	for _, req := range reqs {
+		feature, err := create(req)
+		if err != nil {
+			return fmt.Errorf("feature '%s': %w", req.Key, err)
+		}
+		features = append(features, feature)
+		cmd.Printf("Feature '%s' created successfully\n", req.Key)
+	}
+	return printer.Print(features)
+}
+
+// renderFeatureTemplate renders the feature of --template with the
+// parameters given by --set, --key, --name and --description. --type
+// replaces the type of the rendered feature.
+func renderFeatureTemplate(cmd *cobra.Command) ([]*api.CreateFeatureV2Request, error) {
+	if featureFromFile != "" {
+		return nil, fmt.Errorf("--template cannot be combined with --from-file")
+	}
+	t, err := findTemplate(featureTemplate)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	values, err := templates.ParseValues(featureSets)
+	if err != nil {
+		return nil, err
+	}
+	for param, value := range map[string]string{"key": featureKey, "name": featureName, "description": featureDescription} {
+		if cmd.Flags().Changed(param) {
+			values[param] = value
+		}
 	}
 
-	printer := output.NewPrinter(output.ParseFormat(GetOutput()))
-	return printer.Print(feature)
+	req, err := t.Render(values)
+	if err != nil {
+		return nil, err
+	}
+	if cmd.Flags().Changed("type") {
+		req.Type = featureType
+	}
+	return []*api.CreateFeatureV2Request{req}, nil
 }
 
-func printDryRunPreview(cmd *cobra.Command, req *api.CreateFeatureV2Request) error {
+func printDryRunPreview(cmd *cobra.Command, reqs []*api.CreateFeatureV2Request) error {
 	cmd.Println("Validating feature configuration...")
 	cmd.Println()
-	cmd.Println("[OK] JSON syntax valid")
+	cmd.Println("[OK] Syntax valid")
 	cmd.Println("[OK] Schema validation passed")
+	for _, req := range reqs {
+		cmd.Println()
+		printFeaturePreview(cmd, req)
+	}
+
 	cmd.Println()
+	cmd.Println("Dry-run complete. No changes were made.")
+	return nil
+}
+
+func printFeaturePreview(cmd *cobra.Command, req *api.CreateFeatureV2Request) {
 	cmd.Println("Feature Preview:")
 	cmd.Println("----------------")
 	cmd.Printf("Name:        %s\n", req.Name)
@@ -309,13 +519,19 @@ func printDryRunPreview(cmd *cobra.Command, req *api.CreateFeatureV2Request) err
 			cmd.Printf("  %s: %s\n", envKey, status)
 		}
 	}
-
-	cmd.Println()
-	cmd.Println("Dry-run complete. No changes were made.")
-	return nil
 }
 
 func runFeaturesUpdate(cmd *cobra.Command, args []string) error {
+	if featureFromFile != "" {
+		if cmd.Flags().Changed("name") || cmd.Flags().Changed("description") {
+			return fmt.Errorf("--from-file cannot be combined with --name or --description")
+		}
+		return runFeaturesUpdateV2(cmd, args)
+	}
+	if featureMerge || featureDryRun {
+		return fmt.Errorf("--merge and --dry-run require --from-file")
+	}
+
 	projectKey := getProjectKey()
 	if projectKey == "" {
 		return errProjectRequired
@@ -343,6 +559,116 @@ func runFeaturesUpdate(cmd *cobra.Command, args []string) error {
 	return printer.Print(feature)
 }
 
+func runFeaturesUpdateV2(cmd *cobra.Command, args []string) error {
+	projectKey := getProjectKey()
+	if projectKey == "" {
+		return errProjectRequired
+	}
+	featureKey := args[0]
+	// The confirmation is read from stdin, which the input has used up
+	if featureFromFile == "-" && !featureForce && !featureDryRun {
+		return fmt.Errorf("--from-file - requires --force or --dry-run, as stdin cannot also answer the confirmation")
+	}
+
+	data, err := api.ReadInputFile(featureFromFile)
+	if err != nil {
+		return err
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	current, err := withTimeout(context.Background(), func(ctx context.Context) (*api.FeatureV2, error) {
+		return client.FeatureV2(ctx, projectKey, featureKey)
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := desiredFeatureRequest(current, data)
+	if err != nil {
+		return err
+	}
+	if err := validateFeatureRequest(req); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	ops, err := jsondiff.Diff(current.Request(), req)
+	if err != nil {
+		return err
+	}
+	if len(ops) == 0 {
+		cmd.Printf("Feature '%s' is already up to date\n", featureKey)
+		return nil
+	}
+	cmd.Printf("Changes to feature '%s':\n", featureKey)
+	jsondiff.Write(cmd.OutOrStderr(), ops, useColor())
+
+	if featureDryRun {
+		cmd.Println("Dry-run complete. No changes were made.")
+		return nil
+	}
+	if !confirmAction(fmt.Sprintf("Apply %d change(s) to feature '%s'?", len(ops), featureKey), featureForce) {
+		cmd.Println("Update cancelled")
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	feature, err := client.UpdateFeatureV2(ctx, projectKey, featureKey, req)
+	if err != nil {
+		return err
+	}
+
+	printer := output.NewPrinter(output.ParseFormat(GetOutput()))
+	return printer.Print(feature)
+}
+
+// validateFeatureRequest checks req as the API would, and the variable
+// values of its variations against the types and validation schemas of its
+// variables.
+func validateFeatureRequest(req *api.CreateFeatureV2Request) error {
+	if err := api.ValidateFeatureRequest(req); err != nil {
+		return err
+	}
+	return inputs.ValidateFeatureValues(req)
+}
+
+// desiredFeatureRequest decodes the --from-file input. With --merge the
+// input is a JSON Merge Patch applied to the current feature; otherwise it
+// is the complete definition.
+func desiredFeatureRequest(current *api.FeatureV2, data []byte) (*api.CreateFeatureV2Request, error) {
+	var req api.CreateFeatureV2Request
+	if !featureMerge {
+		if err := api.DecodeInput(data, featureFromFile, &req); err != nil {
+			return nil, err
+		}
+		return &req, nil
+	}
+
+	var patch any
+	if err := api.DecodeInput(data, featureFromFile, &patch); err != nil {
+		return nil, err
+	}
+	if _, ok := patch.(map[string]any); !ok {
+		return nil, fmt.Errorf("--merge input must be an object")
+	}
+	base, err := jsondiff.Normalize(current.Request())
+	if err != nil {
+		return nil, err
+	}
+	merged, err := json.Marshal(jsondiff.MergePatch(base, patch))
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge feature: %w", err)
+	}
+	if err := json.Unmarshal(merged, &req); err != nil {
+		return nil, fmt.Errorf("failed to merge feature: %w", err)
+	}
+	return &req, nil
+}
+
 func runFeaturesDelete(cmd *cobra.Command, args []string) error {
 	projectKey := getProjectKey()
 	if projectKey == "" {
@@ -0,0 +1,267 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/135yshr/devcycle-cli/internal/output"
+	"github.com/135yshr/devcycle-cli/internal/stale"
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+// expandedFeature is a feature together with its variables, variations and
+// per-environment configuration, as shown by 'features get --expand'.
+type expandedFeature struct {
+	Feature      *api.FeatureV2       `json:"feature"`
+	Variables    []api.Variable       `json:"variables"`
+	Variations   []api.Variation      `json:"variations"`
+	Environments []environmentSummary `json:"environments"`
+}
+
+// environmentSummary summarizes the configuration of a feature in one
+// environment.
+type environmentSummary struct {
+	Key    string `json:"key"`
+	Name   string `json:"name,omitempty"`
+	Type   string `json:"type,omitempty"`
+	Status string `json:"status"`
+	Rules  int    `json:"rules"`
+	// Serves is the variation served to every user, if there is one.
+	Serves string `json:"serves,omitempty"`
+}
+
+// fetchExpandedFeature fetches a feature and everything attached to it.
+// The requests run concurrently; the first failure cancels the others.
+func fetchExpandedFeature(ctx context.Context, client *api.Client, projectKey, featureKey string) (*expandedFeature, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg         sync.WaitGroup
+		mu         sync.Mutex
+		firstErr   error
+		feature    *api.FeatureV2
+		variables  []api.Variable
+		variations []api.Variation
+		configs    map[string]*api.EnvironmentConfig
+		envs       []api.Environment
+	)
+	fetch := func(fn func() error) {
+		wg.Go(func() {
+			if err := fn(); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				mu.Unlock()
+			}
+		})
+	}
+	fetch(func() (err error) {
+		feature, err = client.FeatureV2(ctx, projectKey, featureKey)
+		return err
+	})
+	fetch(func() (err error) {
+		variables, err = client.Variables(ctx, projectKey)
+		return err
+	})
+	fetch(func() (err error) {
+		variations, err = client.Variations(ctx, projectKey, featureKey)
+		return err
+	})
+	fetch(func() (err error) {
+		configs, err = client.FeatureConfigurations(ctx, projectKey, featureKey)
+		return err
+	})
+	fetch(func() (err error) {
+		envs, err = client.Environments(ctx, projectKey)
+		return err
+	})
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	expanded := &expandedFeature{Feature: feature, Variations: variations}
+	for _, v := range variables {
+		if v.Feature == feature.ID || v.Feature == feature.Key {
+			expanded.Variables = append(expanded.Variables, v)
+		}
+	}
+	sort.Slice(expanded.Variables, func(i, j int) bool { return expanded.Variables[i].Key < expanded.Variables[j].Key })
+	expanded.Environments = summarizeEnvironments(configs, envs, variations)
+	return expanded, nil
+}
+
+// summarizeEnvironments summarizes configs, ordered by environment key.
+// Environments without a configuration are reported as inactive.
+func summarizeEnvironments(configs map[string]*api.EnvironmentConfig, envs []api.Environment, variations []api.Variation) []environmentSummary {
+	variationKeys := make(map[string]string, len(variations))
+	for _, v := range variations {
+		variationKeys[v.ID] = v.Key
+	}
+
+	byKey := make(map[string]*environmentSummary)
+	for _, env := range envs {
+		byKey[env.Key] = &environmentSummary{Key: env.Key, Name: env.Name, Type: env.Type, Status: "inactive"}
+	}
+	for key, cfg := range configs {
+		s, ok := byKey[key]
+		if !ok {
+			s = &environmentSummary{Key: key}
+			byKey[key] = s
+		}
+		if cfg == nil {
+			continue
+		}
+		if cfg.Status != "" {
+			s.Status = cfg.Status
+		}
+		s.Rules = len(cfg.Targets)
+		if served, ok := stale.ServedVariation(cfg.Targets); ok {
+			if key, found := variationKeys[served]; found {
+				served = key
+			}
+			s.Serves = served
+		}
+	}
+
+	summaries := make([]environmentSummary, 0, len(byKey))
+	for _, s := range byKey {
+		summaries = append(summaries, *s)
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Key < summaries[j].Key })
+	return summaries
+}
+
+// printExpandedFeature writes the sectioned table view of an expanded
+// feature to w.
+func printExpandedFeature(w io.Writer, e *expandedFeature) error {
+	f := e.Feature
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "FEATURE")
+	fmt.Fprintf(tw, "  Key:\t%s\n", f.Key)
+	fmt.Fprintf(tw, "  Name:\t%s\n", f.Name)
+	fmt.Fprintf(tw, "  Type:\t%s\n", f.Type)
+	fmt.Fprintf(tw, "  Status:\t%s\n", f.Status)
+	if f.Description != "" {
+		fmt.Fprintf(tw, "  Description:\t%s\n", f.Description)
+	}
+	fmt.Fprintf(tw, "  Tags:\t%s\n", formatTags(f.Tags))
+	if f.ControlVariation != "" {
+		fmt.Fprintf(tw, "  Control variation:\t%s\n", f.ControlVariation)
+	}
+	fmt.Fprintf(tw, "  Created:\t%s\n", f.CreatedAt.Format("2006-01-02 15:04"))
+	fmt.Fprintf(tw, "  Updated:\t%s\n", f.UpdatedAt.Format("2006-01-02 15:04"))
+
+	if v := f.SDKVisibility; v != nil {
+		fmt.Fprintln(tw, "\nSDK VISIBILITY")
+		fmt.Fprintf(tw, "  Mobile:\t%s\n", yesNo(v.Mobile))
+		fmt.Fprintf(tw, "  Client:\t%s\n", yesNo(v.Client))
+		fmt.Fprintf(tw, "  Server:\t%s\n", yesNo(v.Server))
+	}
+	if s := f.Settings; s != nil {
+		fmt.Fprintln(tw, "\nSETTINGS")
+		fmt.Fprintf(tw, "  Public name:\t%s\n", s.PublicName)
+		fmt.Fprintf(tw, "  Public description:\t%s\n", s.PublicDescription)
+		fmt.Fprintf(tw, "  Opt-in enabled:\t%s\n", yesNo(s.OptInEnabled))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	printer := output.NewPrinter(output.FormatTable)
+	printer.SetWriter(w)
+	sections := []struct {
+		title string
+		data  output.TableData
+	}{
+		{fmt.Sprintf("VARIABLES (%d)", len(e.Variables)), expandedVariablesTable(e.Variables)},
+		{fmt.Sprintf("VARIATIONS (%d)", len(e.Variations)), expandedVariationsTable(e.Variations)},
+		{fmt.Sprintf("ENVIRONMENTS (%d)", len(e.Environments)), expandedEnvironmentsTable(e.Environments)},
+	}
+	for _, s := range sections {
+		fmt.Fprintf(w, "\n%s\n", s.title)
+		if len(s.data.Rows()) == 0 {
+			fmt.Fprintln(w, "  (none)")
+			continue
+		}
+		if err := printer.Print(s.data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+type expandedVariablesTable []api.Variable
+
+func (d expandedVariablesTable) Headers() []string {
+	return []string{"KEY", "NAME", "TYPE", "STATUS"}
+}
+
+func (d expandedVariablesTable) Rows() [][]string {
+	rows := make([][]string, len(d))
+	for i, v := range d {
+		rows[i] = []string{v.Key, v.Name, v.Type, v.Status}
+	}
+	return rows
+}
+
+type expandedVariationsTable []api.Variation
+
+func (d expandedVariationsTable) Headers() []string {
+	return []string{"KEY", "NAME", "VALUES"}
+}
+
+func (d expandedVariationsTable) Rows() [][]string {
+	rows := make([][]string, len(d))
+	for i, v := range d {
+		keys := make([]string, 0, len(v.Variables))
+		for key := range v.Variables {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		values := make([]string, len(keys))
+		for j, key := range keys {
+			value, err := json.Marshal(v.Variables[key])
+			if err != nil {
+				value = []byte(fmt.Sprint(v.Variables[key]))
+			}
+			values[j] = key + "=" + string(value)
+		}
+		rows[i] = []string{v.Key, v.Name, strings.Join(values, ", ")}
+	}
+	return rows
+}
+
+type expandedEnvironmentsTable []environmentSummary
+
+func (d expandedEnvironmentsTable) Headers() []string {
+	return []string{"ENVIRONMENT", "TYPE", "STATUS", "RULES", "SERVES"}
+}
+
+func (d expandedEnvironmentsTable) Rows() [][]string {
+	rows := make([][]string, len(d))
+	for i, s := range d {
+		serves := s.Serves
+		if serves == "" {
+			serves = "-"
+		}
+		rows[i] = []string{s.Key, s.Type, s.Status, fmt.Sprintf("%d", s.Rules), serves}
+	}
+	return rows
+}
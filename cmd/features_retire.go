@@ -0,0 +1,318 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/135yshr/devcycle-cli/internal/config"
+	"github.com/135yshr/devcycle-cli/internal/retire"
+	"github.com/135yshr/devcycle-cli/internal/usages"
+	"github.com/135yshr/devcycle-cli/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+// retireStateDir is the directory under the config directory where features
+// waiting for deletion are recorded
+const retireStateDir = "retirements"
+
+var featuresRetireCmd = &cobra.Command{
+	Use:   "retire [feature-key]",
+	Short: "Retire a fully rolled out feature",
+	Long: `Retire a fully rolled out feature in two runs.
+
+The first run checks that every environment serves the --keep-variation
+variation to every user (or pins it with --pin), prints the variable values
+the code should hard-code and, with --usages, the code references to remove.
+It then marks the feature complete (or archived with --status archived) and
+records it under .devcycle/retirements.
+
+Running the command again after the grace period deletes the feature, after
+confirmation. Before then it only reports how long is left.
+
+Examples:
+  dvcx features retire new-checkout --keep-variation on --usages ./src
+  dvcx features retire new-checkout --keep-variation on --pin --dry-run
+  dvcx features retire new-checkout`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFeaturesRetire,
+}
+
+var retireKeepVariation string
+var retirePin bool
+var retireStatus string
+var retireGraceDays int
+var retireUsagePaths []string
+var retireDryRun bool
+var retireForce bool
+
+func init() {
+	featuresCmd.AddCommand(featuresRetireCmd)
+
+	featuresRetireCmd.Flags().StringVar(&retireKeepVariation, "keep-variation", "", "variation to keep, by key or ID (required for the first run)")
+	featuresRetireCmd.Flags().BoolVar(&retirePin, "pin", false, "serve the kept variation to everyone in environments that do not yet")
+	featuresRetireCmd.Flags().StringVar(&retireStatus, "status", api.FeatureStatusComplete, "status to set: complete or archived")
+	featuresRetireCmd.Flags().IntVar(&retireGraceDays, "grace-days", 14, "days to wait after the first run before the feature can be deleted")
+	featuresRetireCmd.Flags().StringSliceVar(&retireUsagePaths, "usages", nil, "source paths to scan for references to the feature's variables")
+	featuresRetireCmd.Flags().BoolVar(&retireDryRun, "dry-run", false, "show what would be done without making changes")
+	featuresRetireCmd.Flags().BoolVarP(&retireForce, "force", "f", false, "skip confirmation prompts")
+}
+
+func runFeaturesRetire(cmd *cobra.Command, args []string) error {
+	projectKey := getProjectKey()
+	if projectKey == "" {
+		return errProjectRequired
+	}
+	featureKey := args[0]
+	if retireStatus != api.FeatureStatusComplete && retireStatus != api.FeatureStatusArchived {
+		return fmt.Errorf("invalid status %q (must be complete or archived)", retireStatus)
+	}
+	if retireGraceDays < 0 {
+		return fmt.Errorf("--grace-days must not be negative")
+	}
+
+	configDir, err := config.ConfigDirPath()
+	if err != nil {
+		return err
+	}
+	statePath := retire.StatePath(filepath.Join(configDir, retireStateDir), projectKey, featureKey)
+	state, err := retire.LoadState(statePath)
+	if err != nil {
+		return err
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	if state != nil {
+		return finishRetirement(cmd, client, state, statePath)
+	}
+	if retireKeepVariation == "" {
+		return fmt.Errorf("required flag \"keep-variation\" not set")
+	}
+
+	// Writes get their own timeouts, as they follow the prompts
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	variations, err := client.Variations(ctx, projectKey, featureKey)
+	if err != nil {
+		return err
+	}
+	var kept *api.Variation
+	for i := range variations {
+		if variations[i].Key == retireKeepVariation || variations[i].ID == retireKeepVariation {
+			kept = &variations[i]
+			break
+		}
+	}
+	if kept == nil {
+		return fmt.Errorf("variation '%s' not found in feature '%s'", retireKeepVariation, featureKey)
+	}
+
+	configs, err := client.FeatureConfigurations(ctx, projectKey, featureKey)
+	if err != nil {
+		return err
+	}
+	checks := retire.Check(configs, *kept)
+	var failing []string
+	cmd.Printf("Checking that every environment serves '%s':\n", kept.Key)
+	for _, c := range checks {
+		if c.OK {
+			cmd.Printf("  PASS  %s\n", c.Environment)
+		} else {
+			cmd.Printf("  FAIL  %s: %s\n", c.Environment, c.Reason)
+			failing = append(failing, c.Environment)
+		}
+	}
+
+	if len(failing) > 0 {
+		if !retirePin {
+			return fmt.Errorf("feature '%s' does not serve '%s' to everyone in %s; use --pin to pin it", featureKey, kept.Key, strings.Join(failing, ", "))
+		}
+		if retireDryRun {
+			cmd.Printf("Would pin '%s' in %s\n", kept.Key, strings.Join(failing, ", "))
+		} else {
+			if !confirmAction(fmt.Sprintf("Serve '%s' to everyone in %s?", kept.Key, strings.Join(failing, ", ")), retireForce) {
+				cmd.Println("Retirement cancelled")
+				return nil
+			}
+			pinned, err := retire.Pin(configs, variations, kept.Key)
+			if err != nil {
+				return err
+			}
+			req := &api.UpdateFeatureConfigurationsRequest{Configurations: pinned}
+			_, err = withTimeout(context.Background(), func(ctx context.Context) (map[string]*api.EnvironmentConfig, error) {
+				return client.UpdateFeatureConfigurations(ctx, projectKey, featureKey, req)
+			})
+			if err != nil {
+				return err
+			}
+			cmd.Printf("Pinned '%s' in %s\n", kept.Key, strings.Join(failing, ", "))
+		}
+	}
+
+	if err := printRetiredValues(cmd, kept); err != nil {
+		return err
+	}
+	if len(retireUsagePaths) > 0 {
+		if err := printRetiredUsages(cmd, kept); err != nil {
+			return err
+		}
+	}
+
+	deleteAfter := time.Now().AddDate(0, 0, retireGraceDays)
+	if retireDryRun {
+		cmd.Printf("\nWould mark feature '%s' %s; it could be deleted after %s\n", featureKey, retireStatus, deleteAfter.Format("2006-01-02"))
+		cmd.Println("Dry-run complete. No changes were made.")
+		return nil
+	}
+
+	req := &api.UpdateFeatureStatusRequest{Status: retireStatus}
+	if retireStatus == api.FeatureStatusComplete {
+		req.StaticVariation = kept.Key
+	}
+	_, err = withTimeout(context.Background(), func(ctx context.Context) (*api.Feature, error) {
+		return client.UpdateFeatureStatus(ctx, projectKey, featureKey, req)
+	})
+	if err != nil {
+		return err
+	}
+	state = &retire.State{
+		Project:     projectKey,
+		Feature:     featureKey,
+		Variation:   kept.Key,
+		Status:      retireStatus,
+		CompletedAt: time.Now(),
+		DeleteAfter: deleteAfter,
+	}
+	if err := state.Save(statePath); err != nil {
+		return err
+	}
+
+	cmd.Printf("\nFeature '%s' marked %s\n", featureKey, retireStatus)
+	cmd.Printf("Run 'dvcx features retire %s' again after %s to delete it\n", featureKey, deleteAfter.Format("2006-01-02"))
+	return nil
+}
+
+// finishRetirement deletes a feature recorded by an earlier run once its
+// grace period has passed.
+func finishRetirement(cmd *cobra.Command, client *api.Client, state *retire.State, statePath string) error {
+	if now := time.Now(); now.Before(state.DeleteAfter) {
+		left := int(state.DeleteAfter.Sub(now).Hours()/24) + 1
+		cmd.Printf("Feature '%s' was marked %s on %s keeping '%s'\n", state.Feature, state.Status, state.CompletedAt.Format("2006-01-02"), state.Variation)
+		cmd.Printf("It can be deleted after %s (%d days left)\n", state.DeleteAfter.Format("2006-01-02"), left)
+		return nil
+	}
+
+	if err := verifyRetirement(client, state, statePath); err != nil {
+		return err
+	}
+
+	if retireDryRun {
+		cmd.Printf("Would delete feature '%s'\n", state.Feature)
+		cmd.Println("Dry-run complete. No changes were made.")
+		return nil
+	}
+	if !confirmAction(fmt.Sprintf("Grace period is over. Delete feature '%s'? This cannot be undone.", state.Feature), retireForce) {
+		cmd.Println("Delete cancelled")
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := client.DeleteFeature(ctx, state.Project, state.Feature); err != nil {
+		return err
+	}
+	if err := os.Remove(statePath); err != nil {
+		return fmt.Errorf("failed to remove retirement state: %w", err)
+	}
+	cmd.Printf("Feature '%s' deleted successfully\n", state.Feature)
+	return nil
+}
+
+// verifyRetirement checks that the feature recorded in state has not been
+// changed since it was marked, so that a feature in use is never deleted.
+// A changed feature must be retired again, so its state is removed.
+func verifyRetirement(client *api.Client, state *retire.State, statePath string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	feature, err := client.Feature(ctx, state.Project, state.Feature)
+	if err != nil {
+		return err
+	}
+	configs, err := client.FeatureConfigurations(ctx, state.Project, state.Feature)
+	if err != nil {
+		return err
+	}
+	variations, err := client.Variations(ctx, state.Project, state.Feature)
+	if err != nil {
+		return err
+	}
+	if err := state.Verify(feature, configs, variations); err != nil {
+		if !retireDryRun {
+			if err := os.Remove(statePath); err != nil {
+				return fmt.Errorf("failed to remove retirement state: %w", err)
+			}
+		}
+		return fmt.Errorf("%w; run 'dvcx features retire %s --keep-variation <variation>' to start over", err, state.Feature)
+	}
+	return nil
+}
+
+// printRetiredValues prints the values of the kept variation, which the
+// code should use in place of the variables.
+func printRetiredValues(cmd *cobra.Command, kept *api.Variation) error {
+	keys := make([]string, 0, len(kept.Variables))
+	for key := range kept.Variables {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	cmd.Printf("\nValues to hard-code (variation '%s'):\n", kept.Key)
+	for _, key := range keys {
+		value, err := json.Marshal(kept.Variables[key])
+		if err != nil {
+			return fmt.Errorf("failed to format value of variable '%s': %w", key, err)
+		}
+		cmd.Printf("  %s = %s\n", key, value)
+	}
+	return nil
+}
+
+// printRetiredUsages prints the code references to the kept variation's
+// variables.
+func printRetiredUsages(cmd *cobra.Command, kept *api.Variation) error {
+	scanner, err := newUsagesScanner(nil, nil)
+	if err != nil {
+		return err
+	}
+	found, err := scanner.Scan(retireUsagePaths)
+	if err != nil {
+		return err
+	}
+
+	var refs []usages.Usage
+	for _, u := range found {
+		if _, ok := kept.Variables[u.Key]; ok {
+			refs = append(refs, u)
+		}
+	}
+	if len(refs) == 0 {
+		cmd.Println("\nNo code references found")
+		return nil
+	}
+	cmd.Println("\nCode references to remove:")
+	for _, u := range refs {
+		cmd.Printf("  %s  %s\n", u.Location(), u.Key)
+	}
+	return nil
+}
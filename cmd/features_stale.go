@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/135yshr/devcycle-cli/internal/output"
+	"github.com/135yshr/devcycle-cli/internal/stale"
+	"github.com/135yshr/devcycle-cli/internal/usages"
+	"github.com/135yshr/devcycle-cli/internal/workpool"
+	"github.com/spf13/cobra"
+)
+
+var featuresStaleCmd = &cobra.Command{
+	Use:   "stale",
+	Short: "Find features that are candidates for cleanup",
+	Long: `Find features that are candidates for cleanup.
+
+Each feature is classified from its age, its latest change (the later of its
+last update and its latest audit log entry) and how it is served in each
+environment:
+
+  ready to remove     serves one variation to everyone in every environment
+                      and has not changed for --rolled-out-days, or is
+                      inactive everywhere, unchanged for --abandoned-days
+                      and not referenced in code (with --usages)
+  fully rolled out    serves one variation everywhere but changed recently
+  possibly abandoned  inactive everywhere, or partially rolled out, and
+                      unchanged for --abandoned-days
+
+Features that fit none of these are only listed with --all.
+
+With --usages the given source paths are scanned as with 'dvcx usages', and
+features whose variables are not referenced in code say so in their reasons.`,
+	RunE: runFeaturesStale,
+}
+
+var staleRolledOutDays int
+var staleAbandonedDays int
+var staleSort string
+var staleAll bool
+var staleUsagePaths []string
+
+func init() {
+	featuresCmd.AddCommand(featuresStaleCmd)
+
+	featuresStaleCmd.Flags().IntVar(&staleRolledOutDays, "rolled-out-days", int(stale.DefaultThresholds.RolledOut/(24*time.Hour)), "days a fully rolled out feature must be unchanged to be ready to remove")
+	featuresStaleCmd.Flags().IntVar(&staleAbandonedDays, "abandoned-days", int(stale.DefaultThresholds.Abandoned/(24*time.Hour)), "days without changes after which a feature is possibly abandoned")
+	featuresStaleCmd.Flags().StringVar(&staleSort, "sort", "class", "sort order: class, key, age or idle")
+	featuresStaleCmd.Flags().BoolVar(&staleAll, "all", false, "include features that are in use")
+	featuresStaleCmd.Flags().StringSliceVar(&staleUsagePaths, "usages", nil, "source paths to scan for variable references")
+}
+
+type staleTableData struct {
+	results []stale.Result
+	usages  bool
+}
+
+func (d staleTableData) Headers() []string {
+	if d.usages {
+		return []string{"KEY", "CLASSIFICATION", "AGE", "IDLE", "USAGES", "REASONS"}
+	}
+	return []string{"KEY", "CLASSIFICATION", "AGE", "IDLE", "REASONS"}
+}
+
+func (d staleTableData) Rows() [][]string {
+	rows := make([][]string, len(d.results))
+	for i, r := range d.results {
+		row := []string{r.Key, r.Class, fmt.Sprintf("%dd", r.AgeDays), fmt.Sprintf("%dd", r.IdleDays)}
+		if d.usages {
+			n := "-"
+			if r.Usages != nil {
+				n = strconv.Itoa(*r.Usages)
+			}
+			row = append(row, n)
+		}
+		rows[i] = append(row, strings.Join(r.Reasons, "; "))
+	}
+	return rows
+}
+
+func runFeaturesStale(cmd *cobra.Command, args []string) error {
+	projectKey := getProjectKey()
+	if projectKey == "" {
+		return errProjectRequired
+	}
+	if staleRolledOutDays < 0 || staleAbandonedDays < 0 {
+		return fmt.Errorf("--rolled-out-days and --abandoned-days must not be negative")
+	}
+	if err := stale.Sort(nil, staleSort); err != nil {
+		return err
+	}
+	thresholds := stale.Thresholds{
+		RolledOut: time.Duration(staleRolledOutDays) * 24 * time.Hour,
+		Abandoned: time.Duration(staleAbandonedDays) * 24 * time.Hour,
+	}
+
+	var found []usages.Usage
+	if len(staleUsagePaths) > 0 {
+		scanner, err := newUsagesScanner(nil, nil)
+		if err != nil {
+			return err
+		}
+		if found, err = scanner.Scan(staleUsagePaths); err != nil {
+			return err
+		}
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	features, err := client.Features(ctx, projectKey)
+	if err != nil {
+		return err
+	}
+
+	// Count code references per feature through its variables.
+	featureUsages := make(map[string]int)
+	if len(staleUsagePaths) > 0 {
+		variables, err := client.Variables(ctx, projectKey)
+		if err != nil {
+			return err
+		}
+		perKey := make(map[string]int)
+		for _, u := range found {
+			perKey[u.Key]++
+		}
+		for _, v := range variables {
+			featureUsages[v.Feature] += perKey[v.Key]
+		}
+	}
+
+	inputs, err := workpool.Map(ctx, featureKeys(features), fetchWorkers, func(ctx context.Context, key string) (stale.Input, error) {
+		var in stale.Input
+		configs, err := client.FeatureConfigurations(ctx, projectKey, key)
+		if err != nil {
+			return in, fmt.Errorf("failed to get configuration for feature '%s': %w", key, err)
+		}
+		variations, err := client.Variations(ctx, projectKey, key)
+		if err != nil {
+			return in, fmt.Errorf("failed to get variations for feature '%s': %w", key, err)
+		}
+		logs, err := client.FeatureAuditLogs(ctx, projectKey, key)
+		if err != nil {
+			return in, fmt.Errorf("failed to get audit logs for feature '%s': %w", key, err)
+		}
+		in.Configs = configs
+		in.Variations = variations
+		for _, l := range logs {
+			if l.CreatedAt.After(in.LastAudit) {
+				in.LastAudit = l.CreatedAt
+			}
+		}
+		return in, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var results []stale.Result
+	for _, f := range features {
+		in := inputs[f.Key]
+		in.Feature = f
+		in.Usages = featureUsages[f.ID]
+		in.UsagesScanned = len(staleUsagePaths) > 0
+
+		r := stale.Classify(in, thresholds, now)
+		if r.Class == stale.InUse && !staleAll {
+			continue
+		}
+		results = append(results, r)
+	}
+	if err := stale.Sort(results, staleSort); err != nil {
+		return err
+	}
+
+	printer := output.NewPrinter(output.ParseFormat(GetOutput()))
+	if output.ParseFormat(GetOutput()) != output.FormatTable {
+		if results == nil {
+			results = []stale.Result{}
+		}
+		return printer.Print(results)
+	}
+	if len(results) == 0 {
+		cmd.Println("No stale features found")
+		return nil
+	}
+	return printer.Print(staleTableData{results: results, usages: len(staleUsagePaths) > 0})
+}
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/135yshr/devcycle-cli/internal/output"
+	"github.com/135yshr/devcycle-cli/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+var featuresArchiveCmd = &cobra.Command{
+	Use:   "archive [feature-key]",
+	Short: "Archive a feature",
+	Long: `Archive a feature.
+
+Archiving a feature that is still active in a production environment is
+refused unless --force is given.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFeaturesArchive,
+}
+
+var featuresUnarchiveCmd = &cobra.Command{
+	Use:   "unarchive [feature-key]",
+	Short: "Restore an archived or complete feature",
+	Long:  `Restore an archived or complete feature to active.`,
+	Args:  cobra.ExactArgs(1),
+	RunE:  runFeaturesUnarchive,
+}
+
+var featuresCompleteCmd = &cobra.Command{
+	Use:   "complete [feature-key]",
+	Short: "Mark a feature complete",
+	Long: `Mark a feature complete. A complete feature serves its static variation,
+given with --variation, to every user in every environment.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFeaturesComplete,
+}
+
+var featureArchiveForce bool
+var featureStaticVariation string
+
+func init() {
+	featuresCmd.AddCommand(featuresArchiveCmd)
+	featuresCmd.AddCommand(featuresUnarchiveCmd)
+	featuresCmd.AddCommand(featuresCompleteCmd)
+
+	featuresArchiveCmd.Flags().BoolVarP(&featureArchiveForce, "force", "f", false, "archive even if the feature is active in production")
+	featuresCompleteCmd.Flags().StringVar(&featureStaticVariation, "variation", "", "variation to serve to everyone, by key or ID")
+}
+
+// productionEnvironments returns the keys of the project's production
+// environments.
+func productionEnvironments(ctx context.Context, client *api.Client, projectKey string) ([]string, error) {
+	envs, err := client.Environments(ctx, projectKey)
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	for _, env := range envs {
+		if env.Type == "production" {
+			keys = append(keys, env.Key)
+		}
+	}
+	return keys, nil
+}
+
+// activeEnvironments returns the environments among envKeys in which the
+// feature configured by configs is active.
+func activeEnvironments(configs map[string]*api.EnvironmentConfig, envKeys []string) []string {
+	var active []string
+	for _, envKey := range envKeys {
+		if cfg := configs[envKey]; cfg != nil && cfg.Status == "active" {
+			active = append(active, envKey)
+		}
+	}
+	return active
+}
+
+func runFeaturesArchive(cmd *cobra.Command, args []string) error {
+	projectKey := getProjectKey()
+	if projectKey == "" {
+		return errProjectRequired
+	}
+	featureKey := args[0]
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if !featureArchiveForce {
+		production, err := productionEnvironments(ctx, client, projectKey)
+		if err != nil {
+			return err
+		}
+		configs, err := client.FeatureConfigurations(ctx, projectKey, featureKey)
+		if err != nil {
+			return err
+		}
+		if active := activeEnvironments(configs, production); len(active) > 0 {
+			return fmt.Errorf("feature '%s' is active in production (%s); disable it first or use --force", featureKey, strings.Join(active, ", "))
+		}
+	}
+
+	feature, err := client.ArchiveFeature(ctx, projectKey, featureKey)
+	if err != nil {
+		return err
+	}
+	return printFeatureStatus(cmd, feature, "Feature '%s' archived\n")
+}
+
+func runFeaturesUnarchive(cmd *cobra.Command, args []string) error {
+	projectKey := getProjectKey()
+	if projectKey == "" {
+		return errProjectRequired
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	feature, err := client.UnarchiveFeature(ctx, projectKey, args[0])
+	if err != nil {
+		return err
+	}
+	return printFeatureStatus(cmd, feature, "Feature '%s' restored to active\n")
+}
+
+func runFeaturesComplete(cmd *cobra.Command, args []string) error {
+	projectKey := getProjectKey()
+	if projectKey == "" {
+		return errProjectRequired
+	}
+	featureKey := args[0]
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	static := featureStaticVariation
+	if static != "" {
+		variations, err := client.Variations(ctx, projectKey, featureKey)
+		if err != nil {
+			return err
+		}
+		static = ""
+		for _, v := range variations {
+			if v.Key == featureStaticVariation || v.ID == featureStaticVariation {
+				static = v.Key
+				break
+			}
+		}
+		if static == "" {
+			return fmt.Errorf("variation '%s' not found in feature '%s'", featureStaticVariation, featureKey)
+		}
+	}
+
+	feature, err := client.CompleteFeature(ctx, projectKey, featureKey, static)
+	if err != nil {
+		return err
+	}
+	return printFeatureStatus(cmd, feature, "Feature '%s' marked complete\n")
+}
+
+// printFeatureStatus prints feature as structured output, or message with
+// the feature key for table output.
+func printFeatureStatus(cmd *cobra.Command, feature *api.Feature, message string) error {
+	if output.ParseFormat(GetOutput()) == output.FormatTable {
+		cmd.Printf(message, feature.Key)
+		return nil
+	}
+	printer := output.NewPrinter(output.ParseFormat(GetOutput()))
+	return printer.Print(feature)
+}
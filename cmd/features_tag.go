@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/135yshr/devcycle-cli/internal/listfilter"
+	"github.com/135yshr/devcycle-cli/internal/tags"
+	"github.com/135yshr/devcycle-cli/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+var featuresTagCmd = &cobra.Command{
+	Use:   "tag",
+	Short: "Manage feature tags",
+	Long: `Add, remove or replace the tags of a feature.
+
+With a filter flag (--type, --status, --tag, --search or a date flag) or
+--all, every argument is a tag and the change applies to every matching
+feature. Bulk changes are listed and confirmed before they are made.
+
+Examples:
+  dvcx features tag add dark-mode ui web
+  dvcx features tag remove dark-mode web
+  dvcx features tag set dark-mode ui
+  dvcx features tag add --search checkout payments
+  dvcx features tag remove --status archived --dry-run experiment`,
+}
+
+var featuresTagAddCmd = &cobra.Command{
+	Use:   "add [feature-key] [tags...]",
+	Short: "Add tags to features",
+	Long:  `Add tags to a feature, or to every feature matching the filter flags.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runFeaturesTag(cmd, args, tags.Add, 1)
+	},
+}
+
+var featuresTagRemoveCmd = &cobra.Command{
+	Use:   "remove [feature-key] [tags...]",
+	Short: "Remove tags from features",
+	Long:  `Remove tags from a feature, or from every feature matching the filter flags.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runFeaturesTag(cmd, args, tags.Remove, 1)
+	},
+}
+
+var featuresTagSetCmd = &cobra.Command{
+	Use:   "set [feature-key] [tags...]",
+	Short: "Replace the tags of features",
+	Long: `Replace the tags of a feature, or of every feature matching the filter
+flags. Giving no tags removes every tag.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runFeaturesTag(cmd, args, func(_, t []string) []string { return tags.Normalize(t) }, 0)
+	},
+}
+
+var featureTagFilter listFilterFlags
+var featureTagAll bool
+var featureTagDryRun bool
+var featureTagForce bool
+
+func init() {
+	featuresCmd.AddCommand(featuresTagCmd)
+	featuresTagCmd.AddCommand(featuresTagAddCmd)
+	featuresTagCmd.AddCommand(featuresTagRemoveCmd)
+	featuresTagCmd.AddCommand(featuresTagSetCmd)
+
+	for _, c := range []*cobra.Command{featuresTagAddCmd, featuresTagRemoveCmd, featuresTagSetCmd} {
+		addListFilterFlags(c, &featureTagFilter, featureListFields)
+		c.Flags().BoolVar(&featureTagAll, "all", false, "change every feature in the project")
+		c.Flags().BoolVar(&featureTagDryRun, "dry-run", false, "show the changes without making them")
+		c.Flags().BoolVarP(&featureTagForce, "force", "f", false, "skip confirmation prompt")
+	}
+}
+
+// featureTagChange is a change to the tags of one feature.
+type featureTagChange struct {
+	feature string
+	before  []string
+	after   []string
+}
+
+// runFeaturesTag applies edit to the tags of the selected features. minTags
+// is the number of tags the command needs.
+func runFeaturesTag(cmd *cobra.Command, args []string, edit func(current, tags []string) []string, minTags int) error {
+	projectKey := getProjectKey()
+	if projectKey == "" {
+		return errProjectRequired
+	}
+	bulk := featureTagAll || featureTagFilter.hasFilter()
+	if featureTagAll && featureTagFilter.hasFilter() {
+		return fmt.Errorf("--all cannot be combined with filter flags")
+	}
+
+	var featureKey string
+	tagArgs := args
+	if !bulk {
+		if len(args) == 0 {
+			return fmt.Errorf("a feature key, filter flags or --all is required")
+		}
+		featureKey, tagArgs = args[0], args[1:]
+	}
+	if len(tags.Normalize(tagArgs)) < minTags {
+		return fmt.Errorf("at least one tag is required")
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	var features []api.Feature
+	if bulk {
+		filter, err := featureTagFilter.options()
+		if err != nil {
+			return err
+		}
+		features, err = client.FeaturesWithOptions(ctx, projectKey, featureListOptions(filter))
+		if err != nil {
+			return err
+		}
+		if features, err = listfilter.Apply(features, featureListFields, filter); err != nil {
+			return err
+		}
+	} else {
+		feature, err := client.Feature(ctx, projectKey, featureKey)
+		if err != nil {
+			return err
+		}
+		features = []api.Feature{*feature}
+	}
+
+	var changes []featureTagChange
+	for _, f := range features {
+		after := edit(f.Tags, tagArgs)
+		if !tags.Equal(f.Tags, after) {
+			changes = append(changes, featureTagChange{feature: f.Key, before: f.Tags, after: after})
+		}
+	}
+	if len(changes) == 0 {
+		cmd.Println("No tag changes needed")
+		return nil
+	}
+
+	for _, c := range changes {
+		cmd.Printf("  %s: %s -> %s\n", c.feature, formatTags(c.before), formatTags(c.after))
+	}
+	if featureTagDryRun {
+		cmd.Println("Dry-run complete. No changes were made.")
+		return nil
+	}
+	if bulk && !confirmAction(fmt.Sprintf("Update the tags of %d features?", len(changes)), featureTagForce) {
+		cmd.Println("Tag update cancelled")
+		return nil
+	}
+
+	// The confirmation may have outlasted ctx, so each write gets its own
+	for _, c := range changes {
+		_, err := withTimeout(context.Background(), func(ctx context.Context) (*api.Feature, error) {
+			return client.UpdateFeatureTags(ctx, projectKey, c.feature, c.after)
+		})
+		if err != nil {
+			return fmt.Errorf("feature '%s': %w", c.feature, err)
+		}
+	}
+	if bulk {
+		cmd.Printf("Tags of %d features updated successfully\n", len(changes))
+	} else {
+		cmd.Printf("Tags of feature '%s' updated successfully\n", featureKey)
+	}
+	return nil
+}
+
+// formatTags joins tags for display, showing "-" when there are none.
+func formatTags(t []string) string {
+	if len(t) == 0 {
+		return "-"
+	}
+	return strings.Join(t, ", ")
+}
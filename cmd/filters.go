@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/135yshr/devcycle-cli/internal/filterexpr"
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+// parseWhere compiles a filter expression. Syntax errors are reported with
+// the expression and a caret under the offending position.
+func parseWhere(expr string) (api.Filters, error) {
+	filters, err := filterexpr.Parse(expr)
+	var syntaxErr *filterexpr.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		highlight := strings.ReplaceAll(syntaxErr.Highlight(expr), "\n", "\n  ")
+		return api.Filters{}, fmt.Errorf("invalid --where expression: %w\n  %s", err, highlight)
+	}
+	return filters, err
+}
+
+// combineWhere compiles several filter expressions and joins them with
+// match. Each expression that is itself a group of several conditions
+// becomes a nested group.
+func combineWhere(exprs []string, match string) (api.Filters, error) {
+	match = strings.ToLower(match)
+	if match != "and" && match != "or" {
+		return api.Filters{}, fmt.Errorf("invalid --match value %q (must be and or or)", match)
+	}
+	if len(exprs) == 0 {
+		return api.Filters{Operator: "and", Filters: []api.Filter{{Type: "all"}}}, nil
+	}
+	if len(exprs) == 1 {
+		return parseWhere(exprs[0])
+	}
+
+	combined := api.Filters{Operator: match}
+	for _, expr := range exprs {
+		filters, err := parseWhere(expr)
+		if err != nil {
+			return api.Filters{}, err
+		}
+		if len(filters.Filters) == 1 || filters.Operator == match {
+			combined.Filters = append(combined.Filters, filters.Filters...)
+			continue
+		}
+		combined.Filters = append(combined.Filters, api.Filter{Operator: filters.Operator, Filters: filters.Filters})
+	}
+	return combined, nil
+}
+
+// hasAudienceRefs reports whether filters contain an audience(...) match.
+func hasAudienceRefs(filters *api.Filters) bool {
+	found := false
+	filterexpr.Walk(filters, func(f *api.Filter) {
+		if f.Type == "audienceMatch" {
+			found = true
+		}
+	})
+	return found
+}
+
+// resolveAudienceRefs replaces audience keys in audience(...) matches with
+// audience IDs, which is what the API expects. Values that are already IDs
+// are left unchanged.
+func resolveAudienceRefs(ctx context.Context, client *api.Client, projectKey string, filters *api.Filters) error {
+	if !hasAudienceRefs(filters) {
+		return nil
+	}
+
+	audiences, err := client.Audiences(ctx, projectKey)
+	if err != nil {
+		return err
+	}
+	ids := make(map[string]string, len(audiences)*2)
+	for _, a := range audiences {
+		ids[a.Key] = a.ID
+		ids[a.ID] = a.ID
+	}
+
+	var unknown []string
+	filterexpr.Walk(filters, func(f *api.Filter) {
+		if f.Type != "audienceMatch" {
+			return
+		}
+		for i, ref := range f.Audiences {
+			id, ok := ids[ref]
+			if !ok {
+				unknown = append(unknown, ref)
+				continue
+			}
+			f.Audiences[i] = id
+		}
+	})
+	if len(unknown) > 0 {
+		return fmt.Errorf("audience not found: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// describeFilters renders filters in expression syntax for table output.
+func describeFilters(filters api.Filters) string {
+	if len(filters.Filters) == 0 {
+		return "-"
+	}
+	return filterexpr.Format(filters)
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/135yshr/devcycle-cli/internal/codegen"
+	"github.com/spf13/cobra"
+)
+
+var generateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate typed accessors for variables",
+	Long: `Generate typed constants and accessors for the project's variables.
+
+For Go (--lang go) a variables.go file is written with a <Name>Key constant
+and a typed <Name> function for each variable. For TypeScript (--lang ts) a
+variables.ts file is written with a VariableKeys map, a VariableTypes
+interface and a typed function for each variable. Descriptions become doc
+comments and archived variables are marked deprecated.
+
+With --check nothing is written; the command fails when the generated file
+is missing or out of date, which is useful in CI.`,
+	RunE: runGenerate,
+}
+
+var generateProject string
+var generateLang string
+var generateOutDir string
+var generatePackage string
+var generateCheck bool
+
+func init() {
+	rootCmd.AddCommand(generateCmd)
+
+	generateCmd.Flags().StringVarP(&generateProject, "project", "p", "", "project key (uses config default if not specified)")
+	generateCmd.Flags().StringVar(&generateLang, "lang", "", "language to generate: go or ts (required)")
+	generateCmd.Flags().StringVar(&generateOutDir, "out-dir", ".", "directory to write the generated file to")
+	generateCmd.Flags().StringVar(&generatePackage, "package", "", "Go package name (default: derived from --out-dir)")
+	generateCmd.Flags().BoolVar(&generateCheck, "check", false, "fail if the generated file is out of date instead of writing it")
+}
+
+func runGenerate(cmd *cobra.Command, args []string) error {
+	projectKey := generateProject
+	if projectKey == "" {
+		projectKey = getProjectKey()
+	}
+	if projectKey == "" {
+		return errProjectRequired
+	}
+	lang := strings.ToLower(generateLang)
+	switch lang {
+	case "go", "ts", "typescript":
+	case "":
+		return fmt.Errorf("required flag \"lang\" not set")
+	default:
+		return fmt.Errorf("unsupported language %q (must be go or ts)", generateLang)
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	variables, err := client.Variables(ctx, projectKey)
+	if err != nil {
+		return err
+	}
+	features, err := client.Features(ctx, projectKey)
+	if err != nil {
+		return err
+	}
+	vars := codegen.FromAPI(variables, features)
+
+	var file codegen.File
+	if lang == "go" {
+		pkg := generatePackage
+		if pkg == "" {
+			pkg = goPackageName(generateOutDir)
+		}
+		content, err := codegen.Go(pkg, vars)
+		if err != nil {
+			return err
+		}
+		file = codegen.File{Path: filepath.Join(generateOutDir, "variables.go"), Content: content}
+	} else {
+		content, err := codegen.TypeScript(vars)
+		if err != nil {
+			return err
+		}
+		file = codegen.File{Path: filepath.Join(generateOutDir, "variables.ts"), Content: content}
+	}
+
+	if generateCheck {
+		stale, err := codegen.Stale([]codegen.File{file})
+		if err != nil {
+			return err
+		}
+		if len(stale) > 0 {
+			return fmt.Errorf("%s is out of date; run 'dvcx generate --lang %s --out-dir %s' to update it", strings.Join(stale, ", "), generateLang, generateOutDir)
+		}
+		cmd.Printf("%s is up to date\n", file.Path)
+		return nil
+	}
+
+	if err := codegen.Write([]codegen.File{file}); err != nil {
+		return err
+	}
+	cmd.Printf("Generated %s (%d variables)\n", file.Path, len(vars))
+	return nil
+}
+
+// goPackageName derives a Go package name from the last element of dir.
+func goPackageName(dir string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		abs = dir
+	}
+	var b strings.Builder
+	for _, r := range strings.ToLower(filepath.Base(abs)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			b.WriteRune(r)
+		}
+	}
+	name := b.String()
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		return "flags"
+	}
+	return name
+}
@@ -1,10 +1,17 @@
 package cmd
 
 import (
+	"context"
+	"os"
+	"time"
+
 	"github.com/135yshr/devcycle-cli/internal/config"
 	"github.com/135yshr/devcycle-cli/pkg/api"
 )
 
+// fetchWorkers is the number of features whose details are fetched at a time.
+const fetchWorkers = 8
+
 func loadToken() (*api.Token, error) {
 	tokenPath, err := config.TokenFilePath()
 	if err != nil {
@@ -12,3 +19,36 @@ func loadToken() (*api.Token, error) {
 	}
 	return api.LoadToken(tokenPath)
 }
+
+// isTerminal reports whether f is attached to an interactive terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// useColor reports whether colored output should be written to stdout.
+func useColor() bool {
+	if noColor || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return isTerminal(os.Stdout)
+}
+
+// withTimeout calls fn with a context that expires after the default API timeout.
+func withTimeout[T any](ctx context.Context, fn func(context.Context) (T, error)) (T, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	return fn(ctx)
+}
+
+// featureKeys returns the keys of features, in order.
+func featureKeys(features []api.Feature) []string {
+	keys := make([]string, len(features))
+	for i, f := range features {
+		keys[i] = f.Key
+	}
+	return keys
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/135yshr/devcycle-cli/internal/listfilter"
+	"github.com/135yshr/devcycle-cli/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+// listFilterFlags holds the filtering and sorting flags of a list command.
+type listFilterFlags struct {
+	Type          string
+	Status        string
+	Tags          []string
+	Search        string
+	CreatedAfter  string
+	CreatedBefore string
+	UpdatedAfter  string
+	UpdatedBefore string
+	Sort          string
+	Reverse       bool
+}
+
+// addListFilterFlags registers the filtering flags on cmd. Flags for fields
+// the resource does not have are left out.
+func addListFilterFlags[T any](cmd *cobra.Command, f *listFilterFlags, fields listfilter.Fields[T]) {
+	flags := cmd.Flags()
+	if fields.Type != nil {
+		flags.StringVar(&f.Type, "type", "", "only include items of this type")
+	}
+	if fields.Status != nil {
+		flags.StringVar(&f.Status, "status", "", "only include items with this status")
+	}
+	if fields.Tags != nil {
+		flags.StringSliceVar(&f.Tags, "tag", nil, "only include items with this tag (can be repeated; all must match)")
+	}
+	flags.StringVar(&f.Search, "search", "", "only include items whose key, name or description contains this text, or matches /regexp/")
+	if fields.CreatedAt != nil {
+		flags.StringVar(&f.CreatedAfter, "created-after", "", "only include items created after this date (YYYY-MM-DD, RFC 3339 or 30d)")
+		flags.StringVar(&f.CreatedBefore, "created-before", "", "only include items created before this date")
+	}
+	if fields.UpdatedAt != nil {
+		flags.StringVar(&f.UpdatedAfter, "updated-after", "", "only include items updated after this date")
+		flags.StringVar(&f.UpdatedBefore, "updated-before", "", "only include items updated before this date")
+	}
+}
+
+// addListSortFlags registers the sorting flags on cmd.
+func addListSortFlags(cmd *cobra.Command, f *listFilterFlags) {
+	flags := cmd.Flags()
+	flags.StringVar(&f.Sort, "sort", "", "sort by key, name, created or updated")
+	flags.BoolVar(&f.Reverse, "reverse", false, "reverse the sort order")
+}
+
+// hasFilter reports whether any filtering flag is set.
+func (f *listFilterFlags) hasFilter() bool {
+	return f.Type != "" || f.Status != "" || len(f.Tags) > 0 || f.Search != "" ||
+		f.CreatedAfter != "" || f.CreatedBefore != "" || f.UpdatedAfter != "" || f.UpdatedBefore != ""
+}
+
+// options converts the flags to filter options, parsing the dates.
+func (f *listFilterFlags) options() (listfilter.Options, error) {
+	opts := listfilter.Options{
+		Type:    f.Type,
+		Status:  f.Status,
+		Tags:    f.Tags,
+		Search:  f.Search,
+		Sort:    f.Sort,
+		Reverse: f.Reverse,
+	}
+	now := time.Now()
+	for _, d := range []struct {
+		value string
+		dst   *time.Time
+	}{
+		{f.CreatedAfter, &opts.CreatedAfter},
+		{f.CreatedBefore, &opts.CreatedBefore},
+		{f.UpdatedAfter, &opts.UpdatedAfter},
+		{f.UpdatedBefore, &opts.UpdatedBefore},
+	} {
+		if d.value == "" {
+			continue
+		}
+		t, err := listfilter.ParseTime(d.value, now)
+		if err != nil {
+			return listfilter.Options{}, err
+		}
+		*d.dst = t
+	}
+	return opts, nil
+}
+
+var featureListFields = listfilter.Fields[api.Feature]{
+	Key:         func(f api.Feature) string { return f.Key },
+	Name:        func(f api.Feature) string { return f.Name },
+	Description: func(f api.Feature) string { return f.Description },
+	Type:        func(f api.Feature) string { return f.Type },
+	Status:      func(f api.Feature) string { return f.Status },
+	Tags:        func(f api.Feature) []string { return f.Tags },
+	CreatedAt:   func(f api.Feature) time.Time { return f.CreatedAt },
+	UpdatedAt:   func(f api.Feature) time.Time { return f.UpdatedAt },
+}
+
+var variableListFields = listfilter.Fields[api.Variable]{
+	Key:         func(v api.Variable) string { return v.Key },
+	Name:        func(v api.Variable) string { return v.Name },
+	Description: func(v api.Variable) string { return v.Description },
+	Type:        func(v api.Variable) string { return v.Type },
+	Status:      func(v api.Variable) string { return v.Status },
+	CreatedAt:   func(v api.Variable) time.Time { return v.CreatedAt },
+	UpdatedAt:   func(v api.Variable) time.Time { return v.UpdatedAt },
+}
+
+var audienceListFields = listfilter.Fields[api.AudienceDefinition]{
+	Key:         func(a api.AudienceDefinition) string { return a.Key },
+	Name:        func(a api.AudienceDefinition) string { return a.Name },
+	Description: func(a api.AudienceDefinition) string { return a.Description },
+	CreatedAt:   func(a api.AudienceDefinition) time.Time { return a.CreatedAt },
+	UpdatedAt:   func(a api.AudienceDefinition) time.Time { return a.UpdatedAt },
+}
@@ -143,11 +143,14 @@ func getClient() (*api.Client, error) {
 	return api.NewClient(api.WithToken(token.AccessToken)), nil
 }
 
+// truncate shortens s to at most maxLen characters, ending it with "..." when
+// it is cut. It counts runes so that multi-byte characters are never split.
 func truncate(s string, maxLen int) string {
-	if len(s) <= maxLen {
+	r := []rune(s)
+	if len(r) <= maxLen {
 		return s
 	}
-	return s[:maxLen-3] + "..."
+	return string(r[:maxLen-3]) + "..."
 }
 
 func runProjectsCreate(cmd *cobra.Command, args []string) error {
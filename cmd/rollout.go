@@ -0,0 +1,316 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/135yshr/devcycle-cli/internal/config"
+	"github.com/135yshr/devcycle-cli/internal/rollout"
+	"github.com/135yshr/devcycle-cli/internal/targeting"
+	"github.com/135yshr/devcycle-cli/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+// rolloutStateDir is the directory under the config directory where rollout progress is stored
+const rolloutStateDir = "rollouts"
+
+var rolloutCmd = &cobra.Command{
+	Use:   "rollout",
+	Short: "Progressively roll out a variation",
+	Long: `Progressively increase the percentage of users served a variation.
+
+At each step the distribution of the selected target is rewritten so the
+variation receives the step's percentage, then the command waits for the
+interval before the next step. The remaining traffic goes to --fallback, or
+is shared among the other variations currently served.
+
+Progress is saved under .devcycle/rollouts so an interrupted rollout resumes
+from the next step when the same command is run again. When --guard-metric
+is set, the metric's results are checked before every step after the first
+and the rollout is aborted if the variation regresses against the baseline
+by more than --max-regression.`,
+	RunE: runRollout,
+}
+
+var rolloutProject string
+var rolloutFeature string
+var rolloutEnvironment string
+var rolloutTarget string
+var rolloutVariation string
+var rolloutFallback string
+var rolloutSteps string
+var rolloutInterval time.Duration
+var rolloutGuardMetric string
+var rolloutGuardBaseline string
+var rolloutMaxRegression float64
+var rolloutReset bool
+var rolloutDryRun bool
+
+func init() {
+	rootCmd.AddCommand(rolloutCmd)
+
+	rolloutCmd.Flags().StringVarP(&rolloutProject, "project", "p", "", "project key (uses config default if not specified)")
+	rolloutCmd.Flags().StringVarP(&rolloutFeature, "feature", "f", "", "feature key (required)")
+	rolloutCmd.Flags().StringVarP(&rolloutEnvironment, "environment", "e", "", "environment key (uses config default if not specified)")
+	rolloutCmd.Flags().StringVar(&rolloutTarget, "target", "", "name of the target to roll out (required when the environment has several targets)")
+	rolloutCmd.Flags().StringVar(&rolloutVariation, "variation", "", "variation key to roll out (required)")
+	rolloutCmd.Flags().StringVar(&rolloutFallback, "fallback", "", "variation key that receives the remaining traffic")
+	rolloutCmd.Flags().StringVar(&rolloutSteps, "steps", "1,5,25,50,100", "comma-separated rollout percentages")
+	rolloutCmd.Flags().DurationVar(&rolloutInterval, "interval", 30*time.Minute, "time to wait between steps")
+	rolloutCmd.Flags().StringVar(&rolloutGuardMetric, "guard-metric", "", "metric key checked before each step")
+	rolloutCmd.Flags().StringVar(&rolloutGuardBaseline, "guard-baseline", "", "variation key the guard metric is compared against (defaults to --fallback)")
+	rolloutCmd.Flags().Float64Var(&rolloutMaxRegression, "max-regression", 0.05, "maximum allowed relative regression of the guard metric (0.05 = 5%)")
+	rolloutCmd.Flags().BoolVar(&rolloutReset, "reset", false, "discard saved progress and start from the first step")
+	rolloutCmd.Flags().BoolVar(&rolloutDryRun, "dry-run", false, "show the rollout plan without applying changes")
+}
+
+func runRollout(cmd *cobra.Command, args []string) error {
+	projectKey := rolloutProject
+	if projectKey == "" {
+		projectKey = getProjectKey()
+	}
+	if projectKey == "" {
+		return errProjectRequired
+	}
+	envKey := rolloutEnvironment
+	if envKey == "" {
+		envKey = config.Environment()
+	}
+	if envKey == "" {
+		return errEnvironmentRequired
+	}
+	if rolloutFeature == "" {
+		return fmt.Errorf("required flag \"feature\" not set")
+	}
+	if rolloutVariation == "" {
+		return fmt.Errorf("required flag \"variation\" not set")
+	}
+	steps, err := rollout.ParseSteps(rolloutSteps)
+	if err != nil {
+		return err
+	}
+	baseline := rolloutGuardBaseline
+	if baseline == "" {
+		baseline = rolloutFallback
+	}
+	if rolloutGuardMetric != "" && baseline == "" {
+		return fmt.Errorf("--guard-baseline or --fallback is required when --guard-metric is set")
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	configDir, err := config.ConfigDirPath()
+	if err != nil {
+		return err
+	}
+	statePath := rollout.StatePath(filepath.Join(configDir, rolloutStateDir), projectKey, rolloutFeature, envKey)
+
+	plan := &rollout.State{
+		Project:     projectKey,
+		Feature:     rolloutFeature,
+		Environment: envKey,
+		Target:      rolloutTarget,
+		Variation:   rolloutVariation,
+		Fallback:    rolloutFallback,
+		Steps:       steps,
+		Status:      rollout.StatusInProgress,
+	}
+
+	state, err := rollout.LoadState(statePath)
+	if err != nil {
+		return err
+	}
+	switch {
+	case state == nil || rolloutReset:
+		state = plan
+	case !state.Matches(plan):
+		return fmt.Errorf("a different rollout is saved for feature '%s' in '%s' (%s); use --reset to start over", rolloutFeature, envKey, statePath)
+	case state.Status == rollout.StatusCompleted:
+		cmd.Printf("Rollout of '%s' in '%s' is already complete\n", rolloutVariation, envKey)
+		return nil
+	case state.Status == rollout.StatusAborted:
+		return fmt.Errorf("rollout was aborted: %s; use --reset to start over", state.Reason)
+	default:
+		cmd.Printf("Resuming rollout at step %d/%d\n", state.Completed+1, len(steps))
+	}
+
+	cmd.Printf("Rollout plan for '%s' in '%s': %s every %s\n", rolloutFeature, envKey, formatSteps(steps), rolloutInterval)
+	if rolloutDryRun {
+		for i := state.Completed; i < len(steps); i++ {
+			cmd.Printf("  step %d/%d: %s at %s\n", i+1, len(steps), rolloutVariation, formatPercent(steps[i]))
+		}
+		cmd.Println("Dry-run complete. No changes were made.")
+		return nil
+	}
+
+	var optimizeFor string
+	if rolloutGuardMetric != "" {
+		metric, err := withTimeout(ctx, func(ctx context.Context) (*api.Metric, error) {
+			return client.Metric(ctx, projectKey, rolloutGuardMetric)
+		})
+		if err != nil {
+			return err
+		}
+		optimizeFor = metric.OptimizeFor
+	}
+
+	for i := state.Completed; i < len(steps); i++ {
+		if wait := state.NextWait(rolloutInterval, time.Now()); wait > 0 {
+			cmd.Printf("Waiting %s before step %d/%d...\n", wait.Round(time.Second), i+1, len(steps))
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				cmd.Printf("Rollout paused after step %d/%d; run the same command to resume\n", state.Completed, len(steps))
+				return nil
+			}
+		}
+
+		if rolloutGuardMetric != "" && i > 0 {
+			if err := checkRolloutGuard(ctx, cmd, client, projectKey, envKey, baseline, optimizeFor); err != nil {
+				var regErr *rollout.RegressionError
+				if errors.As(err, &regErr) {
+					state.Status = rollout.StatusAborted
+					state.Reason = regErr.Error()
+					state.UpdatedAt = time.Now()
+					if saveErr := state.Save(statePath); saveErr != nil {
+						return saveErr
+					}
+				}
+				return err
+			}
+		}
+
+		if err := applyRolloutStep(ctx, client, projectKey, envKey, steps[i]); err != nil {
+			return err
+		}
+
+		state.Completed = i + 1
+		state.UpdatedAt = time.Now()
+		if state.Completed == len(steps) {
+			state.Status = rollout.StatusCompleted
+		}
+		if err := state.Save(statePath); err != nil {
+			return err
+		}
+		cmd.Printf("Step %d/%d: '%s' now served to %s of '%s' in '%s'\n", i+1, len(steps), rolloutVariation, formatPercent(steps[i]), rolloutFeature, envKey)
+	}
+
+	cmd.Println("Rollout complete")
+	return nil
+}
+
+func applyRolloutStep(ctx context.Context, client *api.Client, projectKey, envKey string, pct float64) error {
+	configs, err := withTimeout(ctx, func(ctx context.Context) (map[string]*api.EnvironmentConfig, error) {
+		return client.FeatureConfigurations(ctx, projectKey, rolloutFeature)
+	})
+	if err != nil {
+		return err
+	}
+	cfg := configs[envKey]
+	if cfg == nil {
+		return fmt.Errorf("environment '%s' not found for feature '%s'", envKey, rolloutFeature)
+	}
+
+	idx, err := selectRolloutTarget(cfg.Targets)
+	if err != nil {
+		return err
+	}
+
+	variations, err := withTimeout(ctx, func(ctx context.Context) ([]api.Variation, error) {
+		return client.Variations(ctx, projectKey, rolloutFeature)
+	})
+	if err != nil {
+		return err
+	}
+	current := cfg.Targets[idx].Distribution
+	variation, err := targeting.ResolveVariation(variations, current, rolloutVariation)
+	if err != nil {
+		return err
+	}
+	fallback := ""
+	if rolloutFallback != "" {
+		fallback, err = targeting.ResolveVariation(variations, current, rolloutFallback)
+		if err != nil {
+			return err
+		}
+	}
+
+	dist, err := rollout.Distribute(current, variation, fallback, pct)
+	if err != nil {
+		return err
+	}
+	cfg.Targets[idx].Distribution = dist
+
+	req := &api.UpdateFeatureConfigurationsRequest{
+		Configurations: map[string]*api.EnvironmentConfig{envKey: cfg},
+	}
+	_, err = withTimeout(ctx, func(ctx context.Context) (map[string]*api.EnvironmentConfig, error) {
+		return client.UpdateFeatureConfigurations(ctx, projectKey, rolloutFeature, req)
+	})
+	return err
+}
+
+func selectRolloutTarget(targets []api.Target) (int, error) {
+	if rolloutTarget != "" {
+		idx := targeting.FindTarget(targets, rolloutTarget)
+		if idx < 0 {
+			return -1, fmt.Errorf("target '%s' not found (available: %s)", rolloutTarget, strings.Join(targeting.TargetNames(targets), ", "))
+		}
+		return idx, nil
+	}
+	switch len(targets) {
+	case 0:
+		return -1, fmt.Errorf("environment has no targets to roll out")
+	case 1:
+		return 0, nil
+	default:
+		return -1, fmt.Errorf("environment has several targets; use --target to select one (available: %s)", strings.Join(targeting.TargetNames(targets), ", "))
+	}
+}
+
+func checkRolloutGuard(ctx context.Context, cmd *cobra.Command, client *api.Client, projectKey, envKey, baseline, optimizeFor string) error {
+	results, err := withTimeout(ctx, func(ctx context.Context) (*api.MetricResults, error) {
+		return client.MetricResults(ctx, projectKey, rolloutGuardMetric, &api.MetricResultsOptions{
+			Environment: envKey,
+			Feature:     rolloutFeature,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	ok, err := rollout.CheckRegression(rolloutGuardMetric, results, rolloutVariation, baseline, optimizeFor, rolloutMaxRegression)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		cmd.Printf("Guard metric '%s' has no data for '%s' and '%s' yet; continuing\n", rolloutGuardMetric, rolloutVariation, baseline)
+	}
+	return nil
+}
+
+func formatSteps(steps []float64) string {
+	parts := make([]string, len(steps))
+	for i, s := range steps {
+		parts[i] = formatPercent(s)
+	}
+	return strings.Join(parts, " -> ")
+}
+
+func formatPercent(p float64) string {
+	return fmt.Sprintf("%g%%", math.Round(p*10000)/100)
+}
@@ -12,6 +12,7 @@ import (
 var (
 	cfgFile      string
 	outputFormat string
+	noColor      bool
 )
 
 var rootCmd = &cobra.Command{
@@ -39,6 +40,7 @@ func init() {
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is .devcycle/config.yaml)")
 	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "table", "output format (table, json, yaml)")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "disable colored output")
 
 	viper.BindPFlag("output", rootCmd.PersistentFlags().Lookup("output"))
 }
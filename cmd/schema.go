@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/135yshr/devcycle-cli/internal/inputs"
+	"github.com/135yshr/devcycle-cli/internal/jsondiff"
+	"github.com/135yshr/devcycle-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema [kind]",
+	Short: "Print the JSON Schema of an input file",
+	Long: `Print the JSON Schema of the files accepted by --from-file.
+
+Without a kind, the kinds are listed. Point an editor at a schema to get
+completion and checks while writing a file, and use 'dvcx validate' to run
+the same checks, and more, from the command line.
+
+Examples:
+  dvcx schema
+  dvcx schema feature > .devcycle/feature.schema.json
+  dvcx schema targeting -o yaml`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSchema,
+}
+
+func init() {
+	rootCmd.AddCommand(schemaCmd)
+}
+
+type schemaKindsTableData struct {
+	kinds []inputs.Kind
+}
+
+func (d schemaKindsTableData) Headers() []string {
+	return []string{"KIND", "DESCRIPTION"}
+}
+
+func (d schemaKindsTableData) Rows() [][]string {
+	rows := make([][]string, len(d.kinds))
+	for i, k := range d.kinds {
+		rows[i] = []string{k.Name, k.Description}
+	}
+	return rows
+}
+
+func schemaKindNames() []string {
+	var names []string
+	for _, k := range inputs.Kinds() {
+		names = append(names, k.Name)
+	}
+	return names
+}
+
+// lookupKind returns the kind called name, or an error listing the kinds.
+func lookupKind(name string) (inputs.Kind, error) {
+	k, ok := inputs.Lookup(name)
+	if !ok {
+		return inputs.Kind{}, fmt.Errorf("unknown kind %q (must be one of: %s)", name, strings.Join(schemaKindNames(), ", "))
+	}
+	return k, nil
+}
+
+func runSchema(cmd *cobra.Command, args []string) error {
+	format := output.ParseFormat(GetOutput())
+	printer := output.NewPrinter(format)
+
+	if len(args) == 0 {
+		if format == output.FormatTable {
+			return printer.Print(schemaKindsTableData{kinds: inputs.Kinds()})
+		}
+		return printer.Print(inputs.Kinds())
+	}
+
+	k, err := lookupKind(args[0])
+	if err != nil {
+		return err
+	}
+	schema := k.Schema()
+	if format == output.FormatYAML {
+		normalized, err := jsondiff.Normalize(schema)
+		if err != nil {
+			return err
+		}
+		return printer.Print(normalized)
+	}
+
+	enc := json.NewEncoder(cmd.OutOrStdout())
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(schema)
+}
@@ -0,0 +1,240 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/135yshr/devcycle-cli/internal/listfilter"
+	"github.com/135yshr/devcycle-cli/internal/output"
+	"github.com/135yshr/devcycle-cli/internal/status"
+	"github.com/135yshr/devcycle-cli/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show which features are on in which environments",
+	Long: `Show a matrix of the project's features by environments.
+
+Each cell shows whether the feature is on or off in the environment and how
+many targeting rules it has there. "partial" marks features that split the
+users of a rule between variations, such as a percentage rollout or an
+experiment. "-" means the feature is not configured in the environment.
+
+Features can be filtered with the same flags as 'features list', columns
+with --environment, and rows by state with --only:
+
+  active    on in at least one environment
+  inactive  off in every environment
+  partial   partially rolled out in at least one environment
+  mixed     on in some environments and off in others
+
+With -o csv, each feature and environment is written as one record. With
+--watch, the matrix is fetched and shown again every interval until
+interrupted.
+
+Examples:
+  dvcx status
+  dvcx status -p my-app -e staging -e production --only mixed
+  dvcx status --tag checkout -o csv > status.csv
+  dvcx status --watch 30s`,
+	RunE: runStatus,
+}
+
+var statusProject string
+var statusEnvironments []string
+var statusOnly string
+var statusWatch time.Duration
+var statusListFilter listFilterFlags
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+
+	statusCmd.Flags().StringVarP(&statusProject, "project", "p", "", "project key (uses config default if not specified)")
+	statusCmd.Flags().StringSliceVarP(&statusEnvironments, "environment", "e", nil, "only show these environments, in this order (can be repeated)")
+	statusCmd.Flags().StringVar(&statusOnly, "only", "", "only show features that are active, inactive, partial or mixed")
+	statusCmd.Flags().DurationVar(&statusWatch, "watch", 0, "refresh the matrix at this interval until interrupted")
+	addListFilterFlags(statusCmd, &statusListFilter, featureListFields)
+	addListSortFlags(statusCmd, &statusListFilter)
+}
+
+type statusTableData struct {
+	matrix *status.Matrix
+}
+
+func (d statusTableData) Headers() []string {
+	return append([]string{"FEATURE"}, d.matrix.Environments...)
+}
+
+func (d statusTableData) Rows() [][]string {
+	rows := make([][]string, len(d.matrix.Features))
+	for i, f := range d.matrix.Features {
+		row := []string{f.Key}
+		for _, env := range d.matrix.Environments {
+			row = append(row, formatStatusCell(f.Environments[env]))
+		}
+		rows[i] = row
+	}
+	return rows
+}
+
+// formatStatusCell renders a cell as "on (2, partial)", "off" or "-".
+func formatStatusCell(c status.Cell) string {
+	if c.Status == "" {
+		return "-"
+	}
+	s := "off"
+	if c.Active() {
+		s = "on"
+	}
+	switch {
+	case c.Partial:
+		s += fmt.Sprintf(" (%d, partial)", c.Targets)
+	case c.Targets > 0:
+		s += fmt.Sprintf(" (%d)", c.Targets)
+	}
+	return s
+}
+
+// statusCSVData has one record per feature and environment.
+type statusCSVData struct {
+	matrix *status.Matrix
+}
+
+func (d statusCSVData) Headers() []string {
+	return []string{"feature", "name", "type", "environment", "status", "targets", "partial"}
+}
+
+func (d statusCSVData) Rows() [][]string {
+	var rows [][]string
+	for _, f := range d.matrix.Features {
+		for _, env := range d.matrix.Environments {
+			c := f.Environments[env]
+			rows = append(rows, []string{f.Key, f.Name, f.Type, env, c.Status, strconv.Itoa(c.Targets), strconv.FormatBool(c.Partial)})
+		}
+	}
+	return rows
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	projectKey := statusProject
+	if projectKey == "" {
+		projectKey = getProjectKey()
+	}
+	if projectKey == "" {
+		return errProjectRequired
+	}
+	if statusWatch < 0 {
+		return errors.New("--watch must not be negative")
+	}
+	if statusOnly != "" {
+		if err := status.CheckFilter(statusOnly); err != nil {
+			return err
+		}
+	}
+	switch statusListFilter.Status {
+	case "", api.FeatureStatusActive, api.FeatureStatusComplete, api.FeatureStatusArchived:
+	default:
+		return fmt.Errorf("invalid status %q (must be active, complete or archived)", statusListFilter.Status)
+	}
+	filter, err := statusListFilter.options()
+	if err != nil {
+		return err
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	if statusWatch == 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+		matrix, err := fetchStatus(ctx, client, projectKey, filter)
+		if err != nil {
+			return err
+		}
+		return printStatus(matrix)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	redraw := output.ParseFormat(GetOutput()) == output.FormatTable && isTerminal(os.Stdout)
+	for {
+		// Each refresh has its own deadline, so a request that hangs does
+		// not stop the ones after it
+		fetchCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+		matrix, err := fetchStatus(fetchCtx, client, projectKey, filter)
+		cancel()
+		if ctx.Err() != nil {
+			return nil
+		}
+		if redraw {
+			fmt.Print("\x1b[H\x1b[2J")
+			fmt.Printf("Every %s: dvcx status -p %s    %s\n\n", statusWatch, projectKey, time.Now().Format("15:04:05"))
+		}
+		if err != nil {
+			cmd.PrintErrf("Error: %v\n", err)
+		} else if err := printStatus(matrix); err != nil {
+			return err
+		}
+
+		select {
+		case <-time.After(statusWatch):
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// fetchStatus fetches the features that match filter and their
+// configurations, and returns the matrix selected by the flags.
+func fetchStatus(ctx context.Context, client *api.Client, projectKey string, filter listfilter.Options) (*status.Matrix, error) {
+	features, err := client.FeaturesWithOptions(ctx, projectKey, featureListOptions(filter))
+	if err != nil {
+		return nil, err
+	}
+	features, err = listfilter.Apply(features, featureListFields, filter)
+	if err != nil {
+		return nil, err
+	}
+	envs, err := client.Environments(ctx, projectKey)
+	if err != nil {
+		return nil, err
+	}
+	configs, err := status.Fetch(ctx, client, projectKey, features, fetchWorkers)
+	if err != nil {
+		return nil, err
+	}
+
+	matrix := status.Build(projectKey, features, envs, configs)
+	if len(statusEnvironments) > 0 {
+		if err := matrix.Select(statusEnvironments); err != nil {
+			return nil, err
+		}
+	}
+	if statusOnly != "" {
+		if err := matrix.Only(statusOnly); err != nil {
+			return nil, err
+		}
+	}
+	return matrix, nil
+}
+
+func printStatus(matrix *status.Matrix) error {
+	format := output.ParseFormat(GetOutput())
+	printer := output.NewPrinter(format)
+	switch format {
+	case output.FormatTable:
+		return printer.Print(statusTableData{matrix: matrix})
+	case output.FormatCSV:
+		return printer.Print(statusCSVData{matrix: matrix})
+	}
+	return printer.Print(matrix)
+}
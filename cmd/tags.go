@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/135yshr/devcycle-cli/internal/output"
+	"github.com/135yshr/devcycle-cli/internal/tags"
+	"github.com/spf13/cobra"
+)
+
+var tagsCmd = &cobra.Command{
+	Use:   "tags",
+	Short: "Inspect feature tags",
+	Long:  `Inspect the tags used by the features of a project.`,
+}
+
+var tagsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List tags and how many features use them",
+	Long: `List every tag used by the features of a project, with the number of
+features that have it, most used first.
+
+Tags are edited with 'dvcx features tag'.`,
+	RunE: runTagsList,
+}
+
+var tagsProject string
+
+func init() {
+	rootCmd.AddCommand(tagsCmd)
+	tagsCmd.AddCommand(tagsListCmd)
+
+	tagsCmd.PersistentFlags().StringVarP(&tagsProject, "project", "p", "", "project key (uses config default if not specified)")
+}
+
+type tagsTableData struct {
+	usages []tags.Usage
+}
+
+func (d tagsTableData) Headers() []string {
+	return []string{"TAG", "COUNT", "FEATURES"}
+}
+
+func (d tagsTableData) Rows() [][]string {
+	rows := make([][]string, len(d.usages))
+	for i, u := range d.usages {
+		rows[i] = []string{
+			u.Tag,
+			strconv.Itoa(u.Count),
+			strings.Join(u.Features, ", "),
+		}
+	}
+	return rows
+}
+
+func runTagsList(cmd *cobra.Command, args []string) error {
+	projectKey := tagsProject
+	if projectKey == "" {
+		projectKey = getProjectKey()
+	}
+	if projectKey == "" {
+		return errProjectRequired
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	features, err := client.Features(ctx, projectKey)
+	if err != nil {
+		return err
+	}
+	usages := tags.Count(features)
+
+	printer := output.NewPrinter(output.ParseFormat(GetOutput()))
+
+	if output.ParseFormat(GetOutput()) == output.FormatTable {
+		return printer.Print(tagsTableData{usages: usages})
+	}
+	return printer.Print(usages)
+}
@@ -2,20 +2,17 @@ package cmd
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
-	"os"
+	"sort"
 	"time"
 
+	"github.com/135yshr/devcycle-cli/internal/jsondiff"
 	"github.com/135yshr/devcycle-cli/internal/output"
+	"github.com/135yshr/devcycle-cli/internal/targeting"
 	"github.com/135yshr/devcycle-cli/pkg/api"
 	"github.com/spf13/cobra"
 )
 
-// maxConfigFileSize is the maximum file size allowed for JSON configuration files (10MB)
-const maxConfigFileSize = 10 * 1024 * 1024
-
 var targetingCmd = &cobra.Command{
 	Use:   "targeting",
 	Short: "Manage feature targeting configurations",
@@ -50,10 +47,91 @@ var targetingDisableCmd = &cobra.Command{
 	RunE:  runTargetingDisable,
 }
 
+var targetingDiffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compare targeting between environments, features or projects",
+	Long: `Compare the targeting configuration of a feature between two environments.
+
+Targets are aligned by name and then by audience, and differences in status,
+audience filters and distribution percentages are reported. Use --to-feature
+or --to-project to compare against another feature or project; when --from
+and --to are omitted in that case, every environment is compared.
+
+In table mode a readable diff is printed. With --output json or yaml the
+difference is emitted as a JSON Patch (RFC 6902).`,
+	RunE: runTargetingDiff,
+}
+
+var targetingPromoteCmd = &cobra.Command{
+	Use:   "promote [feature-key...]",
+	Short: "Copy targeting from one environment to another",
+	Long: `Copy a feature's targeting configuration from one environment to another.
+
+A preview of the changes is shown before anything is written, and every
+variation referenced by the source configuration must exist on the feature.
+Additional feature keys may be given as arguments to promote several
+features at once.`,
+	RunE: runTargetingPromote,
+}
+
+var targetingAddRuleCmd = &cobra.Command{
+	Use:   "add-rule",
+	Short: "Add a targeting rule",
+	Long: `Add a targeting rule to an environment without replacing existing rules.
+
+Conditions are given with --where as a filter expression, for example
+"user.email endsWith @acme.com" or
+'country in ("CA", "US") and (customData.tier = "gold" or audience("beta"))'.
+Several --where flags are combined with --match (and by default). The variations to
+serve are given with --serve as "variation:percent,...".
+
+New rules are placed before a trailing "all users" rule unless --position
+is given.
+
+When stdin is a terminal and --name or --serve is missing, the missing
+flags are asked for, offering the environments and variations that exist.`,
+	RunE: runTargetingAddRule,
+}
+
+var targetingRemoveRuleCmd = &cobra.Command{
+	Use:   "remove-rule",
+	Short: "Remove a targeting rule",
+	Long:  `Remove a targeting rule from an environment by name.`,
+	RunE:  runTargetingRemoveRule,
+}
+
+var targetingMoveRuleCmd = &cobra.Command{
+	Use:   "move-rule",
+	Short: "Move a targeting rule",
+	Long:  `Move a targeting rule to a new position. Rules are evaluated in order, starting at position 1.`,
+	RunE:  runTargetingMoveRule,
+}
+
+var targetingSetDefaultCmd = &cobra.Command{
+	Use:   "set-default",
+	Short: "Set the default variation distribution",
+	Long: `Set what is served to users who match no other rule.
+
+The last "all users" rule is updated, or a new one is appended.`,
+	RunE: runTargetingSetDefault,
+}
+
 var targetingProject string
 var targetingFeature string
 var targetingEnvironment string
 var targetingFromFile string
+var targetingFrom string
+var targetingTo string
+var targetingToFeature string
+var targetingToProject string
+var targetingDryRun bool
+var targetingForce bool
+var targetingKeepStatus bool
+var targetingRuleName string
+var targetingWhere []string
+var targetingMatch string
+var targetingServe string
+var targetingPosition int
 
 func init() {
 	rootCmd.AddCommand(targetingCmd)
@@ -61,17 +139,50 @@ func init() {
 	targetingCmd.AddCommand(targetingUpdateCmd)
 	targetingCmd.AddCommand(targetingEnableCmd)
 	targetingCmd.AddCommand(targetingDisableCmd)
+	targetingCmd.AddCommand(targetingDiffCmd)
+	targetingCmd.AddCommand(targetingPromoteCmd)
+	targetingCmd.AddCommand(targetingAddRuleCmd)
+	targetingCmd.AddCommand(targetingRemoveRuleCmd)
+	targetingCmd.AddCommand(targetingMoveRuleCmd)
+	targetingCmd.AddCommand(targetingSetDefaultCmd)
 
 	// Persistent flags for all targeting commands
 	targetingCmd.PersistentFlags().StringVarP(&targetingProject, "project", "p", "", "project key (uses config default if not specified)")
 	targetingCmd.PersistentFlags().StringVarP(&targetingFeature, "feature", "f", "", "feature key (required)")
 
 	// Update command flags
-	targetingUpdateCmd.Flags().StringVarP(&targetingFromFile, "from-file", "F", "", "JSON input file for configuration update, use '-' for stdin")
+	targetingUpdateCmd.Flags().StringVarP(&targetingFromFile, "from-file", "F", "", "JSON or YAML input file for configuration update, use '-' for stdin")
 
 	// Enable/Disable command flags
 	targetingEnableCmd.Flags().StringVarP(&targetingEnvironment, "environment", "e", "", "environment key (required)")
 	targetingDisableCmd.Flags().StringVarP(&targetingEnvironment, "environment", "e", "", "environment key (required)")
+
+	// Diff command flags
+	targetingDiffCmd.Flags().StringVar(&targetingFrom, "from", "", "source environment key")
+	targetingDiffCmd.Flags().StringVar(&targetingTo, "to", "", "destination environment key (defaults to --from)")
+	targetingDiffCmd.Flags().StringVar(&targetingToFeature, "to-feature", "", "feature key to compare against (defaults to --feature)")
+	targetingDiffCmd.Flags().StringVar(&targetingToProject, "to-project", "", "project key to compare against (defaults to --project)")
+
+	// Promote command flags
+	targetingPromoteCmd.Flags().StringVar(&targetingFrom, "from", "", "source environment key (required)")
+	targetingPromoteCmd.Flags().StringVar(&targetingTo, "to", "", "destination environment key (required)")
+	targetingPromoteCmd.Flags().BoolVar(&targetingKeepStatus, "keep-status", false, "keep the destination environment's current status")
+	targetingPromoteCmd.Flags().BoolVar(&targetingDryRun, "dry-run", false, "show the preview without applying changes")
+	targetingPromoteCmd.Flags().BoolVar(&targetingForce, "force", false, "skip confirmation prompt")
+
+	// Rule builder command flags
+	for _, c := range []*cobra.Command{targetingAddRuleCmd, targetingRemoveRuleCmd, targetingMoveRuleCmd, targetingSetDefaultCmd} {
+		c.Flags().StringVarP(&targetingEnvironment, "environment", "e", "", "environment key (required)")
+	}
+	targetingAddRuleCmd.Flags().StringVar(&targetingRuleName, "name", "", "rule name (required)")
+	targetingAddRuleCmd.Flags().StringArrayVar(&targetingWhere, "where", nil, "condition such as 'user.email endsWith @acme.com' (repeatable)")
+	targetingAddRuleCmd.Flags().StringVar(&targetingMatch, "match", "and", "how multiple --where conditions combine (and, or)")
+	targetingAddRuleCmd.Flags().StringVar(&targetingServe, "serve", "", "variations to serve, e.g. on:50,off:50 (required)")
+	targetingAddRuleCmd.Flags().IntVar(&targetingPosition, "position", 0, "1-based position of the new rule (default: before the all-users rule)")
+	targetingRemoveRuleCmd.Flags().StringVar(&targetingRuleName, "name", "", "rule name (required)")
+	targetingMoveRuleCmd.Flags().StringVar(&targetingRuleName, "name", "", "rule name (required)")
+	targetingMoveRuleCmd.Flags().IntVar(&targetingPosition, "position", 0, "new 1-based position (required)")
+	targetingSetDefaultCmd.Flags().StringVar(&targetingServe, "serve", "", "variations to serve, e.g. off or on:10,off:90 (required)")
 }
 
 type targetingTableData struct {
@@ -148,37 +259,14 @@ func runTargetingUpdate(cmd *cobra.Command, args []string) error {
 	}
 
 	// Load configuration from file
-	var data []byte
-	var err error
-
-	if targetingFromFile == "-" {
-		data, err = io.ReadAll(io.LimitReader(os.Stdin, maxConfigFileSize))
-		if err != nil {
-			return fmt.Errorf("failed to read from stdin: %w", err)
-		}
-	} else {
-		file, err := os.Open(targetingFromFile)
-		if err != nil {
-			return fmt.Errorf("failed to open file %s: %w", targetingFromFile, err)
-		}
-		defer file.Close()
-
-		info, err := file.Stat()
-		if err != nil {
-			return fmt.Errorf("failed to stat file %s: %w", targetingFromFile, err)
-		}
-		if info.Size() > maxConfigFileSize {
-			return fmt.Errorf("file %s exceeds maximum allowed size (%d bytes)", targetingFromFile, maxConfigFileSize)
-		}
-		data, err = io.ReadAll(file)
-		if err != nil {
-			return fmt.Errorf("failed to read file %s: %w", targetingFromFile, err)
-		}
+	data, err := api.ReadInputFile(targetingFromFile)
+	if err != nil {
+		return err
 	}
 
 	var configs map[string]*api.EnvironmentConfig
-	if err := json.Unmarshal(data, &configs); err != nil {
-		return fmt.Errorf("failed to parse JSON: %w", err)
+	if err := api.DecodeInput(data, targetingFromFile, &configs); err != nil {
+		return err
 	}
 
 	client, err := getClient()
@@ -261,3 +349,456 @@ func runTargetingDisable(cmd *cobra.Command, args []string) error {
 	cmd.Printf("Feature '%s' disabled for environment '%s'\n", targetingFeature, targetingEnvironment)
 	return nil
 }
+
+type targetingDiffPair struct {
+	fromEnv string
+	toEnv   string
+}
+
+func runTargetingDiff(cmd *cobra.Command, args []string) error {
+	projectKey := getTargetingProjectKey()
+	if projectKey == "" {
+		return errProjectRequired
+	}
+	if targetingFeature == "" {
+		return fmt.Errorf("required flag \"feature\" not set")
+	}
+	if targetingFrom == "" && targetingTo != "" {
+		return fmt.Errorf("required flag \"from\" not set")
+	}
+
+	toProject := targetingToProject
+	if toProject == "" {
+		toProject = projectKey
+	}
+	toFeature := targetingToFeature
+	if toFeature == "" {
+		toFeature = targetingFeature
+	}
+	toEnv := targetingTo
+	if toEnv == "" {
+		toEnv = targetingFrom
+	}
+
+	sameSource := toProject == projectKey && toFeature == targetingFeature
+	if sameSource && targetingFrom == "" {
+		return fmt.Errorf("specify --from and --to, or --to-feature/--to-project to compare")
+	}
+	if sameSource && targetingFrom == toEnv {
+		return fmt.Errorf("nothing to compare: source and destination are the same")
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	fromConfigs, err := client.FeatureConfigurations(ctx, projectKey, targetingFeature)
+	if err != nil {
+		return err
+	}
+	toConfigs := fromConfigs
+	if !sameSource {
+		toConfigs, err = client.FeatureConfigurations(ctx, toProject, toFeature)
+		if err != nil {
+			return err
+		}
+	}
+
+	var pairs []targetingDiffPair
+	if targetingFrom != "" {
+		if _, ok := fromConfigs[targetingFrom]; !ok {
+			return fmt.Errorf("environment '%s' not found for feature '%s'", targetingFrom, targetingFeature)
+		}
+		if _, ok := toConfigs[toEnv]; !ok {
+			return fmt.Errorf("environment '%s' not found for feature '%s'", toEnv, toFeature)
+		}
+		pairs = append(pairs, targetingDiffPair{fromEnv: targetingFrom, toEnv: toEnv})
+	} else {
+		envs := make(map[string]bool)
+		for env := range fromConfigs {
+			envs[env] = true
+		}
+		for env := range toConfigs {
+			envs[env] = true
+		}
+		for env := range envs {
+			pairs = append(pairs, targetingDiffPair{fromEnv: env, toEnv: env})
+		}
+		sort.Slice(pairs, func(i, j int) bool { return pairs[i].fromEnv < pairs[j].fromEnv })
+	}
+
+	label := func(project, feature, env string) string {
+		if project != projectKey || toProject != projectKey {
+			return fmt.Sprintf("%s/%s (%s)", project, feature, env)
+		}
+		return fmt.Sprintf("%s (%s)", feature, env)
+	}
+
+	format := output.ParseFormat(GetOutput())
+	patches := make(map[string][]jsondiff.Op, len(pairs))
+	for i, pair := range pairs {
+		diff, err := targeting.Compare(fromConfigs[pair.fromEnv], toConfigs[pair.toEnv])
+		if err != nil {
+			return err
+		}
+
+		if format == output.FormatTable {
+			if i > 0 {
+				cmd.Println()
+			}
+			diff.WriteText(cmd.OutOrStdout(), label(projectKey, targetingFeature, pair.fromEnv), label(toProject, toFeature, pair.toEnv), useColor())
+			continue
+		}
+
+		patch, err := diff.Patch()
+		if err != nil {
+			return err
+		}
+		if patch == nil {
+			patch = []jsondiff.Op{}
+		}
+		patches[pair.toEnv] = patch
+	}
+
+	if format == output.FormatTable {
+		return nil
+	}
+
+	printer := output.NewPrinter(format)
+	if targetingFrom != "" {
+		return printer.Print(patches[toEnv])
+	}
+	return printer.Print(patches)
+}
+
+type targetingPromotion struct {
+	feature string
+	config  *api.EnvironmentConfig
+}
+
+func runTargetingPromote(cmd *cobra.Command, args []string) error {
+	projectKey := getTargetingProjectKey()
+	if projectKey == "" {
+		return errProjectRequired
+	}
+	if targetingFrom == "" {
+		return fmt.Errorf("required flag \"from\" not set")
+	}
+	if targetingTo == "" {
+		return fmt.Errorf("required flag \"to\" not set")
+	}
+	if targetingFrom == targetingTo {
+		return fmt.Errorf("source and destination environments must differ")
+	}
+
+	var features []string
+	if targetingFeature != "" {
+		features = append(features, targetingFeature)
+	}
+	features = append(features, args...)
+	if len(features) == 0 {
+		return fmt.Errorf("required flag \"feature\" not set")
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	var promotions []targetingPromotion
+	for i, feature := range features {
+		promoted, diff, err := planPromotion(client, projectKey, feature)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		if !diff.HasChanges() {
+			fmt.Fprintf(out, "Feature '%s': %s already matches %s\n", feature, targetingTo, targetingFrom)
+			continue
+		}
+		diff.WriteText(out, fmt.Sprintf("%s (%s, current)", feature, targetingTo), fmt.Sprintf("%s (%s, promoted from %s)", feature, targetingTo, targetingFrom), useColor())
+		promotions = append(promotions, targetingPromotion{feature: feature, config: promoted})
+	}
+
+	if len(promotions) == 0 {
+		return nil
+	}
+	if targetingDryRun {
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "Dry-run complete. No changes were made.")
+		return nil
+	}
+
+	fmt.Fprintln(out)
+	if !confirmAction(fmt.Sprintf("Promote targeting for %d feature(s) from '%s' to '%s'?", len(promotions), targetingFrom, targetingTo), targetingForce) {
+		cmd.Println("Promotion cancelled")
+		return nil
+	}
+
+	for _, p := range promotions {
+		req := &api.UpdateFeatureConfigurationsRequest{
+			Configurations: map[string]*api.EnvironmentConfig{
+				targetingTo: p.config,
+			},
+		}
+		_, err := withTimeout(context.Background(), func(ctx context.Context) (map[string]*api.EnvironmentConfig, error) {
+			return client.UpdateFeatureConfigurations(ctx, projectKey, p.feature, req)
+		})
+		if err != nil {
+			return err
+		}
+		cmd.Printf("Feature '%s' promoted from '%s' to '%s'\n", p.feature, targetingFrom, targetingTo)
+	}
+	return nil
+}
+
+// planPromotion fetches the configurations of feature and returns the
+// promoted configuration of the destination environment and how it differs
+// from the current one.
+func planPromotion(client *api.Client, projectKey, feature string) (*api.EnvironmentConfig, *targeting.Diff, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	configs, err := client.FeatureConfigurations(ctx, projectKey, feature)
+	if err != nil {
+		return nil, nil, err
+	}
+	src, ok := configs[targetingFrom]
+	if !ok {
+		return nil, nil, fmt.Errorf("environment '%s' not found for feature '%s'", targetingFrom, feature)
+	}
+	dst, ok := configs[targetingTo]
+	if !ok {
+		return nil, nil, fmt.Errorf("environment '%s' not found for feature '%s'", targetingTo, feature)
+	}
+
+	variations, err := client.Variations(ctx, projectKey, feature)
+	if err != nil {
+		return nil, nil, err
+	}
+	if missing := targeting.MissingVariations(src, variations); len(missing) > 0 {
+		return nil, nil, fmt.Errorf("feature '%s' references unknown variation(s) in %s: %v", feature, targetingFrom, missing)
+	}
+
+	keepStatus := ""
+	if targetingKeepStatus && dst != nil {
+		keepStatus = dst.Status
+	}
+	promoted, err := targeting.Promote(src, keepStatus)
+	if err != nil {
+		return nil, nil, err
+	}
+	diff, err := targeting.Compare(dst, promoted)
+	if err != nil {
+		return nil, nil, err
+	}
+	return promoted, diff, nil
+}
+
+// modifyTargeting fetches the configuration of the environment given by
+// --environment, applies fn to it and writes the environment back, leaving
+// other environments untouched.
+func modifyTargeting(fn func(cfg *api.EnvironmentConfig, variations []api.Variation) error) (*api.EnvironmentConfig, error) {
+	projectKey := getTargetingProjectKey()
+	if projectKey == "" {
+		return nil, errProjectRequired
+	}
+	if targetingFeature == "" {
+		return nil, fmt.Errorf("required flag \"feature\" not set")
+	}
+	if targetingEnvironment == "" {
+		return nil, fmt.Errorf("required flag \"environment\" not set")
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	configs, err := client.FeatureConfigurations(ctx, projectKey, targetingFeature)
+	if err != nil {
+		return nil, err
+	}
+	cfg, ok := configs[targetingEnvironment]
+	if !ok {
+		return nil, fmt.Errorf("environment '%s' not found for feature '%s'", targetingEnvironment, targetingFeature)
+	}
+	if cfg == nil {
+		cfg = &api.EnvironmentConfig{Status: "inactive"}
+	}
+
+	variations, err := client.Variations(ctx, projectKey, targetingFeature)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fn(cfg, variations); err != nil {
+		return nil, err
+	}
+
+	req := &api.UpdateFeatureConfigurationsRequest{
+		Configurations: map[string]*api.EnvironmentConfig{
+			targetingEnvironment: cfg,
+		},
+	}
+	result, err := client.UpdateFeatureConfigurations(ctx, projectKey, targetingFeature, req)
+	if err != nil {
+		return nil, err
+	}
+	return result[targetingEnvironment], nil
+}
+
+func printTargetingRules(cmd *cobra.Command, cfg *api.EnvironmentConfig) error {
+	if cfg == nil {
+		return nil
+	}
+	printer := output.NewPrinter(output.ParseFormat(GetOutput()))
+	if output.ParseFormat(GetOutput()) == output.FormatTable {
+		return printer.Print(targetingRulesTableData{targets: cfg.Targets})
+	}
+	return printer.Print(cfg)
+}
+
+type targetingRulesTableData struct {
+	targets []api.Target
+}
+
+func (d targetingRulesTableData) Headers() []string {
+	return []string{"#", "NAME", "FILTERS", "SERVE"}
+}
+
+func (d targetingRulesTableData) Rows() [][]string {
+	names := targeting.TargetNames(d.targets)
+	rows := make([][]string, len(d.targets))
+	for i, t := range d.targets {
+		filters := "all users"
+		if !targeting.IsCatchAll(t) {
+			filters = describeFilters(t.Audience.Filters)
+		}
+		rows[i] = []string{
+			fmt.Sprintf("%d", i+1),
+			names[i],
+			filters,
+			targeting.FormatDistribution(t.Distribution),
+		}
+	}
+	return rows
+}
+
+func runTargetingAddRule(cmd *cobra.Command, args []string) error {
+	// Ask for the missing flags on a terminal
+	if (targetingRuleName == "" || targetingServe == "") && canPrompt() {
+		if err := askTargetingRuleFlags(cmd); err != nil {
+			return err
+		}
+	}
+	if targetingRuleName == "" {
+		return fmt.Errorf("required flag \"name\" not set")
+	}
+	if targetingServe == "" {
+		return fmt.Errorf("required flag \"serve\" not set")
+	}
+	if targetingPosition < 0 {
+		return fmt.Errorf("--position must be 1 or greater")
+	}
+	filters, err := combineWhere(targetingWhere, targetingMatch)
+	if err != nil {
+		return err
+	}
+	if hasAudienceRefs(&filters) {
+		client, err := getClient()
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := resolveAudienceRefs(ctx, client, getTargetingProjectKey(), &filters); err != nil {
+			return err
+		}
+	}
+
+	cfg, err := modifyTargeting(func(cfg *api.EnvironmentConfig, variations []api.Variation) error {
+		dist, err := targeting.ResolveServe(targetingServe, cfg, variations)
+		if err != nil {
+			return err
+		}
+		target := api.Target{
+			Name:         targetingRuleName,
+			Audience:     api.Audience{Name: targetingRuleName, Filters: filters},
+			Distribution: dist,
+		}
+		return targeting.AddRule(cfg, target, targetingPosition-1)
+	})
+	if err != nil {
+		return err
+	}
+
+	cmd.Printf("Rule '%s' added to environment '%s'\n", targetingRuleName, targetingEnvironment)
+	return printTargetingRules(cmd, cfg)
+}
+
+func runTargetingRemoveRule(cmd *cobra.Command, args []string) error {
+	if targetingRuleName == "" {
+		return fmt.Errorf("required flag \"name\" not set")
+	}
+
+	cfg, err := modifyTargeting(func(cfg *api.EnvironmentConfig, variations []api.Variation) error {
+		return targeting.RemoveRule(cfg, targetingRuleName)
+	})
+	if err != nil {
+		return err
+	}
+
+	cmd.Printf("Rule '%s' removed from environment '%s'\n", targetingRuleName, targetingEnvironment)
+	return printTargetingRules(cmd, cfg)
+}
+
+func runTargetingMoveRule(cmd *cobra.Command, args []string) error {
+	if targetingRuleName == "" {
+		return fmt.Errorf("required flag \"name\" not set")
+	}
+	if targetingPosition < 1 {
+		return fmt.Errorf("required flag \"position\" not set")
+	}
+
+	cfg, err := modifyTargeting(func(cfg *api.EnvironmentConfig, variations []api.Variation) error {
+		return targeting.MoveRule(cfg, targetingRuleName, targetingPosition-1)
+	})
+	if err != nil {
+		return err
+	}
+
+	cmd.Printf("Rule '%s' moved to position %d in environment '%s'\n", targetingRuleName, targetingPosition, targetingEnvironment)
+	return printTargetingRules(cmd, cfg)
+}
+
+func runTargetingSetDefault(cmd *cobra.Command, args []string) error {
+	if targetingServe == "" {
+		return fmt.Errorf("required flag \"serve\" not set")
+	}
+
+	cfg, err := modifyTargeting(func(cfg *api.EnvironmentConfig, variations []api.Variation) error {
+		dist, err := targeting.ResolveServe(targetingServe, cfg, variations)
+		if err != nil {
+			return err
+		}
+		targeting.SetDefault(cfg, dist)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	cmd.Printf("Default rule updated in environment '%s'\n", targetingEnvironment)
+	return printTargetingRules(cmd, cfg)
+}
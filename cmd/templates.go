@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/135yshr/devcycle-cli/internal/config"
+	"github.com/135yshr/devcycle-cli/internal/output"
+	"github.com/135yshr/devcycle-cli/internal/templates"
+	"github.com/spf13/cobra"
+)
+
+// templatesDir is the directory under the config directory that holds user templates
+const templatesDir = "templates"
+
+var templatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "Manage feature templates",
+	Long: `Manage the templates used by 'dvcx features create --template'.
+
+A template renders a feature definition from a few parameters, so that
+features with the same structure are created the same way every time. dvcx
+comes with built-in templates; templates in .devcycle/templates are added to
+them and replace built-in templates of the same name.`,
+}
+
+var templatesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List feature templates",
+	Long:  `List the built-in templates and the templates in .devcycle/templates.`,
+	RunE:  runTemplatesList,
+}
+
+var templatesShowCmd = &cobra.Command{
+	Use:   "show [template]",
+	Short: "Show a feature template",
+	Long: `Show the parameters and the feature definition of a template.
+
+Examples:
+  dvcx templates show ab-test
+  dvcx templates show kill-switch -o json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTemplatesShow,
+}
+
+var templatesInitCmd = &cobra.Command{
+	Use:   "init [name]",
+	Short: "Create a feature template",
+	Long: `Create a template in .devcycle/templates, either from a skeleton or as a
+copy of an existing template to adapt.
+
+Examples:
+  dvcx templates init team-release
+  dvcx templates init checkout-experiment --from ab-test`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTemplatesInit,
+}
+
+var templatesFrom string
+var templatesForce bool
+
+func init() {
+	rootCmd.AddCommand(templatesCmd)
+	templatesCmd.AddCommand(templatesListCmd)
+	templatesCmd.AddCommand(templatesShowCmd)
+	templatesCmd.AddCommand(templatesInitCmd)
+
+	templatesInitCmd.Flags().StringVar(&templatesFrom, "from", "", "template to copy")
+	templatesInitCmd.Flags().BoolVar(&templatesForce, "force", false, "overwrite an existing template file")
+}
+
+type templatesTableData struct {
+	templates []*templates.Template
+}
+
+func (d templatesTableData) Headers() []string {
+	return []string{"NAME", "SOURCE", "DESCRIPTION"}
+}
+
+func (d templatesTableData) Rows() [][]string {
+	wd, _ := os.Getwd()
+	rows := make([][]string, len(d.templates))
+	for i, t := range d.templates {
+		source := t.Source
+		if rel, err := filepath.Rel(wd, source); err == nil && source != templates.SourceBuiltin {
+			source = rel
+		}
+		rows[i] = []string{t.Name, source, t.Description}
+	}
+	return rows
+}
+
+// templatesDirPath returns the directory of the user templates.
+func templatesDirPath() (string, error) {
+	configDir, err := config.ConfigDirPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, templatesDir), nil
+}
+
+// findTemplate returns the template called name.
+func findTemplate(name string) (*templates.Template, error) {
+	dir, err := templatesDirPath()
+	if err != nil {
+		return nil, err
+	}
+	return templates.Find(dir, name)
+}
+
+func runTemplatesList(cmd *cobra.Command, args []string) error {
+	dir, err := templatesDirPath()
+	if err != nil {
+		return err
+	}
+	list, err := templates.Load(dir)
+	if err != nil {
+		return err
+	}
+
+	printer := output.NewPrinter(output.ParseFormat(GetOutput()))
+	if output.ParseFormat(GetOutput()) == output.FormatTable {
+		return printer.Print(templatesTableData{templates: list})
+	}
+	return printer.Print(list)
+}
+
+func runTemplatesShow(cmd *cobra.Command, args []string) error {
+	t, err := findTemplate(args[0])
+	if err != nil {
+		return err
+	}
+
+	if output.ParseFormat(GetOutput()) == output.FormatTable {
+		_, err := cmd.OutOrStdout().Write(t.Raw)
+		return err
+	}
+	printer := output.NewPrinter(output.ParseFormat(GetOutput()))
+	return printer.Print(t)
+}
+
+// templateNamePattern matches the names of templates.
+var templateNamePattern = regexp.MustCompile(`^[a-z0-9._-]+$`)
+
+// templateNameLine matches the line that names a template.
+var templateNameLine = regexp.MustCompile(`(?m)^name:.*$`)
+
+func runTemplatesInit(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if !templateNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid template name %q (use lowercase letters, digits, '.', '_' and '-')", name)
+	}
+
+	content := templates.Skeleton(name)
+	if templatesFrom != "" {
+		from, err := findTemplate(templatesFrom)
+		if err != nil {
+			return err
+		}
+		loc := templateNameLine.FindIndex(from.Raw)
+		content = append(append(append([]byte{}, from.Raw[:loc[0]]...), "name: "+name...), from.Raw[loc[1]:]...)
+	}
+
+	dir, err := templatesDirPath()
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, name+".yaml")
+	if _, err := templates.Parse(content, path); err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err == nil && !templatesForce {
+		return fmt.Errorf("%s already exists (use --force to overwrite it)", path)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create template directory: %w", err)
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return fmt.Errorf("failed to write template: %w", err)
+	}
+
+	cmd.Printf("Template '%s' created at %s\n", name, path)
+	return nil
+}
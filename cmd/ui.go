@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/135yshr/devcycle-cli/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+var uiCmd = &cobra.Command{
+	Use:   "ui",
+	Short: "Browse and change features in a terminal UI",
+	Long: `Open a full-screen terminal UI to browse projects and features.
+
+The UI starts with the features of the project given with --project or set
+in the config, or with the list of projects. Selecting a feature shows its
+variations, variables and status in each environment. From there a feature
+can be enabled or disabled in an environment, its default distribution can
+be changed, and its recent audit entries can be viewed. Every change is
+confirmed before it is made.
+
+The current screen is refreshed every --refresh interval; 'r' refreshes it
+at once.
+
+Keys:
+  ↑/↓ or j/k   move
+  enter        open
+  esc          go back
+  /            search features by key, name or tag
+  space        enable or disable the feature in the selected environment
+  d            edit the default distribution, e.g. "on:90,off:10"
+  a            show the audit entries of the feature
+  r            refresh
+  q            quit
+
+Examples:
+  dvcx ui
+  dvcx ui -p my-app --refresh 10s`,
+	RunE: runUI,
+}
+
+var uiProject string
+var uiRefresh time.Duration
+
+func init() {
+	rootCmd.AddCommand(uiCmd)
+
+	uiCmd.Flags().StringVarP(&uiProject, "project", "p", "", "project key (uses config default if not specified)")
+	uiCmd.Flags().DurationVar(&uiRefresh, "refresh", 30*time.Second, "interval between background refreshes (0 to disable)")
+}
+
+func runUI(cmd *cobra.Command, args []string) error {
+	if !isTerminal(os.Stdin) || !isTerminal(os.Stdout) {
+		return errors.New("the UI requires an interactive terminal")
+	}
+	if uiRefresh < 0 {
+		return errors.New("--refresh must not be negative")
+	}
+
+	projectKey := uiProject
+	if projectKey == "" {
+		projectKey = getProjectKey()
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	term, err := tui.Open(os.Stdin, os.Stdout)
+	if err != nil {
+		return err
+	}
+	defer term.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	err = tui.Run(ctx, term, tui.New(client, projectKey), uiRefresh)
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
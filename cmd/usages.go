@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/135yshr/devcycle-cli/internal/config"
+	"github.com/135yshr/devcycle-cli/internal/output"
+	"github.com/135yshr/devcycle-cli/internal/usages"
+	"github.com/spf13/cobra"
+)
+
+var usagesCmd = &cobra.Command{
+	Use:   "usages [paths...]",
+	Short: "Find variable keys referenced in source code",
+	Long: `Scan source code for references to variable keys and compare them with the
+variables defined in the project.
+
+Go, TypeScript/JavaScript, Python, Java, Kotlin and Swift files are scanned
+for SDK calls such as variableValue(user, "key", default). Files written by
+'dvcx generate' are recognized, and references to their constants and
+accessors count as usages of the underlying keys. The current directory is
+scanned when no paths are given; .git, node_modules and vendor are skipped.
+
+Keys referenced in code but not defined in the project are reported as
+missing; defined keys that are never referenced are reported as unused.
+
+Additional patterns can be given with --pattern lang=regexp, where the first
+capture group is the key, or in the config file:
+
+  usages:
+    patterns:
+      - lang: go
+        pattern: 'flags\.Bool\("([^"]+)"'
+    exclude:
+      - testdata
+      - "*.min.js"`,
+	RunE: runUsages,
+}
+
+var usagesProject string
+var usagesPatterns []string
+var usagesExclude []string
+
+func init() {
+	rootCmd.AddCommand(usagesCmd)
+
+	usagesCmd.Flags().StringVarP(&usagesProject, "project", "p", "", "project key (uses config default if not specified)")
+	usagesCmd.Flags().StringArrayVar(&usagesPatterns, "pattern", nil, "additional pattern as lang=regexp (can be repeated)")
+	usagesCmd.Flags().StringSliceVar(&usagesExclude, "exclude", nil, "glob of files or directories to skip (can be repeated)")
+}
+
+type usagesTableData struct {
+	reports []usages.KeyReport
+}
+
+func (d usagesTableData) Headers() []string {
+	return []string{"KEY", "STATUS", "LOCATIONS"}
+}
+
+func (d usagesTableData) Rows() [][]string {
+	rows := make([][]string, len(d.reports))
+	for i, r := range d.reports {
+		rows[i] = []string{r.Key, r.Status, formatUsageLocations(r.Locations)}
+	}
+	return rows
+}
+
+// maxTableLocations is the number of locations shown per key in table
+// output.
+const maxTableLocations = 3
+
+func formatUsageLocations(locs []usages.Usage) string {
+	if len(locs) == 0 {
+		return "-"
+	}
+	var parts []string
+	for i, u := range locs {
+		if i == maxTableLocations {
+			parts = append(parts, fmt.Sprintf("(+%d more)", len(locs)-i))
+			break
+		}
+		parts = append(parts, u.Location())
+	}
+	return strings.Join(parts, ", ")
+}
+
+// newUsagesScanner returns a scanner with the built-in patterns plus those
+// from the config file and the given flags.
+func newUsagesScanner(flagPatterns, flagExclude []string) (*usages.Scanner, error) {
+	cfg := config.Usages()
+	patterns := usages.DefaultPatterns()
+	for _, p := range cfg.Patterns {
+		pattern, err := usages.NewPattern(p.Lang, p.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid usages pattern in config: %w", err)
+		}
+		patterns = append(patterns, pattern)
+	}
+	for _, s := range flagPatterns {
+		pattern, err := usages.ParsePattern(s)
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, pattern)
+	}
+	exclude := append(append([]string{}, cfg.Exclude...), flagExclude...)
+	return usages.NewScanner(patterns, exclude), nil
+}
+
+func runUsages(cmd *cobra.Command, args []string) error {
+	projectKey := usagesProject
+	if projectKey == "" {
+		projectKey = getProjectKey()
+	}
+	if projectKey == "" {
+		return errProjectRequired
+	}
+	paths := args
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	scanner, err := newUsagesScanner(usagesPatterns, usagesExclude)
+	if err != nil {
+		return err
+	}
+	found, err := scanner.Scan(paths)
+	if err != nil {
+		return err
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	variables, err := client.Variables(ctx, projectKey)
+	if err != nil {
+		return err
+	}
+	defined := make([]string, len(variables))
+	for i, v := range variables {
+		defined[i] = v.Key
+	}
+
+	reports := usages.Analyze(found, defined)
+	printer := output.NewPrinter(output.ParseFormat(GetOutput()))
+	if output.ParseFormat(GetOutput()) != output.FormatTable {
+		return printer.Print(reports)
+	}
+	if err := printer.Print(usagesTableData{reports: reports}); err != nil {
+		return err
+	}
+
+	counts := make(map[string]int)
+	for _, r := range reports {
+		counts[r.Status]++
+	}
+	cmd.Printf("\n%d referenced, %d missing, %d unused\n", counts[usages.StatusOK]+counts[usages.StatusMissing], counts[usages.StatusMissing], counts[usages.StatusUnused])
+	return nil
+}
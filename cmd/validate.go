@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/135yshr/devcycle-cli/internal/inputs"
+	"github.com/135yshr/devcycle-cli/internal/jsonschema"
+	"github.com/135yshr/devcycle-cli/internal/output"
+	"github.com/135yshr/devcycle-cli/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate [files...]",
+	Short: "Check input files before applying them",
+	Long: `Check feature, targeting, audience and variation files before they are
+applied with --from-file.
+
+Each document is checked against the JSON Schema of its kind (see 'dvcx
+schema') and then for mistakes a schema cannot catch:
+  - variations must give a value of the declared type to every variable,
+    matching the variable's validation schema
+  - the control variation and distributions must name declared variations,
+    and the percentages of each distribution must add up to 1
+  - audiences matched by filters must exist in the project
+
+The kind of each document is detected from its properties unless --kind is
+given. Checks against the project need authentication; --offline skips them.
+Targeting and variation files are checked against the variations and
+variables of the feature given with --feature.
+
+The command exits with an error when a problem is found, so it can run in CI.
+
+Examples:
+  dvcx validate dark-mode.yaml
+  dvcx validate features/*.yaml --offline
+  dvcx validate targeting.json --kind targeting --feature dark-mode
+  cat audience.json | dvcx validate -`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runValidate,
+}
+
+var validateProject string
+var validateKind string
+var validateFeature string
+var validateOffline bool
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+
+	validateCmd.Flags().StringVarP(&validateProject, "project", "p", "", "project key (uses config default if not specified)")
+	validateCmd.Flags().StringVar(&validateKind, "kind", "", "kind of every document: feature, targeting, audience or variation (default: detected)")
+	validateCmd.Flags().StringVarP(&validateFeature, "feature", "f", "", "feature that targeting and variation files are for")
+	validateCmd.Flags().BoolVar(&validateOffline, "offline", false, "skip the checks that need the API")
+}
+
+// validationResult is the outcome of checking one document of a file.
+type validationResult struct {
+	File     string             `json:"file"`
+	Document int                `json:"document"`
+	Kind     string             `json:"kind,omitempty"`
+	Problems []jsonschema.Error `json:"problems"`
+}
+
+// name identifies the document, adding its number when the file holds
+// several.
+func (r validationResult) name(documents int) string {
+	if documents > 1 {
+		return fmt.Sprintf("%s#%d", r.File, r.Document)
+	}
+	return r.File
+}
+
+type validationTableData struct {
+	results []validationResult
+	counts  map[string]int
+}
+
+func (d validationTableData) Headers() []string {
+	return []string{"FILE", "KIND", "PATH", "PROBLEM"}
+}
+
+func (d validationTableData) Rows() [][]string {
+	var rows [][]string
+	for _, r := range d.results {
+		for _, p := range r.Problems {
+			path := p.Path
+			if path == "" {
+				path = "-"
+			}
+			rows = append(rows, []string{r.name(d.counts[r.File]), r.Kind, path, p.Message})
+		}
+	}
+	return rows
+}
+
+func runValidate(cmd *cobra.Command, args []string) error {
+	var kind *inputs.Kind
+	if validateKind != "" {
+		k, err := lookupKind(validateKind)
+		if err != nil {
+			return err
+		}
+		kind = &k
+	}
+
+	project := &inputs.Project{}
+	if !validateOffline {
+		projectKey := validateProject
+		if projectKey == "" {
+			projectKey = getProjectKey()
+		}
+		if projectKey == "" {
+			return fmt.Errorf("%w (or use --offline)", errProjectRequired)
+		}
+		var err error
+		if project, err = loadValidationProject(projectKey, validateFeature); err != nil {
+			return err
+		}
+	} else if validateFeature != "" {
+		return fmt.Errorf("--feature cannot be combined with --offline")
+	}
+
+	var results []validationResult
+	counts := make(map[string]int)
+	for _, path := range args {
+		fileResults := validateFile(path, kind, project)
+		counts[fileResults[0].File] = len(fileResults)
+		results = append(results, fileResults...)
+	}
+
+	problems, invalid := 0, 0
+	for _, r := range results {
+		problems += len(r.Problems)
+		if len(r.Problems) > 0 {
+			invalid++
+		}
+	}
+
+	printer := output.NewPrinter(output.ParseFormat(GetOutput()))
+	if output.ParseFormat(GetOutput()) == output.FormatTable {
+		if problems > 0 {
+			if err := printer.Print(validationTableData{results: results, counts: counts}); err != nil {
+				return err
+			}
+		} else {
+			cmd.Printf("No problems found in %d documents\n", len(results))
+		}
+	} else if err := printer.Print(results); err != nil {
+		return err
+	}
+
+	if problems > 0 {
+		return fmt.Errorf("%d problems found in %d of %d documents", problems, invalid, len(results))
+	}
+	return nil
+}
+
+// validateFile checks every document of the file at path. A file that
+// cannot be read or parsed is reported as a single problem.
+func validateFile(path string, kind *inputs.Kind, project *inputs.Project) []validationResult {
+	file := path
+	if path == "-" {
+		file = "stdin"
+	}
+	data, err := api.ReadInputFile(path)
+	if err != nil {
+		return []validationResult{{File: file, Document: 1, Problems: []jsonschema.Error{{Message: err.Error()}}}}
+	}
+	docs, err := api.DecodeInputAll[any](data, path)
+	if err != nil {
+		return []validationResult{{File: file, Document: 1, Problems: []jsonschema.Error{{Message: err.Error()}}}}
+	}
+
+	results := make([]validationResult, len(docs))
+	for i, doc := range docs {
+		r := validationResult{File: file, Document: i + 1, Problems: []jsonschema.Error{}}
+		var k inputs.Kind
+		if kind != nil {
+			k = *kind
+		} else if detected, ok := inputs.Detect(doc); ok {
+			k = detected
+		} else {
+			r.Problems = append(r.Problems, jsonschema.Error{Message: "cannot tell the kind of this document; use --kind"})
+			results[i] = r
+			continue
+		}
+		r.Kind = k.Name
+		r.Problems = append(r.Problems, k.Validate(doc, project)...)
+		results[i] = r
+	}
+	return results
+}
+
+// loadValidationProject fetches the audiences of the project and, when
+// featureKey is set, the variations and variables of the feature.
+func loadValidationProject(projectKey, featureKey string) (*inputs.Project, error) {
+	client, err := getClient()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	audiences, err := client.Audiences(ctx, projectKey)
+	if err != nil {
+		return nil, err
+	}
+	project := &inputs.Project{Audiences: make(map[string]bool, len(audiences)*2)}
+	for _, a := range audiences {
+		project.Audiences[a.Key] = true
+		project.Audiences[a.ID] = true
+	}
+	if featureKey == "" {
+		return project, nil
+	}
+
+	feature, err := client.FeatureV2(ctx, projectKey, featureKey)
+	if err != nil {
+		return nil, err
+	}
+	variations, err := client.Variations(ctx, projectKey, featureKey)
+	if err != nil {
+		return nil, err
+	}
+	project.Variables = feature.Variables
+	if project.Variables == nil {
+		project.Variables = []api.VariableDefinition{}
+	}
+	project.Variations = make(map[string]bool, len(variations)*2)
+	for _, v := range variations {
+		project.Variations[v.Key] = true
+		project.Variations[v.ID] = true
+	}
+	return project, nil
+}
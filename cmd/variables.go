@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/135yshr/devcycle-cli/internal/config"
+	"github.com/135yshr/devcycle-cli/internal/listfilter"
 	"github.com/135yshr/devcycle-cli/internal/output"
 	"github.com/135yshr/devcycle-cli/pkg/api"
 	"github.com/spf13/cobra"
@@ -19,8 +20,17 @@ var variablesCmd = &cobra.Command{
 var variablesListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all variables",
-	Long:  `List all variables in a project.`,
-	RunE:  runVariablesList,
+	Long: `List all variables in a project.
+
+Variables can be filtered by type, status, text and dates, and sorted. Only
+the status filter is sent to the server; the other filters are applied
+locally. --search matches the key, name or description and is treated as a regular
+expression when written as /expr/.
+
+Examples:
+  dvcx variables list --type Boolean --status active
+  dvcx variables list --search '/^checkout-/' --sort created --reverse`,
+	RunE: runVariablesList,
 }
 
 var variablesGetCmd = &cobra.Command{
@@ -61,6 +71,7 @@ var variableDescription string
 var variableType string
 var variableFeature string
 var variableForce bool
+var variableListFilter listFilterFlags
 
 func init() {
 	rootCmd.AddCommand(variablesCmd)
@@ -72,6 +83,10 @@ func init() {
 
 	variablesCmd.PersistentFlags().StringVarP(&variableProject, "project", "p", "", "project key (uses config default if not specified)")
 
+	// List command flags
+	addListFilterFlags(variablesListCmd, &variableListFilter, variableListFields)
+	addListSortFlags(variablesListCmd, &variableListFilter)
+
 	// Create command flags
 	variablesCreateCmd.Flags().StringVarP(&variableName, "name", "n", "", "variable name (required)")
 	variablesCreateCmd.Flags().StringVarP(&variableKey, "key", "k", "", "variable key (required)")
@@ -116,6 +131,10 @@ func runVariablesList(cmd *cobra.Command, args []string) error {
 	if projectKey == "" {
 		return errProjectRequired
 	}
+	filter, err := variableListFilter.options()
+	if err != nil {
+		return err
+	}
 
 	client, err := getClient()
 	if err != nil {
@@ -125,7 +144,13 @@ func runVariablesList(cmd *cobra.Command, args []string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	variables, err := client.Variables(ctx, projectKey)
+	variables, err := client.VariablesWithOptions(ctx, projectKey, &api.VariableListOptions{
+		Status: filter.Status,
+	})
+	if err != nil {
+		return err
+	}
+	variables, err = listfilter.Apply(variables, variableListFields, filter)
 	if err != nil {
 		return err
 	}
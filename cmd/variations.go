@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/135yshr/devcycle-cli/internal/inputs"
 	"github.com/135yshr/devcycle-cli/internal/output"
 	"github.com/135yshr/devcycle-cli/pkg/api"
 	"github.com/spf13/cobra"
@@ -35,16 +36,26 @@ var variationsGetCmd = &cobra.Command{
 var variationsCreateCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create a new variation",
-	Long:  `Create a new variation for a feature.`,
-	RunE:  runVariationsCreate,
+	Long: `Create a new variation for a feature.
+
+The values given with --variables are checked against the variables of the
+feature: every variable needs a value of its type that matches its
+validation schema, if it has one.
+
+Examples:
+  dvcx variations create -f dark-mode -k dim -n Dim -v '{"enabled": true, "theme": {"bg": "#333"}}'`,
+	RunE: runVariationsCreate,
 }
 
 var variationsUpdateCmd = &cobra.Command{
 	Use:   "update [variation-key]",
 	Short: "Update a variation",
-	Long:  `Update an existing variation.`,
-	Args:  cobra.ExactArgs(1),
-	RunE:  runVariationsUpdate,
+	Long: `Update an existing variation.
+
+The values given with --variables are checked against the variables of the
+feature, like for create, but variables may be left out.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runVariationsUpdate,
 }
 
 var variationsDeleteCmd = &cobra.Command{
@@ -212,6 +223,9 @@ func runVariationsCreate(cmd *cobra.Command, args []string) error {
 		if err := json.Unmarshal([]byte(variationVariables), &vars); err != nil {
 			return fmt.Errorf("invalid JSON for variables: %w", err)
 		}
+		if err := checkVariationValues(ctx, client, projectKey, vars, true); err != nil {
+			return err
+		}
 		req.Variables = vars
 	}
 
@@ -250,6 +264,9 @@ func runVariationsUpdate(cmd *cobra.Command, args []string) error {
 		if err := json.Unmarshal([]byte(variationVariables), &vars); err != nil {
 			return fmt.Errorf("invalid JSON for variables: %w", err)
 		}
+		if err := checkVariationValues(ctx, client, projectKey, vars, false); err != nil {
+			return err
+		}
 		req.Variables = vars
 	}
 
@@ -262,6 +279,25 @@ func runVariationsUpdate(cmd *cobra.Command, args []string) error {
 	return printer.Print(variation)
 }
 
+// checkVariationValues checks variable values against the variables of the
+// feature. Unless complete is set, variables without a value are allowed.
+func checkVariationValues(ctx context.Context, client *api.Client, projectKey string, values map[string]any, complete bool) error {
+	feature, err := client.FeatureV2(ctx, projectKey, variationFeature)
+	if err != nil {
+		return err
+	}
+	var errs inputs.Errors
+	if complete {
+		errs = inputs.ValidateVariationValues("", values, feature.Variables)
+	} else {
+		errs = inputs.ValidateVariableValues("", values, feature.Variables)
+	}
+	if errs != nil {
+		return fmt.Errorf("invalid variables: %w", errs)
+	}
+	return nil
+}
+
 func runVariationsDelete(cmd *cobra.Command, args []string) error {
 	projectKey := getVariationProjectKey()
 	if projectKey == "" {
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/135yshr/devcycle-cli/internal/jsondiff"
+	"github.com/135yshr/devcycle-cli/internal/output"
+	"github.com/135yshr/devcycle-cli/internal/prompt"
+	"github.com/135yshr/devcycle-cli/internal/wizard"
+	"github.com/135yshr/devcycle-cli/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+// canPrompt reports whether missing values can be asked for: stdin is an
+// interactive terminal.
+func canPrompt() bool {
+	return isTerminal(os.Stdin)
+}
+
+// newPrompter returns a prompter that reads stdin and writes to stderr, so
+// that the output of the command can still be redirected.
+func newPrompter(cmd *cobra.Command) *prompt.Prompter {
+	return prompt.New(os.Stdin, cmd.ErrOrStderr())
+}
+
+// runFeaturesCreateWizard asks for a feature, shows it and creates it
+// through the v2 API once confirmed.
+func runFeaturesCreateWizard(cmd *cobra.Command) error {
+	projectKey := getProjectKey()
+	if projectKey == "" {
+		return errProjectRequired
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	environments, err := client.Environments(ctx, projectKey)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	// Only the flags that were given are kept; --type has a default
+	answers := wizard.FeatureAnswers{Name: featureName, Key: featureKey, Description: featureDescription}
+	if cmd.Flags().Changed("type") {
+		answers.Type = featureType
+	}
+	p := newPrompter(cmd)
+	req, err := wizard.Feature(p, answers, environments)
+	if err != nil {
+		return err
+	}
+	if err := validateFeatureRequest(req); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	cmd.Println()
+	normalized, err := jsondiff.Normalize(req)
+	if err != nil {
+		return err
+	}
+	preview := output.NewPrinter(output.FormatYAML)
+	preview.SetWriter(cmd.ErrOrStderr())
+	if err := preview.Print(normalized); err != nil {
+		return err
+	}
+	if featureDryRun {
+		cmd.Println("Dry-run complete. No changes were made.")
+		return nil
+	}
+	ok, err := p.Confirm(fmt.Sprintf("Create feature '%s'?", req.Key), true)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		cmd.Println("Creation cancelled")
+		return nil
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	feature, err := client.CreateFeatureV2(ctx, projectKey, req)
+	if err != nil {
+		return err
+	}
+
+	printer := output.NewPrinter(output.ParseFormat(GetOutput()))
+	return printer.Print(feature)
+}
+
+// askAudienceCreateFlags asks for the audience create flags that are
+// missing, then for confirmation. It reports whether the audience was
+// confirmed.
+func askAudienceCreateFlags(cmd *cobra.Command) (bool, error) {
+	p := newPrompter(cmd)
+	answers, err := wizard.Audience(p, wizard.AudienceAnswers{
+		Name:        audienceName,
+		Key:         audienceKey,
+		Description: audienceDescription,
+		Where:       audienceWhere,
+	}, audienceFilters == "")
+	if err != nil {
+		return false, err
+	}
+	audienceName, audienceKey, audienceDescription, audienceWhere = answers.Name, answers.Key, answers.Description, answers.Where
+
+	return p.Confirm(fmt.Sprintf("Create audience '%s'?", audienceKey), true)
+}
+
+// askTargetingRuleFlags asks for the add-rule flags that are missing. The
+// environments of the project are offered when --environment is missing.
+func askTargetingRuleFlags(cmd *cobra.Command) error {
+	projectKey := getTargetingProjectKey()
+	if projectKey == "" {
+		return errProjectRequired
+	}
+	p := newPrompter(cmd)
+	if targetingFeature == "" {
+		feature, err := p.Ask("Feature key", "", prompt.Required)
+		if err != nil {
+			return err
+		}
+		targetingFeature = feature
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var environments []api.Environment
+	if targetingEnvironment == "" {
+		if environments, err = client.Environments(ctx, projectKey); err != nil {
+			return err
+		}
+	}
+	variations, err := client.Variations(ctx, projectKey, targetingFeature)
+	if err != nil {
+		return err
+	}
+
+	answers := wizard.RuleAnswers{Environment: targetingEnvironment, Name: targetingRuleName, Serve: targetingServe}
+	if len(targetingWhere) > 0 {
+		answers.Where = targetingWhere[0]
+	}
+	answers, err = wizard.Rule(p, answers, environments, variations)
+	if err != nil {
+		return err
+	}
+	targetingEnvironment, targetingRuleName, targetingServe = answers.Environment, answers.Name, answers.Serve
+	if len(targetingWhere) == 0 && answers.Where != "" {
+		targetingWhere = []string{answers.Where}
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+// Package bundle assembles offline SDK configuration bundles. A bundle
+// holds everything a DevCycle local-bucketing SDK needs to evaluate the
+// features of one environment, in the shape served by the DevCycle CDN.
+package bundle
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+// Config is the SDK configuration for one environment.
+type Config struct {
+	Project     Project             `json:"project"`
+	Environment Environment         `json:"environment"`
+	Features    []Feature           `json:"features"`
+	Variables   []Variable          `json:"variables"`
+	Audiences   map[string]Audience `json:"audiences"`
+}
+
+// Project identifies the project a bundle was built for.
+type Project struct {
+	ID       string          `json:"_id"`
+	Key      string          `json:"key"`
+	Settings ProjectSettings `json:"settings"`
+}
+
+// ProjectSettings are the project settings read by the SDKs.
+type ProjectSettings struct {
+	EdgeDB Toggle `json:"edgeDB"`
+	OptIn  Toggle `json:"optIn"`
+}
+
+// Toggle is an enabled flag.
+type Toggle struct {
+	Enabled bool `json:"enabled"`
+}
+
+// Environment identifies the environment a bundle was built for.
+type Environment struct {
+	ID  string `json:"_id"`
+	Key string `json:"key"`
+}
+
+// Feature is a feature that is active in the environment.
+type Feature struct {
+	ID            string        `json:"_id"`
+	Key           string        `json:"key"`
+	Type          string        `json:"type"`
+	Variations    []Variation   `json:"variations"`
+	Configuration Configuration `json:"configuration"`
+}
+
+// Variation is a feature variation with its variable values.
+type Variation struct {
+	ID        string          `json:"_id"`
+	Key       string          `json:"key"`
+	Name      string          `json:"name"`
+	Variables []VariableValue `json:"variables"`
+}
+
+// VariableValue is the value a variation serves for a variable.
+type VariableValue struct {
+	Var   string `json:"_var"`
+	Value any    `json:"value"`
+}
+
+// Configuration holds the targeting rules of a feature.
+type Configuration struct {
+	ID      string   `json:"_id"`
+	Targets []Target `json:"targets"`
+}
+
+// Target is a targeting rule.
+type Target struct {
+	ID           string             `json:"_id"`
+	Audience     Audience           `json:"_audience"`
+	Distribution []api.Distribution `json:"distribution"`
+}
+
+// Audience is a set of filters, either inline in a target or a saved
+// audience referenced by audience match filters.
+type Audience struct {
+	ID      string      `json:"_id"`
+	Filters api.Filters `json:"filters"`
+}
+
+// Variable is a variable served by the features in the bundle.
+type Variable struct {
+	ID   string `json:"_id"`
+	Key  string `json:"key"`
+	Type string `json:"type"`
+}
+
+// FeatureSource is the data fetched for one feature.
+type FeatureSource struct {
+	Feature    api.Feature
+	Variations []api.Variation
+	Config     *api.EnvironmentConfig
+}
+
+// Source is the data a bundle is built from.
+type Source struct {
+	Project     api.Project
+	Environment api.Environment
+	Features    []FeatureSource
+	Variables   []api.Variable
+	Audiences   []api.AudienceDefinition
+}
+
+// Build assembles the bundle for src. Only features that are active in the
+// environment are included, and only the variables they serve. Features,
+// variations and variables are sorted by key so bundles can be diffed.
+func Build(src Source) (*Config, error) {
+	cfg := &Config{
+		Project:     Project{ID: src.Project.ID, Key: src.Project.Key},
+		Environment: Environment{ID: src.Environment.ID, Key: src.Environment.Key},
+		Features:    []Feature{},
+		Variables:   []Variable{},
+		Audiences:   make(map[string]Audience, len(src.Audiences)),
+	}
+
+	variablesByKey := make(map[string]api.Variable, len(src.Variables))
+	for _, v := range src.Variables {
+		variablesByKey[v.Key] = v
+	}
+	for _, a := range src.Audiences {
+		cfg.Audiences[a.ID] = Audience{ID: a.ID, Filters: a.Filters}
+	}
+
+	used := make(map[string]api.Variable)
+	for _, fs := range src.Features {
+		if fs.Config == nil || fs.Config.Status != "active" {
+			continue
+		}
+		f, err := buildFeature(fs, variablesByKey, used)
+		if err != nil {
+			return nil, fmt.Errorf("feature '%s': %w", fs.Feature.Key, err)
+		}
+		cfg.Features = append(cfg.Features, f)
+	}
+	sort.Slice(cfg.Features, func(i, j int) bool {
+		return cfg.Features[i].Key < cfg.Features[j].Key
+	})
+
+	for _, v := range used {
+		cfg.Variables = append(cfg.Variables, Variable{ID: v.ID, Key: v.Key, Type: v.Type})
+	}
+	sort.Slice(cfg.Variables, func(i, j int) bool {
+		return cfg.Variables[i].Key < cfg.Variables[j].Key
+	})
+
+	return cfg, nil
+}
+
+func buildFeature(fs FeatureSource, variablesByKey map[string]api.Variable, used map[string]api.Variable) (Feature, error) {
+	f := Feature{
+		ID:            fs.Feature.ID,
+		Key:           fs.Feature.Key,
+		Type:          fs.Feature.Type,
+		Variations:    []Variation{},
+		Configuration: Configuration{ID: fs.Feature.ID, Targets: []Target{}},
+	}
+
+	variationIDs := make(map[string]string, len(fs.Variations)*2)
+	for _, v := range fs.Variations {
+		variationIDs[v.ID] = v.ID
+		variationIDs[v.Key] = v.ID
+
+		variation := Variation{ID: v.ID, Key: v.Key, Name: v.Name, Variables: []VariableValue{}}
+		keys := make([]string, 0, len(v.Variables))
+		for k := range v.Variables {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			variable, ok := variablesByKey[k]
+			if !ok {
+				return Feature{}, fmt.Errorf("variation '%s' sets unknown variable '%s'", v.Key, k)
+			}
+			used[k] = variable
+			variation.Variables = append(variation.Variables, VariableValue{Var: variable.ID, Value: v.Variables[k]})
+		}
+		f.Variations = append(f.Variations, variation)
+	}
+	sort.Slice(f.Variations, func(i, j int) bool {
+		return f.Variations[i].Key < f.Variations[j].Key
+	})
+
+	for i, t := range fs.Config.Targets {
+		id := t.ID
+		if id == "" {
+			// Bucketing is seeded by the target ID, so a missing ID is
+			// replaced with a stable one.
+			id = fmt.Sprintf("%s-target-%d", fs.Feature.ID, i)
+		}
+		target := Target{
+			ID:           id,
+			Audience:     Audience{ID: id, Filters: t.Audience.Filters},
+			Distribution: make([]api.Distribution, len(t.Distribution)),
+		}
+		for j, d := range t.Distribution {
+			vid, ok := variationIDs[d.Variation]
+			if !ok {
+				return Feature{}, fmt.Errorf("target %d serves unknown variation '%s'", i+1, d.Variation)
+			}
+			target.Distribution[j] = api.Distribution{Variation: vid, Percentage: d.Percentage}
+		}
+		f.Configuration.Targets = append(f.Configuration.Targets, target)
+	}
+	return f, nil
+}
+
+// Marshal encodes cfg as indented JSON followed by a newline.
+func Marshal(cfg *Config) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(cfg); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
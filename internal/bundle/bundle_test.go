@@ -0,0 +1,151 @@
+package bundle
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+func testSource() Source {
+	allUsers := api.Filters{Operator: "and", Filters: []api.Filter{{Type: "all"}}}
+	return Source{
+		Project:     api.Project{ID: "proj-1", Key: "my-app"},
+		Environment: api.Environment{ID: "env-1", Key: "development"},
+		Variables: []api.Variable{
+			{ID: "var-2", Key: "theme", Type: "String"},
+			{ID: "var-1", Key: "dark-mode", Type: "Boolean"},
+			{ID: "var-3", Key: "unused", Type: "Number"},
+		},
+		Audiences: []api.AudienceDefinition{{ID: "aud-1", Key: "beta", Filters: allUsers}},
+		Features: []FeatureSource{
+			{
+				Feature: api.Feature{ID: "feat-2", Key: "theme", Type: "experiment"},
+				Variations: []api.Variation{
+					{ID: "v-dark", Key: "dark", Name: "Dark", Variables: map[string]any{"theme": "dark", "dark-mode": true}},
+					{ID: "v-light", Key: "light", Name: "Light", Variables: map[string]any{"theme": "light", "dark-mode": false}},
+				},
+				Config: &api.EnvironmentConfig{Status: "active", Targets: []api.Target{
+					{ID: "t-1", Audience: api.Audience{Filters: allUsers}, Distribution: []api.Distribution{
+						{Variation: "v-dark", Percentage: 0.5},
+						{Variation: "light", Percentage: 0.5},
+					}},
+					{Audience: api.Audience{Filters: allUsers}, Distribution: []api.Distribution{{Variation: "dark", Percentage: 1}}},
+				}},
+			},
+			{
+				Feature: api.Feature{ID: "feat-1", Key: "disabled", Type: "release"},
+				Config:  &api.EnvironmentConfig{Status: "inactive"},
+			},
+		},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	cfg, err := Build(testSource())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.Project.Key != "my-app" || cfg.Environment.ID != "env-1" {
+		t.Errorf("unexpected project or environment: %+v %+v", cfg.Project, cfg.Environment)
+	}
+	if len(cfg.Features) != 1 || cfg.Features[0].Key != "theme" {
+		t.Fatalf("expected only the active feature, got %+v", cfg.Features)
+	}
+
+	f := cfg.Features[0]
+	if f.Variations[0].Key != "dark" || f.Variations[0].Variables[0].Var != "var-1" {
+		t.Errorf("expected variations and variable values sorted by key, got %+v", f.Variations)
+	}
+
+	targets := f.Configuration.Targets
+	if targets[0].Distribution[1].Variation != "v-light" {
+		t.Errorf("expected variation key to be resolved to ID, got %s", targets[0].Distribution[1].Variation)
+	}
+	if targets[1].ID != "feat-2-target-1" || targets[1].Audience.ID != targets[1].ID {
+		t.Errorf("expected a stable ID for a target without one, got %q", targets[1].ID)
+	}
+
+	if len(cfg.Variables) != 2 || cfg.Variables[0].Key != "dark-mode" || cfg.Variables[1].Key != "theme" {
+		t.Errorf("expected only used variables sorted by key, got %+v", cfg.Variables)
+	}
+	if _, ok := cfg.Audiences["aud-1"]; !ok {
+		t.Error("expected audiences to be keyed by ID")
+	}
+}
+
+func TestBuild_Errors(t *testing.T) {
+	src := testSource()
+	src.Features[0].Variations[0].Variables["missing"] = 1
+	if _, err := Build(src); err == nil || !strings.Contains(err.Error(), "unknown variable 'missing'") {
+		t.Errorf("expected unknown variable error, got %v", err)
+	}
+
+	src = testSource()
+	src.Features[0].Config.Targets[0].Distribution[0].Variation = "nope"
+	if _, err := Build(src); err == nil || !strings.Contains(err.Error(), "unknown variation 'nope'") {
+		t.Errorf("expected unknown variation error, got %v", err)
+	}
+}
+
+func TestMarshal_Deterministic(t *testing.T) {
+	a, _ := Build(testSource())
+	b, _ := Build(testSource())
+	da, err := Marshal(a)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	db, _ := Marshal(b)
+	if string(da) != string(db) {
+		t.Error("expected identical output for identical input")
+	}
+	if !strings.HasSuffix(string(da), "}\n") {
+		t.Error("expected trailing newline")
+	}
+}
+
+func TestHandler(t *testing.T) {
+	body := []byte(`{"features":[]}`)
+	h := Handler(func() ([]byte, error) { return body, nil })
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/config/v1/server/dvc_server_x.json", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != string(body) {
+		t.Fatalf("unexpected response: %d %s", rec.Code, rec.Body.String())
+	}
+	etag := rec.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("expected ETag header")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/config/v1/server/dvc_server_x.json", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("expected 304, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/config.json", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", rec.Code)
+	}
+
+	failing := Handler(func() ([]byte, error) { return nil, errors.New("boom") })
+	rec = httptest.NewRecorder()
+	failing.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/config.json", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500, got %d", rec.Code)
+	}
+}
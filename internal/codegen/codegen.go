@@ -0,0 +1,178 @@
+// Package codegen generates typed accessors for DevCycle variables, so
+// services refer to variables through constants and functions instead of
+// string keys.
+package codegen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+// Header is the first line of every generated file.
+const Header = "Code generated by dvcx generate; DO NOT EDIT."
+
+// Variable is a variable to generate accessors for.
+type Variable struct {
+	Key         string
+	Name        string
+	Type        string
+	Description string
+	Feature     string
+	Deprecated  bool
+}
+
+// FromAPI converts API variables, sorted by key. The feature that owns each
+// variable is resolved from features by ID. Archived variables are marked
+// deprecated.
+func FromAPI(variables []api.Variable, features []api.Feature) []Variable {
+	featureKeys := make(map[string]string, len(features))
+	for _, f := range features {
+		featureKeys[f.ID] = f.Key
+	}
+
+	out := make([]Variable, len(variables))
+	for i, v := range variables {
+		feature := featureKeys[v.Feature]
+		if feature == "" {
+			feature = v.Feature
+		}
+		out[i] = Variable{
+			Key:         v.Key,
+			Name:        v.Name,
+			Type:        v.Type,
+			Description: v.Description,
+			Feature:     feature,
+			Deprecated:  v.Status == "archived",
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out
+}
+
+// reserved holds identifiers used by the generated files themselves.
+var reserved = map[string]bool{"Client": true, "VariableKeys": true, "VariableKey": true, "VariableTypes": true, "VariableClient": true}
+
+// identifiers returns an exported identifier for each variable key. The Go
+// generator also declares <identifier>Key, so an identifier is only used
+// when neither form clashes with an earlier one; otherwise a numeric suffix
+// is added.
+func identifiers(vars []Variable) []string {
+	ids := make([]string, len(vars))
+	taken := make(map[string]bool)
+	for i, v := range vars {
+		base := pascalCase(v.Key)
+		id := base
+		for n := 2; taken[id] || taken[id+"Key"] || reserved[id]; n++ {
+			id = fmt.Sprintf("%s%d", base, n)
+		}
+		taken[id] = true
+		taken[id+"Key"] = true
+		ids[i] = id
+	}
+	return ids
+}
+
+// pascalCase converts a variable key such as "new-checkout.enabled" to
+// "NewCheckoutEnabled". Keys that start with a digit are prefixed with V.
+func pascalCase(key string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range key {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	s := b.String()
+	if s == "" || unicode.IsDigit(rune(s[0])) {
+		s = "V" + s
+	}
+	return s
+}
+
+// tsReserved holds TypeScript reserved words that cannot name a function.
+var tsReserved = map[string]bool{
+	"break": true, "case": true, "catch": true, "class": true, "const": true, "continue": true,
+	"debugger": true, "default": true, "delete": true, "do": true, "else": true, "enum": true,
+	"export": true, "extends": true, "false": true, "finally": true, "for": true, "function": true,
+	"if": true, "import": true, "in": true, "instanceof": true, "new": true, "null": true,
+	"return": true, "super": true, "switch": true, "this": true, "throw": true, "true": true,
+	"try": true, "typeof": true, "var": true, "void": true, "while": true, "with": true,
+	"let": true, "static": true, "yield": true, "await": true, "implements": true,
+	"interface": true, "package": true, "private": true, "protected": true, "public": true,
+}
+
+// camelCase converts an identifier returned by identifiers to lower camel
+// case for use as a TypeScript function name.
+func camelCase(id string) string {
+	s := strings.ToLower(id[:1]) + id[1:]
+	if tsReserved[s] {
+		s += "Value"
+	}
+	return s
+}
+
+// docLines returns the documentation for v, one line per element.
+func docLines(v Variable) []string {
+	var lines []string
+	summary := fmt.Sprintf("the %q variable (%s)", v.Key, v.Type)
+	if v.Feature != "" {
+		summary += fmt.Sprintf(" of feature %q", v.Feature)
+	}
+	lines = append(lines, summary+".")
+	if desc := strings.TrimSpace(v.Description); desc != "" {
+		lines = append(lines, "")
+		lines = append(lines, strings.Split(desc, "\n")...)
+	}
+	return lines
+}
+
+// File is a generated file.
+type File struct {
+	Path    string
+	Content []byte
+}
+
+// Write writes each file, creating parent directories as needed.
+func Write(files []File) error {
+	for _, f := range files {
+		if err := os.MkdirAll(filepath.Dir(f.Path), 0755); err != nil {
+			return fmt.Errorf("failed to create directory for %s: %w", f.Path, err)
+		}
+		if err := os.WriteFile(f.Path, f.Content, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.Path, err)
+		}
+	}
+	return nil
+}
+
+// Stale returns the paths of files whose content on disk differs from the
+// generated content, including files that do not exist.
+func Stale(files []File) ([]string, error) {
+	var stale []string
+	for _, f := range files {
+		data, err := os.ReadFile(f.Path)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			stale = append(stale, f.Path)
+		case err != nil:
+			return nil, fmt.Errorf("failed to read %s: %w", f.Path, err)
+		case !bytes.Equal(data, f.Content):
+			stale = append(stale, f.Path)
+		}
+	}
+	return stale, nil
+}
@@ -0,0 +1,156 @@
+package codegen
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+func testVariables() []Variable {
+	return FromAPI([]api.Variable{
+		{Key: "new-checkout.enabled", Type: "Boolean", Feature: "feat-1", Description: "Turns on the new checkout.\nRemove after Q3."},
+		{Key: "checkout-theme", Type: "String", Feature: "feat-1"},
+		{Key: "max-items", Type: "Number", Status: "archived"},
+		{Key: "limits", Type: "JSON"},
+		{Key: "2fa", Type: "Boolean"},
+		{Key: "delete", Type: "String"},
+	}, []api.Feature{{ID: "feat-1", Key: "new-checkout"}})
+}
+
+func TestFromAPI(t *testing.T) {
+	vars := testVariables()
+	var keys []string
+	for _, v := range vars {
+		keys = append(keys, v.Key)
+	}
+	want := []string{"2fa", "checkout-theme", "delete", "limits", "max-items", "new-checkout.enabled"}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("expected %v, got %v", want, keys)
+	}
+	if vars[1].Feature != "new-checkout" {
+		t.Errorf("expected feature key to be resolved, got %q", vars[1].Feature)
+	}
+	if !vars[4].Deprecated {
+		t.Error("expected archived variable to be deprecated")
+	}
+}
+
+func TestIdentifiers(t *testing.T) {
+	vars := []Variable{{Key: "dark-mode"}, {Key: "dark_mode"}, {Key: "dark-mode-key"}, {Key: "client"}, {Key: "9lives"}}
+	want := []string{"DarkMode", "DarkMode2", "DarkModeKey2", "Client2", "V9lives"}
+	if got := identifiers(vars); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestGo(t *testing.T) {
+	src, err := Go("flags", testVariables())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	code := string(src)
+
+	for _, want := range []string{
+		"// " + Header,
+		`NewCheckoutEnabledKey = "new-checkout.enabled"`,
+		`// NewCheckoutEnabled returns the "new-checkout.enabled" variable (Boolean) of feature "new-checkout".`,
+		"// Remove after Q3.",
+		"// Deprecated: the variable is archived.",
+		"func MaxItems[U any](c Client[U], user U, defaultValue float64) float64 {",
+		"func Limits[U any](c Client[U], user U, defaultValue map[string]any) map[string]any {",
+		"func V2fa[U any]",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("expected generated code to contain %q\n%s", want, code)
+		}
+	}
+
+	// The generated code must type-check together with a caller that uses a
+	// concrete client type.
+	usage := `package flags
+
+type user struct{ id string }
+
+type sdk struct{}
+
+func (sdk) VariableValue(u user, key string, defaultValue any) (any, error) { return true, nil }
+
+var _ bool = NewCheckoutEnabled(sdk{}, user{}, false)
+`
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for name, content := range map[string]string{"flags.go": code, "usage.go": usage} {
+		f, err := parser.ParseFile(fset, name, content, parser.ParseComments)
+		if err != nil {
+			t.Fatalf("failed to parse %s: %v", name, err)
+		}
+		files = append(files, f)
+	}
+	if _, err := (&types.Config{}).Check("flags", fset, files, nil); err != nil {
+		t.Errorf("generated code does not type-check: %v", err)
+	}
+
+	if _, err := Go("my-flags", nil); err == nil {
+		t.Error("expected error for invalid package name")
+	}
+}
+
+func TestTypeScript(t *testing.T) {
+	src, err := TypeScript(testVariables())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	code := string(src)
+
+	for _, want := range []string{
+		"// " + Header,
+		"  NewCheckoutEnabled: 'new-checkout.enabled',",
+		"  'limits': Record<string, unknown>",
+		"  /** @deprecated The variable is archived. */\n  MaxItems: 'max-items',",
+		" * @deprecated The variable is archived.",
+		"export function newCheckoutEnabled<U>(client: VariableClient<U>, user: U, defaultValue: boolean): boolean {",
+		"  return client.variableValue(user, VariableKeys.CheckoutTheme, defaultValue) as string",
+		"export function deleteValue<U>(",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("expected generated code to contain %q\n%s", want, code)
+		}
+	}
+}
+
+func TestStale(t *testing.T) {
+	dir := t.TempDir()
+	current := File{Path: filepath.Join(dir, "a.go"), Content: []byte("a")}
+	changed := File{Path: filepath.Join(dir, "b.go"), Content: []byte("new")}
+	missing := File{Path: filepath.Join(dir, "sub", "c.go"), Content: []byte("c")}
+
+	if err := Write([]File{current, {Path: changed.Path, Content: []byte("old")}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stale, err := Stale([]File{current, changed, missing})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(stale, []string{changed.Path, missing.Path}) {
+		t.Errorf("unexpected stale files: %v", stale)
+	}
+
+	if err := Write([]File{changed, missing}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(missing.Path); err != nil {
+		t.Errorf("expected file to be created: %v", err)
+	}
+	if stale, _ := Stale([]File{current, changed, missing}); len(stale) != 0 {
+		t.Errorf("expected no stale files after writing, got %v", stale)
+	}
+}
@@ -0,0 +1,86 @@
+package codegen
+
+import (
+	"fmt"
+	"go/format"
+	"go/token"
+	"strings"
+)
+
+var goTypes = map[string]string{
+	"String":  "string",
+	"Boolean": "bool",
+	"Number":  "float64",
+	"JSON":    "map[string]any",
+}
+
+// Go returns a Go source file in package pkg with a key constant and a
+// typed accessor for each variable. The accessors work with any client
+// that has a VariableValue method, such as the DevCycle Go server SDK.
+func Go(pkg string, vars []Variable) ([]byte, error) {
+	if !token.IsIdentifier(pkg) {
+		return nil, fmt.Errorf("invalid Go package name %q", pkg)
+	}
+
+	ids := identifiers(vars)
+	var b strings.Builder
+	fmt.Fprintf(&b, "// %s\n\n", Header)
+	fmt.Fprintf(&b, "// Package %s provides typed accessors for DevCycle variables.\n", pkg)
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+
+	if len(vars) > 0 {
+		b.WriteString("// Variable keys.\nconst (\n")
+		for i, v := range vars {
+			fmt.Fprintf(&b, "\t// %sKey is the key of the %q variable.\n", ids[i], v.Key)
+			if v.Deprecated {
+				b.WriteString("\t//\n\t// Deprecated: the variable is archived.\n")
+			}
+			fmt.Fprintf(&b, "\t%sKey = %q\n", ids[i], v.Key)
+		}
+		b.WriteString(")\n\n")
+	}
+
+	b.WriteString(`// Client is implemented by DevCycle SDK clients. U is the SDK's user type.
+type Client[U any] interface {
+	VariableValue(user U, key string, defaultValue any) (any, error)
+}
+`)
+
+	for i, v := range vars {
+		goType, ok := goTypes[v.Type]
+		if !ok {
+			goType = "any"
+		}
+		b.WriteString("\n")
+		for j, line := range docLines(v) {
+			if j == 0 {
+				line = fmt.Sprintf("%s returns %s", ids[i], line)
+			}
+			writeGoComment(&b, line)
+		}
+		writeGoComment(&b, "defaultValue is returned when the variable cannot be evaluated.")
+		if v.Deprecated {
+			writeGoComment(&b, "")
+			writeGoComment(&b, "Deprecated: the variable is archived.")
+		}
+		fmt.Fprintf(&b, "func %s[U any](c Client[U], user U, defaultValue %s) %s {\n", ids[i], goType, goType)
+		fmt.Fprintf(&b, "\tv, err := c.VariableValue(user, %sKey, defaultValue)\n", ids[i])
+		b.WriteString("\tif err != nil {\n\t\treturn defaultValue\n\t}\n")
+		fmt.Fprintf(&b, "\tvalue, ok := v.(%s)\n", goType)
+		b.WriteString("\tif !ok {\n\t\treturn defaultValue\n\t}\n\treturn value\n}\n")
+	}
+
+	src, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated Go code: %w", err)
+	}
+	return src, nil
+}
+
+func writeGoComment(b *strings.Builder, line string) {
+	if line == "" {
+		b.WriteString("//\n")
+		return
+	}
+	b.WriteString("// " + line + "\n")
+}
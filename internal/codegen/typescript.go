@@ -0,0 +1,89 @@
+package codegen
+
+import (
+	"fmt"
+	"strings"
+)
+
+var tsTypes = map[string]string{
+	"String":  "string",
+	"Boolean": "boolean",
+	"Number":  "number",
+	"JSON":    "Record<string, unknown>",
+}
+
+// TypeScript returns a TypeScript module with a key map, a key-to-type
+// interface and a typed accessor for each variable. The accessors work
+// with any client that has a variableValue(user, key, default) method,
+// such as the DevCycle Node.js server SDK.
+func TypeScript(vars []Variable) ([]byte, error) {
+	ids := identifiers(vars)
+	var b strings.Builder
+	fmt.Fprintf(&b, "// %s\n\n", Header)
+
+	b.WriteString("export const VariableKeys = {\n")
+	for i, v := range vars {
+		if v.Deprecated {
+			b.WriteString("  /** @deprecated The variable is archived. */\n")
+		}
+		fmt.Fprintf(&b, "  %s: %s,\n", ids[i], tsString(v.Key))
+	}
+	b.WriteString("} as const\n\n")
+	b.WriteString("export type VariableKey = (typeof VariableKeys)[keyof typeof VariableKeys]\n\n")
+
+	b.WriteString("export interface VariableTypes {\n")
+	for _, v := range vars {
+		fmt.Fprintf(&b, "  %s: %s\n", tsString(v.Key), tsType(v.Type))
+	}
+	b.WriteString("}\n\n")
+
+	b.WriteString(`/** Implemented by DevCycle SDK clients. U is the SDK's user type. */
+export interface VariableClient<U> {
+  variableValue(user: U, key: string, defaultValue: any): any
+}
+`)
+
+	for i, v := range vars {
+		b.WriteString("\n/**\n")
+		for j, line := range docLines(v) {
+			if j == 0 {
+				line = "Returns " + line
+			}
+			writeTSComment(&b, line)
+		}
+		writeTSComment(&b, "`defaultValue` is returned when the variable cannot be evaluated.")
+		if v.Deprecated {
+			writeTSComment(&b, "@deprecated The variable is archived.")
+		}
+		b.WriteString(" */\n")
+		typ := tsType(v.Type)
+		fmt.Fprintf(&b, "export function %s<U>(client: VariableClient<U>, user: U, defaultValue: %s): %s {\n", camelCase(ids[i]), typ, typ)
+		fmt.Fprintf(&b, "  return client.variableValue(user, VariableKeys.%s, defaultValue) as %s\n", ids[i], typ)
+		b.WriteString("}\n")
+	}
+
+	return []byte(b.String()), nil
+}
+
+func tsType(t string) string {
+	if typ, ok := tsTypes[t]; ok {
+		return typ
+	}
+	return "unknown"
+}
+
+// tsString quotes s as a single-quoted TypeScript string literal.
+func tsString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `'`, `\'`)
+	return "'" + s + "'"
+}
+
+func writeTSComment(b *strings.Builder, line string) {
+	line = strings.ReplaceAll(line, "*/", "*\\/")
+	if line == "" {
+		b.WriteString(" *\n")
+		return
+	}
+	b.WriteString(" * " + line + "\n")
+}
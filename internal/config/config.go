@@ -93,3 +93,23 @@ func ClientSecret() string {
 func Debug() bool {
 	return viper.GetBool("debug")
 }
+
+// UsagePattern is a user-defined pattern for the usages scanner. Pattern
+// is a regular expression whose first capture group is the variable key.
+type UsagePattern struct {
+	Lang    string `mapstructure:"lang"`
+	Pattern string `mapstructure:"pattern"`
+}
+
+// UsagesConfig configures the usages scanner.
+type UsagesConfig struct {
+	Patterns []UsagePattern `mapstructure:"patterns"`
+	Exclude  []string       `mapstructure:"exclude"`
+}
+
+// Usages returns the usages section of the config file.
+func Usages() UsagesConfig {
+	var u UsagesConfig
+	_ = viper.UnmarshalKey("usages", &u)
+	return u
+}
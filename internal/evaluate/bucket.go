@@ -0,0 +1,91 @@
+package evaluate
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"sort"
+
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+// baseSeed is the seed DevCycle uses to hash target IDs.
+const baseSeed = 1
+
+// Murmur3 returns the 32-bit MurmurHash3 (x86) of key with the given seed.
+func Murmur3(key string, seed uint32) uint32 {
+	const (
+		c1 = 0xcc9e2d51
+		c2 = 0x1b873593
+	)
+
+	data := []byte(key)
+	h := seed
+	n := len(data) / 4
+	for i := 0; i < n; i++ {
+		k := uint32(data[i*4]) | uint32(data[i*4+1])<<8 | uint32(data[i*4+2])<<16 | uint32(data[i*4+3])<<24
+		k *= c1
+		k = bits.RotateLeft32(k, 15)
+		k *= c2
+		h ^= k
+		h = bits.RotateLeft32(h, 13)
+		h = h*5 + 0xe6546b64
+	}
+
+	var k uint32
+	tail := data[n*4:]
+	switch len(tail) {
+	case 3:
+		k ^= uint32(tail[2]) << 16
+		fallthrough
+	case 2:
+		k ^= uint32(tail[1]) << 8
+		fallthrough
+	case 1:
+		k ^= uint32(tail[0])
+		k *= c1
+		k = bits.RotateLeft32(k, 15)
+		k *= c2
+		h ^= k
+	}
+
+	h ^= uint32(len(data))
+	h ^= h >> 16
+	h *= 0x85ebca6b
+	h ^= h >> 13
+	h *= 0xc2b2ae35
+	h ^= h >> 16
+	return h
+}
+
+// Bucket returns the position of a user within a target as a value in
+// [0, 1], computed the same way as the DevCycle SDKs: the user ID is hashed
+// with the hash of the target ID as seed.
+func Bucket(userID, targetID string) float64 {
+	targetHash := Murmur3(targetID, baseSeed)
+	return float64(Murmur3(userID, targetHash)) / math.MaxUint32
+}
+
+// ChooseVariation returns the variation reference of the distribution slot
+// containing bucket. Slots are laid out in order of variation reference.
+func ChooseVariation(dist []api.Distribution, bucket float64) (string, error) {
+	sorted := make([]api.Distribution, len(dist))
+	copy(sorted, dist)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Variation < sorted[j].Variation
+	})
+
+	var upper float64
+	for _, d := range sorted {
+		upper += d.Percentage
+		if bucket < upper {
+			return d.Variation, nil
+		}
+	}
+	// A bucket of exactly 1, or one lost to rounding in the percentages,
+	// belongs to the last slot of a full distribution.
+	if len(sorted) > 0 && bucket <= upper+1e-9 {
+		return sorted[len(sorted)-1].Variation, nil
+	}
+	return "", fmt.Errorf("bucket %.4f is outside the distribution (total %.4f)", bucket, upper)
+}
@@ -0,0 +1,129 @@
+// Package evaluate simulates DevCycle feature evaluation locally. It walks
+// the targeting rules of an environment in order, applies the audience
+// filters to a user and buckets the user into a variation the same way the
+// DevCycle SDKs do.
+package evaluate
+
+import (
+	"fmt"
+
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+// Step records how a single targeting rule was evaluated.
+type Step struct {
+	Index   int     `json:"index"`
+	Rule    string  `json:"rule"`
+	Matched bool    `json:"matched"`
+	Checks  []Check `json:"checks,omitempty"`
+}
+
+// Result is the outcome of evaluating a feature for a user.
+type Result struct {
+	UserID        string         `json:"userId"`
+	Status        string         `json:"status"`
+	Rule          string         `json:"rule,omitempty"`
+	RuleIndex     int            `json:"ruleIndex,omitempty"`
+	Bucket        float64        `json:"bucket,omitempty"`
+	Variation     string         `json:"variation,omitempty"`
+	VariationName string         `json:"variationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+	Steps         []Step         `json:"steps"`
+	Reason        string         `json:"reason"`
+}
+
+// Served reports whether a variation is served to the user.
+func (r *Result) Served() bool {
+	return r.Variation != ""
+}
+
+// Evaluator evaluates feature configurations against users.
+type Evaluator struct {
+	variations map[string]api.Variation
+	audiences  map[string]api.AudienceDefinition
+}
+
+// New returns an Evaluator for a feature with the given variations. The
+// audiences are used to resolve audience(...) filters, which may reference
+// an audience by ID or key.
+func New(variations []api.Variation, audiences []api.AudienceDefinition) *Evaluator {
+	e := &Evaluator{
+		variations: make(map[string]api.Variation, len(variations)*2),
+		audiences:  make(map[string]api.AudienceDefinition, len(audiences)*2),
+	}
+	for _, v := range variations {
+		e.variations[v.ID] = v
+		e.variations[v.Key] = v
+	}
+	for _, a := range audiences {
+		e.audiences[a.ID] = a
+		e.audiences[a.Key] = a
+	}
+	return e
+}
+
+// Evaluate determines what cfg serves to user. Rules are tried in order and
+// the first rule whose audience matches decides the variation.
+func (e *Evaluator) Evaluate(cfg *api.EnvironmentConfig, user User) (*Result, error) {
+	if user.UserID == "" {
+		return nil, fmt.Errorf("user_id is required")
+	}
+
+	result := &Result{UserID: user.UserID, Steps: []Step{}}
+	if cfg == nil {
+		result.Status = "inactive"
+		result.Reason = "feature is not configured for this environment"
+		return result, nil
+	}
+	result.Status = cfg.Status
+	if cfg.Status != "active" {
+		result.Reason = "feature is disabled in this environment"
+		return result, nil
+	}
+
+	for i, t := range cfg.Targets {
+		m := &matcher{user: user, audiences: e.audiences}
+		matched := m.matchFilters(t.Audience.Filters)
+		result.Steps = append(result.Steps, Step{
+			Index:   i + 1,
+			Rule:    ruleName(t, i),
+			Matched: matched,
+			Checks:  m.checks,
+		})
+		if !matched {
+			continue
+		}
+
+		result.Rule = ruleName(t, i)
+		result.RuleIndex = i + 1
+		result.Bucket = Bucket(user.UserID, t.ID)
+		ref, err := ChooseVariation(t.Distribution, result.Bucket)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %w", result.Rule, err)
+		}
+
+		v, ok := e.variations[ref]
+		if !ok {
+			return nil, fmt.Errorf("rule %q serves unknown variation %q", result.Rule, ref)
+		}
+		result.Variation = v.Key
+		result.VariationName = v.Name
+		result.Variables = v.Variables
+		result.Reason = fmt.Sprintf("matched rule %d %q", i+1, result.Rule)
+		return result, nil
+	}
+
+	result.Reason = "no targeting rule matched"
+	return result, nil
+}
+
+func ruleName(t api.Target, index int) string {
+	switch {
+	case t.Name != "":
+		return t.Name
+	case t.Audience.Name != "":
+		return t.Audience.Name
+	default:
+		return fmt.Sprintf("rule %d", index+1)
+	}
+}
@@ -0,0 +1,234 @@
+package evaluate
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+func TestMurmur3(t *testing.T) {
+	tests := []struct {
+		key  string
+		seed uint32
+		want uint32
+	}{
+		{"", 0, 0},
+		{"", 1, 0x514e28b7},
+		{"hello", 0, 0x248bfa47},
+		{"Hello, world!", 1234, 0xfaf6cdb3},
+		{"The quick brown fox jumps over the lazy dog", 0x9747b28c, 0x2fa826cd},
+	}
+	for _, tt := range tests {
+		if got := Murmur3(tt.key, tt.seed); got != tt.want {
+			t.Errorf("Murmur3(%q, %d) = %#x, want %#x", tt.key, tt.seed, got, tt.want)
+		}
+	}
+}
+
+func TestBucket(t *testing.T) {
+	b := Bucket("user-1", "target-1")
+	if b < 0 || b > 1 {
+		t.Fatalf("expected bucket in [0, 1], got %f", b)
+	}
+	if Bucket("user-1", "target-1") != b {
+		t.Error("expected bucketing to be deterministic")
+	}
+	if Bucket("user-1", "target-2") == b {
+		t.Error("expected different targets to bucket differently")
+	}
+}
+
+func TestChooseVariation(t *testing.T) {
+	dist := []api.Distribution{
+		{Variation: "var-b", Percentage: 0.7},
+		{Variation: "var-a", Percentage: 0.3},
+	}
+	tests := []struct {
+		bucket float64
+		want   string
+	}{
+		{0, "var-a"},
+		{0.29, "var-a"},
+		{0.3, "var-b"},
+		{0.99, "var-b"},
+		{1, "var-b"},
+	}
+	for _, tt := range tests {
+		got, err := ChooseVariation(dist, tt.bucket)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("bucket %.2f: expected %s, got %s", tt.bucket, tt.want, got)
+		}
+	}
+
+	if _, err := ChooseVariation([]api.Distribution{{Variation: "a", Percentage: 0.5}}, 0.7); err == nil {
+		t.Error("expected error for bucket outside a partial distribution")
+	}
+}
+
+func userFilter(subType, comparator string, values ...any) api.Filter {
+	return api.Filter{Type: "user", SubType: subType, Comparator: comparator, Values: values}
+}
+
+func customFilter(key, dataType, comparator string, values ...any) api.Filter {
+	return api.Filter{Type: "user", SubType: "customData", DataKey: key, DataKeyType: dataType, Comparator: comparator, Values: values}
+}
+
+func TestCompare(t *testing.T) {
+	user := User{
+		UserID:     "u1",
+		Email:      "jane@acme.com",
+		Country:    "CA",
+		AppVersion: "2.10.1",
+		CustomData: map[string]any{"plan": "pro", "seats": float64(12), "beta": true},
+	}
+
+	tests := []struct {
+		name   string
+		filter api.Filter
+		want   bool
+	}{
+		{"email endsWith", userFilter("email", "endWith", "@acme.com"), true},
+		{"email !endWith", userFilter("email", "!endWith", "@acme.com"), false},
+		{"country in", userFilter("country", "=", "US", "CA"), true},
+		{"country not in", userFilter("country", "!=", "US", "CA"), false},
+		{"contains", userFilter("email", "contain", "jane"), true},
+		{"exists", userFilter("email", "exist"), true},
+		{"missing platform", userFilter("platform", "=", "iOS"), false},
+		{"missing platform negated", userFilter("platform", "!=", "iOS"), true},
+		{"missing platform !exist", userFilter("platform", "!exist"), true},
+		{"version greater", userFilter("appVersion", ">", "2.9"), true},
+		{"version less", userFilter("appVersion", "<", "2.9"), false},
+		{"custom string", customFilter("plan", "String", "=", "pro"), true},
+		{"custom number", customFilter("seats", "Number", ">=", float64(10)), true},
+		{"custom number !=", customFilter("seats", "Number", "!=", float64(12)), false},
+		{"custom bool", customFilter("beta", "Boolean", "=", true), true},
+		{"custom missing", customFilter("tier", "String", "=", "gold"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, ok := user.field(tt.filter.SubType, tt.filter.DataKey)
+			if got := compare(tt.filter, actual, ok); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	variations := []api.Variation{
+		{ID: "var-on", Key: "on", Name: "On", Variables: map[string]any{"dark-mode": true}},
+		{ID: "var-off", Key: "off", Name: "Off", Variables: map[string]any{"dark-mode": false}},
+	}
+	audiences := []api.AudienceDefinition{
+		{ID: "aud-beta", Key: "beta", Filters: api.Filters{Operator: "and", Filters: []api.Filter{
+			customFilter("beta", "Boolean", "=", true),
+		}}},
+	}
+	cfg := &api.EnvironmentConfig{
+		Status: "active",
+		Targets: []api.Target{
+			{
+				ID:   "t1",
+				Name: "Acme",
+				Audience: api.Audience{Filters: api.Filters{Operator: "and", Filters: []api.Filter{
+					userFilter("email", "endWith", "@acme.com"),
+				}}},
+				Distribution: []api.Distribution{{Variation: "var-on", Percentage: 1}},
+			},
+			{
+				ID:   "t2",
+				Name: "Beta",
+				Audience: api.Audience{Filters: api.Filters{Operator: "and", Filters: []api.Filter{
+					{Type: "audienceMatch", Comparator: "=", Audiences: []string{"aud-beta"}},
+				}}},
+				Distribution: []api.Distribution{{Variation: "on", Percentage: 1}},
+			},
+			{
+				ID:           "t3",
+				Name:         "All Users",
+				Audience:     api.Audience{Filters: api.Filters{Operator: "and", Filters: []api.Filter{{Type: "all"}}}},
+				Distribution: []api.Distribution{{Variation: "var-off", Percentage: 1}},
+			},
+		},
+	}
+	e := New(variations, audiences)
+
+	t.Run("first matching rule wins", func(t *testing.T) {
+		r, err := e.Evaluate(cfg, User{UserID: "u1", Email: "jane@acme.com"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if r.RuleIndex != 1 || r.Variation != "on" || r.Variables["dark-mode"] != true {
+			t.Errorf("unexpected result: %+v", r)
+		}
+		if len(r.Steps) != 1 {
+			t.Errorf("expected evaluation to stop at the first match, got %d steps", len(r.Steps))
+		}
+	})
+
+	t.Run("audience match", func(t *testing.T) {
+		r, err := e.Evaluate(cfg, User{UserID: "u2", Email: "bob@example.com", CustomData: map[string]any{"beta": true}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if r.Rule != "Beta" || r.Variation != "on" {
+			t.Errorf("unexpected result: %+v", r)
+		}
+		if note := r.Steps[1].Checks[0].Note; note != "member of beta" {
+			t.Errorf("unexpected note %q", note)
+		}
+	})
+
+	t.Run("falls through to all users", func(t *testing.T) {
+		r, err := e.Evaluate(cfg, User{UserID: "u3"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if r.Rule != "All Users" || r.Variation != "off" || len(r.Steps) != 3 {
+			t.Errorf("unexpected result: %+v", r)
+		}
+
+		var buf bytes.Buffer
+		r.WriteText(&buf, false)
+		for _, want := range []string{`Rule 1 "Acme": no match`, `FAIL user.email endsWith "@acme.com" (actual: (not set))`, "Variation: off (Off)", "dark-mode = false"} {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("expected output to contain %q, got:\n%s", want, buf.String())
+			}
+		}
+	})
+
+	t.Run("inactive", func(t *testing.T) {
+		r, err := e.Evaluate(&api.EnvironmentConfig{Status: "inactive", Targets: cfg.Targets}, User{UserID: "u1"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if r.Served() || len(r.Steps) != 0 {
+			t.Errorf("expected nothing served, got %+v", r)
+		}
+	})
+
+	t.Run("missing user id", func(t *testing.T) {
+		if _, err := e.Evaluate(cfg, User{}); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func TestParseUser(t *testing.T) {
+	u, err := ParseUser([]byte(`{"user_id":"u1","email":"a@b.c","customData":{"seats":3}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if u.UserID != "u1" || u.CustomData["seats"] != float64(3) {
+		t.Errorf("unexpected user: %+v", u)
+	}
+
+	if _, err := ParseUser([]byte(`{"email":"a@b.c"}`)); err == nil {
+		t.Error("expected error for missing user_id")
+	}
+}
@@ -0,0 +1,320 @@
+package evaluate
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/135yshr/devcycle-cli/internal/filterexpr"
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+// maxAudienceDepth bounds how deeply audiences may reference each other.
+const maxAudienceDepth = 10
+
+// Check records the outcome of a single filter.
+type Check struct {
+	Condition string `json:"condition"`
+	Actual    string `json:"actual,omitempty"`
+	Matched   bool   `json:"matched"`
+	Note      string `json:"note,omitempty"`
+}
+
+// matcher evaluates filters for one user and records each check.
+type matcher struct {
+	user      User
+	audiences map[string]api.AudienceDefinition
+	checks    []Check
+	depth     int
+}
+
+func (m *matcher) matchFilters(filters api.Filters) bool {
+	return m.matchGroup(filters.Operator, filters.Filters)
+}
+
+func (m *matcher) matchGroup(op string, filters []api.Filter) bool {
+	if len(filters) == 0 {
+		return false
+	}
+	if op == "or" {
+		for _, f := range filters {
+			if m.matchFilter(f) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, f := range filters {
+		if !m.matchFilter(f) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *matcher) matchFilter(f api.Filter) bool {
+	if f.Type == "" && f.Operator != "" {
+		return m.matchGroup(f.Operator, f.Filters)
+	}
+
+	check := Check{Condition: describe(f)}
+	switch f.Type {
+	case "all":
+		check.Matched = true
+	case "optIn":
+		check.Note = "opt-in status is not known locally; treated as not opted in"
+	case "audienceMatch":
+		check.Matched, check.Note = m.matchAudiences(f)
+	case "user":
+		actual, ok := m.user.field(f.SubType, f.DataKey)
+		if ok {
+			check.Actual = formatActual(actual)
+		} else {
+			check.Actual = "(not set)"
+		}
+		check.Matched = compare(f, actual, ok)
+	default:
+		check.Note = fmt.Sprintf("unsupported filter type %q", f.Type)
+	}
+	m.checks = append(m.checks, check)
+	return check.Matched
+}
+
+// matchAudiences evaluates the filters of each referenced audience. With
+// the = comparator the user must match any of them, with != none of them.
+func (m *matcher) matchAudiences(f api.Filter) (bool, string) {
+	if m.depth >= maxAudienceDepth {
+		return false, "audience references are nested too deeply"
+	}
+
+	var matched []string
+	for _, ref := range f.Audiences {
+		audience, ok := m.audiences[ref]
+		if !ok {
+			return false, fmt.Sprintf("audience %q not found", ref)
+		}
+		nested := &matcher{user: m.user, audiences: m.audiences, depth: m.depth + 1}
+		if nested.matchFilters(audience.Filters) {
+			matched = append(matched, audience.Key)
+		}
+	}
+
+	note := "not a member"
+	if len(matched) > 0 {
+		note = "member of " + strings.Join(matched, ", ")
+	}
+	if f.Comparator == "!=" {
+		return len(matched) == 0, note
+	}
+	return len(matched) > 0, note
+}
+
+func describe(f api.Filter) string {
+	return filterexpr.Format(api.Filters{Operator: "and", Filters: []api.Filter{f}})
+}
+
+func formatActual(v any) string {
+	switch x := v.(type) {
+	case string:
+		return strconv.Quote(x)
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	default:
+		return fmt.Sprint(x)
+	}
+}
+
+// compare applies the comparator of f to the user's value. Negative
+// comparators match when the value is not set.
+func compare(f api.Filter, actual any, present bool) bool {
+	switch f.Comparator {
+	case "exist":
+		return present
+	case "!exist":
+		return !present
+	}
+	if !present {
+		switch f.Comparator {
+		case "!=", "!contain", "!startWith", "!endWith":
+			return true
+		}
+		return false
+	}
+
+	values := filterValues(f.Values)
+	switch v := actual.(type) {
+	case string:
+		if isVersion(f.SubType) && isOrdering(f.Comparator) {
+			return anyValue(values, func(want any) bool {
+				return ordered(f.Comparator, compareVersions(v, fmt.Sprint(want)))
+			})
+		}
+		if n, err := strconv.ParseFloat(v, 64); err == nil && isOrdering(f.Comparator) {
+			return compareNumber(f.Comparator, n, values)
+		}
+		return compareString(f.Comparator, v, values)
+	case float64:
+		return compareNumber(f.Comparator, v, values)
+	case bool:
+		eq := anyValue(values, func(want any) bool {
+			b, ok := want.(bool)
+			if !ok {
+				b, _ = strconv.ParseBool(fmt.Sprint(want))
+			}
+			return b == v
+		})
+		switch f.Comparator {
+		case "=":
+			return eq
+		case "!=":
+			return !eq
+		}
+		return false
+	default:
+		return compareString(f.Comparator, fmt.Sprint(v), values)
+	}
+}
+
+func compareString(comparator, actual string, values []any) bool {
+	has := func(fn func(a, b string) bool) bool {
+		return anyValue(values, func(want any) bool { return fn(actual, fmt.Sprint(want)) })
+	}
+	equal := func(a, b string) bool { return a == b }
+
+	switch comparator {
+	case "=":
+		return has(equal)
+	case "!=":
+		return !has(equal)
+	case "contain":
+		return has(strings.Contains)
+	case "!contain":
+		return !has(strings.Contains)
+	case "startWith":
+		return has(strings.HasPrefix)
+	case "!startWith":
+		return !has(strings.HasPrefix)
+	case "endWith":
+		return has(strings.HasSuffix)
+	case "!endWith":
+		return !has(strings.HasSuffix)
+	}
+	return false
+}
+
+func compareNumber(comparator string, actual float64, values []any) bool {
+	if comparator == "!=" {
+		return !anyValue(values, func(want any) bool {
+			n, ok := toNumber(want)
+			return ok && n == actual
+		})
+	}
+	return anyValue(values, func(want any) bool {
+		n, ok := toNumber(want)
+		if !ok {
+			return false
+		}
+		switch {
+		case actual < n:
+			return ordered(comparator, -1)
+		case actual > n:
+			return ordered(comparator, 1)
+		default:
+			return ordered(comparator, 0)
+		}
+	})
+}
+
+// ordered reports whether a comparison result (-1, 0 or 1) satisfies
+// comparator.
+func ordered(comparator string, cmp int) bool {
+	switch comparator {
+	case "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	}
+	return false
+}
+
+func isOrdering(comparator string) bool {
+	switch comparator {
+	case ">", ">=", "<", "<=":
+		return true
+	}
+	return false
+}
+
+func isVersion(subType string) bool {
+	return subType == "appVersion" || subType == "platformVersion"
+}
+
+// compareVersions compares dotted version strings numerically, segment by
+// segment. Missing segments count as zero.
+func compareVersions(a, b string) int {
+	as := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	bs := strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < max(len(as), len(bs)); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
+
+func filterValues(v any) []any {
+	switch vals := v.(type) {
+	case nil:
+		return nil
+	case []any:
+		return vals
+	case []string:
+		out := make([]any, len(vals))
+		for i, s := range vals {
+			out[i] = s
+		}
+		return out
+	default:
+		return []any{vals}
+	}
+}
+
+func anyValue(values []any, fn func(any) bool) bool {
+	for _, v := range values {
+		if fn(v) {
+			return true
+		}
+	}
+	return false
+}
+
+func toNumber(v any) (float64, bool) {
+	switch x := v.(type) {
+	case float64:
+		return x, true
+	case int:
+		return float64(x), true
+	case string:
+		n, err := strconv.ParseFloat(x, 64)
+		return n, err == nil
+	}
+	return 0, false
+}
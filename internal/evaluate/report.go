@@ -0,0 +1,284 @@
+package evaluate
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/135yshr/devcycle-cli/internal/output"
+)
+
+// NotServed labels users who are served no variation.
+const NotServed = "(not served)"
+
+// maxUserLineSize bounds the length of a single line in a users file.
+const maxUserLineSize = 1024 * 1024
+
+// ReadUsers reads newline-delimited JSON users from r and calls fn for each
+// one. Blank lines are skipped. Errors identify the offending line.
+func ReadUsers(r io.Reader, fn func(User) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxUserLineSize)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		user, err := ParseUser([]byte(text))
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		if err := fn(user); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("line %d: %w", line+1, err)
+	}
+	return nil
+}
+
+// Count is the number of users served a variation.
+type Count struct {
+	Variation  string  `json:"variation"`
+	Users      int     `json:"users"`
+	Percentage float64 `json:"percentage"`
+}
+
+// RuleCount is the number of users matched by a rule and what they were
+// served.
+type RuleCount struct {
+	Index      int     `json:"index"`
+	Rule       string  `json:"rule"`
+	Users      int     `json:"users"`
+	Variations []Count `json:"variations"`
+}
+
+// Switch is the number of users whose variation changes from one value to
+// another between the current and the proposed configuration.
+type Switch struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Users int    `json:"users"`
+}
+
+// Comparison summarizes how a proposed configuration changes what users are
+// served.
+type Comparison struct {
+	Changed    int      `json:"changed"`
+	Unchanged  int      `json:"unchanged"`
+	Variations []Count  `json:"variations"`
+	Switches   []Switch `json:"switches"`
+}
+
+// EnvironmentReport summarizes the evaluation of all users in one
+// environment.
+type EnvironmentReport struct {
+	Environment string      `json:"environment"`
+	Users       int         `json:"users"`
+	Variations  []Count     `json:"variations"`
+	Rules       []RuleCount `json:"rules"`
+	Comparison  *Comparison `json:"comparison,omitempty"`
+}
+
+// Tally accumulates evaluation results for one environment.
+type Tally struct {
+	users      int
+	variations map[string]int
+	rules      map[int]*ruleTally
+
+	compared   bool
+	changed    int
+	proposed   map[string]int
+	transition map[[2]string]int
+}
+
+type ruleTally struct {
+	name       string
+	users      int
+	variations map[string]int
+}
+
+// NewTally returns an empty Tally.
+func NewTally() *Tally {
+	return &Tally{
+		variations: make(map[string]int),
+		rules:      make(map[int]*ruleTally),
+		proposed:   make(map[string]int),
+		transition: make(map[[2]string]int),
+	}
+}
+
+// Add records the result for one user.
+func (t *Tally) Add(r *Result) {
+	t.users++
+	variation := servedLabel(r)
+	t.variations[variation]++
+
+	if r.RuleIndex == 0 {
+		return
+	}
+	rule, ok := t.rules[r.RuleIndex]
+	if !ok {
+		rule = &ruleTally{name: r.Rule, variations: make(map[string]int)}
+		t.rules[r.RuleIndex] = rule
+	}
+	rule.users++
+	rule.variations[variation]++
+}
+
+// AddComparison records what a user is served by the current and the
+// proposed configuration. It is called in addition to Add.
+func (t *Tally) AddComparison(current, proposed *Result) {
+	t.compared = true
+	from, to := servedLabel(current), servedLabel(proposed)
+	t.proposed[to]++
+	if from != to {
+		t.changed++
+		t.transition[[2]string{from, to}]++
+	}
+}
+
+// Report returns the summary for env.
+func (t *Tally) Report(env string) EnvironmentReport {
+	report := EnvironmentReport{
+		Environment: env,
+		Users:       t.users,
+		Variations:  counts(t.variations, t.users),
+		Rules:       []RuleCount{},
+	}
+
+	indexes := make([]int, 0, len(t.rules))
+	for i := range t.rules {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	for _, i := range indexes {
+		rule := t.rules[i]
+		report.Rules = append(report.Rules, RuleCount{
+			Index:      i,
+			Rule:       rule.name,
+			Users:      rule.users,
+			Variations: counts(rule.variations, rule.users),
+		})
+	}
+
+	if t.compared {
+		c := &Comparison{
+			Changed:    t.changed,
+			Unchanged:  t.users - t.changed,
+			Variations: counts(t.proposed, t.users),
+			Switches:   []Switch{},
+		}
+		for k, n := range t.transition {
+			c.Switches = append(c.Switches, Switch{From: k[0], To: k[1], Users: n})
+		}
+		sort.Slice(c.Switches, func(i, j int) bool {
+			a, b := c.Switches[i], c.Switches[j]
+			if a.Users != b.Users {
+				return a.Users > b.Users
+			}
+			if a.From != b.From {
+				return a.From < b.From
+			}
+			return a.To < b.To
+		})
+		report.Comparison = c
+	}
+	return report
+}
+
+func servedLabel(r *Result) string {
+	if r.Variation == "" {
+		return NotServed
+	}
+	return r.Variation
+}
+
+func counts(m map[string]int, total int) []Count {
+	out := make([]Count, 0, len(m))
+	for v, n := range m {
+		c := Count{Variation: v, Users: n}
+		if total > 0 {
+			c.Percentage = float64(n) / float64(total)
+		}
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Users != out[j].Users {
+			return out[i].Users > out[j].Users
+		}
+		return out[i].Variation < out[j].Variation
+	})
+	return out
+}
+
+// WriteReports renders environment reports as tables.
+func WriteReports(w io.Writer, reports []EnvironmentReport, color bool) error {
+	for i, r := range reports {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintln(w, output.Colorize(fmt.Sprintf("Environment: %s (%d users)", r.Environment, r.Users), output.ColorBold, color))
+
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "VARIATION\tUSERS\tSHARE")
+		fmt.Fprintln(tw, "---------\t-----\t-----")
+		for _, c := range r.Variations {
+			fmt.Fprintf(tw, "%s\t%d\t%s\n", c.Variation, c.Users, formatShare(c.Percentage))
+		}
+		fmt.Fprintln(tw)
+
+		fmt.Fprintln(tw, "#\tRULE\tUSERS\tSERVED")
+		fmt.Fprintln(tw, "-\t----\t-----\t------")
+		for _, rule := range r.Rules {
+			fmt.Fprintf(tw, "%d\t%s\t%d\t%s\n", rule.Index, rule.Rule, rule.Users, formatCounts(rule.Variations))
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+
+		if r.Comparison == nil {
+			continue
+		}
+		c := r.Comparison
+		fmt.Fprintln(w)
+		summary := fmt.Sprintf("Proposed: %d users would switch variation, %d unchanged", c.Changed, c.Unchanged)
+		colorCode := output.ColorGreen
+		if c.Changed > 0 {
+			colorCode = output.ColorYellow
+		}
+		fmt.Fprintln(w, output.Colorize(summary, colorCode, color))
+		if len(c.Switches) == 0 {
+			continue
+		}
+		tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "FROM\tTO\tUSERS")
+		fmt.Fprintln(tw, "----\t--\t-----")
+		for _, s := range c.Switches {
+			fmt.Fprintf(tw, "%s\t%s\t%d\n", s.From, s.To, s.Users)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatShare(p float64) string {
+	return fmt.Sprintf("%.1f%%", p*100)
+}
+
+func formatCounts(counts []Count) string {
+	parts := make([]string, len(counts))
+	for i, c := range counts {
+		parts[i] = fmt.Sprintf("%s %d", c.Variation, c.Users)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,89 @@
+package evaluate
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadUsers(t *testing.T) {
+	input := `{"user_id":"u1"}
+
+{"user_id":"u2","email":"a@b.c"}
+`
+	var ids []string
+	err := ReadUsers(strings.NewReader(input), func(u User) error {
+		ids = append(ids, u.UserID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(ids, []string{"u1", "u2"}) {
+		t.Errorf("expected [u1 u2], got %v", ids)
+	}
+
+	err = ReadUsers(strings.NewReader("{\"user_id\":\"u1\"}\n{\"email\":\"x\"}\n"), func(User) error { return nil })
+	if err == nil || !strings.HasPrefix(err.Error(), "line 2:") {
+		t.Errorf("expected error for line 2, got %v", err)
+	}
+}
+
+func TestTally(t *testing.T) {
+	tally := NewTally()
+	results := []*Result{
+		{Variation: "on", Rule: "Beta", RuleIndex: 1},
+		{Variation: "off", Rule: "All Users", RuleIndex: 2},
+		{Variation: "off", Rule: "All Users", RuleIndex: 2},
+		{},
+	}
+	proposed := []*Result{
+		{Variation: "on", Rule: "Beta", RuleIndex: 1},
+		{Variation: "on", Rule: "Beta", RuleIndex: 1},
+		{Variation: "off", Rule: "All Users", RuleIndex: 2},
+		{Variation: "off", Rule: "All Users", RuleIndex: 2},
+	}
+	for i, r := range results {
+		tally.Add(r)
+		tally.AddComparison(r, proposed[i])
+	}
+
+	report := tally.Report("production")
+	if report.Users != 4 {
+		t.Errorf("expected 4 users, got %d", report.Users)
+	}
+	wantVariations := []Count{
+		{Variation: "off", Users: 2, Percentage: 0.5},
+		{Variation: NotServed, Users: 1, Percentage: 0.25},
+		{Variation: "on", Users: 1, Percentage: 0.25},
+	}
+	if !reflect.DeepEqual(report.Variations, wantVariations) {
+		t.Errorf("unexpected variations: %+v", report.Variations)
+	}
+	if len(report.Rules) != 2 || report.Rules[0].Rule != "Beta" || report.Rules[1].Users != 2 {
+		t.Errorf("unexpected rules: %+v", report.Rules)
+	}
+
+	c := report.Comparison
+	if c == nil || c.Changed != 2 || c.Unchanged != 2 {
+		t.Fatalf("unexpected comparison: %+v", c)
+	}
+	wantSwitches := []Switch{
+		{From: NotServed, To: "off", Users: 1},
+		{From: "off", To: "on", Users: 1},
+	}
+	if !reflect.DeepEqual(c.Switches, wantSwitches) {
+		t.Errorf("unexpected switches: %+v", c.Switches)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteReports(&buf, []EnvironmentReport{report}, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"Environment: production (4 users)", "2 users would switch variation", "off           on   1"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, buf.String())
+		}
+	}
+}
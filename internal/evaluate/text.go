@@ -0,0 +1,67 @@
+package evaluate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/135yshr/devcycle-cli/internal/output"
+)
+
+// WriteText writes a step-by-step explanation of the result.
+func (r *Result) WriteText(w io.Writer, color bool) {
+	fmt.Fprintf(w, "User: %s\n", r.UserID)
+	fmt.Fprintf(w, "Status: %s\n", r.Status)
+
+	for _, s := range r.Steps {
+		verdict := output.Colorize("no match", output.ColorRed, color)
+		if s.Matched {
+			verdict = output.Colorize("match", output.ColorGreen, color)
+		}
+		fmt.Fprintf(w, "Rule %d %q: %s\n", s.Index, s.Rule, verdict)
+		for _, c := range s.Checks {
+			mark := output.Colorize("  FAIL", output.ColorRed, color)
+			if c.Matched {
+				mark = output.Colorize("  PASS", output.ColorGreen, color)
+			}
+			line := mark + " " + c.Condition
+			if c.Actual != "" {
+				line += output.Colorize(" (actual: "+c.Actual+")", output.ColorDim, color)
+			}
+			if c.Note != "" {
+				line += output.Colorize(" ("+c.Note+")", output.ColorDim, color)
+			}
+			fmt.Fprintln(w, line)
+		}
+	}
+
+	if r.Served() {
+		fmt.Fprintf(w, "Bucket: %.4f\n", r.Bucket)
+		variation := r.Variation
+		if r.VariationName != "" && r.VariationName != r.Variation {
+			variation += fmt.Sprintf(" (%s)", r.VariationName)
+		}
+		fmt.Fprintln(w, "Variation: "+output.Colorize(variation, output.ColorBold, color))
+		if len(r.Variables) > 0 {
+			fmt.Fprintln(w, "Variables:")
+			keys := make([]string, 0, len(r.Variables))
+			for k := range r.Variables {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				fmt.Fprintf(w, "  %s = %s\n", k, formatValue(r.Variables[k]))
+			}
+		}
+	}
+	fmt.Fprintf(w, "Result: %s\n", r.Reason)
+}
+
+func formatValue(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
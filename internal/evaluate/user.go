@@ -0,0 +1,68 @@
+package evaluate
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// User is the user context a feature is evaluated for. Field names follow
+// the DevCycle user object.
+type User struct {
+	UserID            string         `json:"user_id"`
+	Email             string         `json:"email,omitempty"`
+	Name              string         `json:"name,omitempty"`
+	Country           string         `json:"country,omitempty"`
+	Language          string         `json:"language,omitempty"`
+	AppVersion        string         `json:"appVersion,omitempty"`
+	AppBuild          any            `json:"appBuild,omitempty"`
+	Platform          string         `json:"platform,omitempty"`
+	PlatformVersion   string         `json:"platformVersion,omitempty"`
+	DeviceModel       string         `json:"deviceModel,omitempty"`
+	CustomData        map[string]any `json:"customData,omitempty"`
+	PrivateCustomData map[string]any `json:"privateCustomData,omitempty"`
+}
+
+// ParseUser decodes a user from JSON. A user ID is required because it is
+// the bucketing key.
+func ParseUser(data []byte) (User, error) {
+	var u User
+	if err := json.Unmarshal(data, &u); err != nil {
+		return User{}, fmt.Errorf("invalid user JSON: %w", err)
+	}
+	if u.UserID == "" {
+		return User{}, fmt.Errorf("user_id is required")
+	}
+	return u, nil
+}
+
+// field returns the value of a user filter sub type, and whether it is set.
+func (u User) field(subType, dataKey string) (any, bool) {
+	var s string
+	switch subType {
+	case "user_id":
+		s = u.UserID
+	case "email":
+		s = u.Email
+	case "country":
+		s = u.Country
+	case "platform":
+		s = u.Platform
+	case "platformVersion":
+		s = u.PlatformVersion
+	case "appVersion":
+		s = u.AppVersion
+	case "deviceModel":
+		s = u.DeviceModel
+	case "customData":
+		if v, ok := u.CustomData[dataKey]; ok && v != nil {
+			return v, true
+		}
+		if v, ok := u.PrivateCustomData[dataKey]; ok && v != nil {
+			return v, true
+		}
+		return nil, false
+	default:
+		return nil, false
+	}
+	return s, s != ""
+}
@@ -0,0 +1,233 @@
+// Package filterexpr implements a small expression language for DevCycle
+// audience filters.
+//
+// An expression such as
+//
+//	country in ("CA", "US") and (customData.tier = "gold" or audience("beta"))
+//
+// compiles to an [api.Filters] tree with [Parse], and [Format] turns an
+// existing tree back into the same syntax.
+package filterexpr
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+// userFields maps accepted field names (lower-cased) to DevCycle user
+// filter sub types.
+var userFields = map[string]string{
+	"user_id":         "user_id",
+	"userid":          "user_id",
+	"id":              "user_id",
+	"email":           "email",
+	"country":         "country",
+	"platform":        "platform",
+	"platformversion": "platformVersion",
+	"appversion":      "appVersion",
+	"devicemodel":     "deviceModel",
+}
+
+// fieldNames maps DevCycle user filter sub types to their canonical
+// expression field names.
+var fieldNames = map[string]string{
+	"user_id":         "user.id",
+	"email":           "user.email",
+	"country":         "user.country",
+	"platform":        "user.platform",
+	"platformVersion": "user.platformVersion",
+	"appVersion":      "user.appVersion",
+	"deviceModel":     "user.deviceModel",
+}
+
+// comparators maps accepted comparator spellings (lower-cased) to DevCycle
+// comparators.
+var comparators = map[string]string{
+	"=":             "=",
+	"==":            "=",
+	"is":            "=",
+	"in":            "=",
+	"equals":        "=",
+	"!=":            "!=",
+	"isnot":         "!=",
+	"notin":         "!=",
+	"!in":           "!=",
+	">":             ">",
+	">=":            ">=",
+	"<":             "<",
+	"<=":            "<=",
+	"contains":      "contain",
+	"contain":       "contain",
+	"!contains":     "!contain",
+	"!contain":      "!contain",
+	"notcontains":   "!contain",
+	"startswith":    "startWith",
+	"startwith":     "startWith",
+	"!startswith":   "!startWith",
+	"!startwith":    "!startWith",
+	"notstartswith": "!startWith",
+	"endswith":      "endWith",
+	"endwith":       "endWith",
+	"!endswith":     "!endWith",
+	"!endwith":      "!endWith",
+	"notendswith":   "!endWith",
+	"exists":        "exist",
+	"exist":         "exist",
+	"!exists":       "!exist",
+	"!exist":        "!exist",
+	"notexists":     "!exist",
+}
+
+// negations maps each DevCycle comparator to its logical negation.
+var negations = map[string]string{
+	"=":          "!=",
+	"!=":         "=",
+	">":          "<=",
+	"<=":         ">",
+	"<":          ">=",
+	">=":         "<",
+	"contain":    "!contain",
+	"!contain":   "contain",
+	"startWith":  "!startWith",
+	"!startWith": "startWith",
+	"endWith":    "!endWith",
+	"!endWith":   "endWith",
+	"exist":      "!exist",
+	"!exist":     "exist",
+}
+
+// literal is a value as written in an expression.
+type literal struct {
+	text   string
+	quoted bool
+}
+
+// isComparator reports whether word is a known comparator spelling.
+func isComparator(word string) bool {
+	_, ok := comparators[strings.ToLower(word)]
+	return ok
+}
+
+// NewFilter builds a user filter for field using the comparator op and the
+// given values. Values for custom data fields are typed by inspection:
+// numbers become Number, true/false become Boolean, anything else String.
+func NewFilter(field, op string, values []string) (api.Filter, error) {
+	lits := make([]literal, len(values))
+	for i, v := range values {
+		lits[i] = literal{text: v}
+	}
+	return newFilter(field, op, lits)
+}
+
+func newFilter(field, op string, values []literal) (api.Filter, error) {
+	comparator, ok := comparators[strings.ToLower(op)]
+	if !ok {
+		return api.Filter{}, fmt.Errorf("unknown comparator %q", op)
+	}
+	hasValues := comparator != "exist" && comparator != "!exist"
+	if hasValues && len(values) == 0 {
+		return api.Filter{}, fmt.Errorf("comparator %q requires a value", op)
+	}
+	if !hasValues && len(values) > 0 {
+		return api.Filter{}, fmt.Errorf("comparator %q does not take a value", op)
+	}
+
+	filter := api.Filter{Type: "user", Comparator: comparator}
+
+	name := strings.TrimPrefix(field, "user.")
+	if key, ok := customDataKey(name); ok {
+		filter.SubType = "customData"
+		filter.DataKey = key
+		if hasValues {
+			filter.DataKeyType, filter.Values = typedValues(values)
+		}
+		return filter, nil
+	}
+
+	subType, ok := userFields[strings.ToLower(name)]
+	if !ok {
+		return api.Filter{}, fmt.Errorf("unknown field %q", field)
+	}
+	filter.SubType = subType
+	if hasValues {
+		vals := make([]any, len(values))
+		for i, v := range values {
+			vals[i] = v.text
+		}
+		filter.Values = vals
+	}
+	return filter, nil
+}
+
+func customDataKey(field string) (string, bool) {
+	for _, prefix := range []string{"customData.", "custom."} {
+		if strings.HasPrefix(field, prefix) && len(field) > len(prefix) {
+			return field[len(prefix):], true
+		}
+	}
+	return "", false
+}
+
+// typedValues infers the custom data type of values. Quoted values are
+// always strings.
+func typedValues(values []literal) (string, []any) {
+	out := make([]any, len(values))
+
+	allBool, allNumber := true, true
+	for _, v := range values {
+		if v.quoted || (v.text != "true" && v.text != "false") {
+			allBool = false
+		}
+		if _, err := strconv.ParseFloat(v.text, 64); v.quoted || err != nil {
+			allNumber = false
+		}
+	}
+
+	switch {
+	case allBool:
+		for i, v := range values {
+			out[i] = v.text == "true"
+		}
+		return "Boolean", out
+	case allNumber:
+		for i, v := range values {
+			out[i], _ = strconv.ParseFloat(v.text, 64)
+		}
+		return "Number", out
+	default:
+		for i, v := range values {
+			out[i] = v.text
+		}
+		return "String", out
+	}
+}
+
+// Walk calls fn for every filter in filters, including filters inside
+// nested groups.
+func Walk(filters *api.Filters, fn func(*api.Filter)) {
+	walkFilters(filters.Filters, fn)
+}
+
+func walkFilters(filters []api.Filter, fn func(*api.Filter)) {
+	for i := range filters {
+		fn(&filters[i])
+		walkFilters(filters[i].Filters, fn)
+	}
+}
+
+// Comparators returns the DevCycle filter comparators, sorted.
+func Comparators() []string {
+	return slices.Sorted(maps.Keys(negations))
+}
+
+// SubTypes returns the DevCycle user filter sub types, sorted.
+func SubTypes() []string {
+	subTypes := append(slices.Collect(maps.Keys(fieldNames)), "customData")
+	slices.Sort(subTypes)
+	return subTypes
+}
@@ -0,0 +1,224 @@
+package filterexpr
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  api.Filters
+	}{
+		{
+			input: "all",
+			want:  api.Filters{Operator: "and", Filters: []api.Filter{{Type: "all"}}},
+		},
+		{
+			input: "user.email endsWith @acme.com",
+			want: api.Filters{Operator: "and", Filters: []api.Filter{
+				{Type: "user", SubType: "email", Comparator: "endWith", Values: []any{"@acme.com"}},
+			}},
+		},
+		{
+			input: `country in ("CA","US")`,
+			want: api.Filters{Operator: "and", Filters: []api.Filter{
+				{Type: "user", SubType: "country", Comparator: "=", Values: []any{"CA", "US"}},
+			}},
+		},
+		{
+			input: "country = CA, US",
+			want: api.Filters{Operator: "and", Filters: []api.Filter{
+				{Type: "user", SubType: "country", Comparator: "=", Values: []any{"CA", "US"}},
+			}},
+		},
+		{
+			input: `user_id!="u1"`,
+			want: api.Filters{Operator: "and", Filters: []api.Filter{
+				{Type: "user", SubType: "user_id", Comparator: "!=", Values: []any{"u1"}},
+			}},
+		},
+		{
+			input: "customData.seats >= 10 and customData.beta = true and customData.plan = pro",
+			want: api.Filters{Operator: "and", Filters: []api.Filter{
+				{Type: "user", SubType: "customData", DataKey: "seats", DataKeyType: "Number", Comparator: ">=", Values: []any{float64(10)}},
+				{Type: "user", SubType: "customData", DataKey: "beta", DataKeyType: "Boolean", Comparator: "=", Values: []any{true}},
+				{Type: "user", SubType: "customData", DataKey: "plan", DataKeyType: "String", Comparator: "=", Values: []any{"pro"}},
+			}},
+		},
+		{
+			input: `customData.code = "10"`,
+			want: api.Filters{Operator: "and", Filters: []api.Filter{
+				{Type: "user", SubType: "customData", DataKey: "code", DataKeyType: "String", Comparator: "=", Values: []any{"10"}},
+			}},
+		},
+		{
+			input: `country in ("CA","US") and (customData.tier = "gold" or audience("beta"))`,
+			want: api.Filters{Operator: "and", Filters: []api.Filter{
+				{Type: "user", SubType: "country", Comparator: "=", Values: []any{"CA", "US"}},
+				{Operator: "or", Filters: []api.Filter{
+					{Type: "user", SubType: "customData", DataKey: "tier", DataKeyType: "String", Comparator: "=", Values: []any{"gold"}},
+					{Type: "audienceMatch", Comparator: "=", Audiences: []string{"beta"}},
+				}},
+			}},
+		},
+		{
+			input: "email exists or platform = iOS or (optIn)",
+			want: api.Filters{Operator: "or", Filters: []api.Filter{
+				{Type: "user", SubType: "email", Comparator: "exist"},
+				{Type: "user", SubType: "platform", Comparator: "=", Values: []any{"iOS"}},
+				{Type: "optIn"},
+			}},
+		},
+		{
+			input: `not email contains "test" and country not in (JP, KR) and not audience("internal")`,
+			want: api.Filters{Operator: "and", Filters: []api.Filter{
+				{Type: "user", SubType: "email", Comparator: "!contain", Values: []any{"test"}},
+				{Type: "user", SubType: "country", Comparator: "!=", Values: []any{"JP", "KR"}},
+				{Type: "audienceMatch", Comparator: "!=", Audiences: []string{"internal"}},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		input string
+		pos   int
+		msg   string
+	}{
+		{"", 0, "empty expression"},
+		{"email", 5, "expected a comparator"},
+		{"email like x", 6, "expected a comparator"},
+		{"shoeSize = 42", 0, "unknown field"},
+		{"email endsWith", 14, "expected a value"},
+		{"(email exists", 13, "expected ')'"},
+		{`email = "abc`, 8, "unterminated string"},
+		{"email exists email exists", 13, "unexpected"},
+		{"not (email exists or all)", 0, "not cannot be applied to a group"},
+		{"country not like x", 12, "after not"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := Parse(tt.input)
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("expected SyntaxError, got %v", err)
+			}
+			if syntaxErr.Pos != tt.pos {
+				t.Errorf("expected position %d, got %d (%v)", tt.pos, syntaxErr.Pos, err)
+			}
+			if !strings.Contains(syntaxErr.Msg, tt.msg) {
+				t.Errorf("expected message to contain %q, got %q", tt.msg, syntaxErr.Msg)
+			}
+		})
+	}
+}
+
+func TestSyntaxError_Highlight(t *testing.T) {
+	err := &SyntaxError{Pos: 6, Msg: "bad"}
+	want := "email like x\n      ^"
+	if got := err.Highlight("email like x"); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		filters api.Filters
+		want    string
+	}{
+		{
+			filters: api.Filters{Operator: "and", Filters: []api.Filter{{Type: "all"}}},
+			want:    "all",
+		},
+		{
+			filters: api.Filters{Operator: "and", Filters: []api.Filter{
+				{Type: "user", SubType: "country", Comparator: "=", Values: []any{"CA", "US"}},
+				{Operator: "or", Filters: []api.Filter{
+					{Type: "user", SubType: "customData", DataKey: "tier", DataKeyType: "String", Comparator: "=", Values: []any{"gold"}},
+					{Type: "audienceMatch", Comparator: "=", Audiences: []string{"beta"}},
+				}},
+			}},
+			want: `user.country in ("CA", "US") and (customData.tier = "gold" or audience("beta"))`,
+		},
+		{
+			filters: api.Filters{Operator: "or", Filters: []api.Filter{
+				{Type: "user", SubType: "email", Comparator: "endWith", Values: []any{"@acme.com"}},
+				{Type: "user", SubType: "customData", DataKey: "seats", DataKeyType: "Number", Comparator: ">", Values: []any{float64(5)}},
+				{Type: "user", SubType: "appVersion", Comparator: "!exist"},
+				{Type: "user", SubType: "country", Comparator: "!=", Values: []any{"JP", "KR"}},
+			}},
+			want: `user.email endsWith "@acme.com" or customData.seats > 5 or user.appVersion !exists or user.country not in ("JP", "KR")`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			got := Format(tt.filters)
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+
+			reparsed, err := Parse(got)
+			if err != nil {
+				t.Fatalf("failed to parse formatted expression: %v", err)
+			}
+			if !reflect.DeepEqual(reparsed, tt.filters) {
+				t.Errorf("round trip mismatch:\n got: %+v\nwant: %+v", reparsed, tt.filters)
+			}
+		})
+	}
+}
+
+func TestWalk(t *testing.T) {
+	filters, err := Parse(`audience("a") and (audience("b") or email exists)`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var seen []string
+	Walk(&filters, func(f *api.Filter) {
+		if f.Type == "audienceMatch" {
+			seen = append(seen, f.Audiences...)
+			f.Audiences = []string{"id-" + f.Audiences[0]}
+		}
+	})
+	if !reflect.DeepEqual(seen, []string{"a", "b"}) {
+		t.Errorf("expected [a b], got %v", seen)
+	}
+	if filters.Filters[1].Filters[0].Audiences[0] != "id-b" {
+		t.Errorf("expected nested filter to be updated, got %+v", filters.Filters[1])
+	}
+}
+
+func TestNewFilter(t *testing.T) {
+	f, err := NewFilter("customData.seats", "<", []string{"3"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f.DataKeyType != "Number" || !reflect.DeepEqual(f.Values, []any{float64(3)}) {
+		t.Errorf("unexpected filter: %+v", f)
+	}
+
+	if _, err := NewFilter("email", "exists", []string{"x"}); err == nil {
+		t.Error("expected error for value with exists")
+	}
+}
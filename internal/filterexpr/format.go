@@ -0,0 +1,130 @@
+package filterexpr
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+// comparatorWords maps DevCycle comparators to their expression spelling.
+var comparatorWords = map[string]string{
+	"contain":    "contains",
+	"!contain":   "!contains",
+	"startWith":  "startsWith",
+	"!startWith": "!startsWith",
+	"endWith":    "endsWith",
+	"!endWith":   "!endsWith",
+	"exist":      "exists",
+	"!exist":     "!exists",
+}
+
+// Format renders filters in expression syntax. The result can be passed
+// back to Parse to obtain equivalent filters.
+func Format(filters api.Filters) string {
+	return formatGroup(filters.Operator, filters.Filters, false)
+}
+
+func formatGroup(op string, filters []api.Filter, nested bool) string {
+	if op == "" {
+		op = "and"
+	}
+	parts := make([]string, len(filters))
+	for i, f := range filters {
+		parts[i] = formatFilter(f)
+	}
+	s := strings.Join(parts, " "+op+" ")
+	if nested && len(filters) > 1 {
+		return "(" + s + ")"
+	}
+	return s
+}
+
+func formatFilter(f api.Filter) string {
+	if isGroup(f) {
+		return formatGroup(f.Operator, f.Filters, true)
+	}
+
+	switch f.Type {
+	case "all", "optIn":
+		return f.Type
+	case "audienceMatch":
+		quoted := make([]string, len(f.Audiences))
+		for i, a := range f.Audiences {
+			quoted[i] = strconv.Quote(a)
+		}
+		s := "audience(" + strings.Join(quoted, ", ") + ")"
+		if f.Comparator == "!=" {
+			return "not " + s
+		}
+		return s
+	case "user":
+		return formatComparison(f)
+	default:
+		data, _ := json.Marshal(f)
+		return string(data)
+	}
+}
+
+func formatComparison(f api.Filter) string {
+	field := fieldNames[f.SubType]
+	switch {
+	case f.SubType == "customData":
+		field = "customData." + f.DataKey
+	case field == "":
+		field = f.SubType
+	}
+
+	values := valueList(f.Values)
+	op, ok := comparatorWords[f.Comparator]
+	if !ok {
+		op = f.Comparator
+	}
+	if f.Comparator == "exist" || f.Comparator == "!exist" {
+		return field + " " + op
+	}
+
+	if len(values) == 1 {
+		return fmt.Sprintf("%s %s %s", field, op, values[0])
+	}
+	switch f.Comparator {
+	case "=":
+		op = "in"
+	case "!=":
+		op = "not in"
+	}
+	return fmt.Sprintf("%s %s (%s)", field, op, strings.Join(values, ", "))
+}
+
+func valueList(v any) []string {
+	var items []any
+	switch vals := v.(type) {
+	case nil:
+	case []any:
+		items = vals
+	case []string:
+		for _, s := range vals {
+			items = append(items, s)
+		}
+	default:
+		items = []any{vals}
+	}
+
+	out := make([]string, len(items))
+	for i, item := range items {
+		switch x := item.(type) {
+		case string:
+			out[i] = strconv.Quote(x)
+		case float64:
+			out[i] = strconv.FormatFloat(x, 'f', -1, 64)
+		case bool:
+			out[i] = strconv.FormatBool(x)
+		default:
+			data, _ := json.Marshal(x)
+			out[i] = string(data)
+		}
+	}
+	return out
+}
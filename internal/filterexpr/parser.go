@@ -0,0 +1,272 @@
+package filterexpr
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+// SyntaxError describes an invalid expression. Pos is the byte offset in
+// the input where the problem was found.
+type SyntaxError struct {
+	Pos int
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at position %d: %s", e.Pos+1, e.Msg)
+}
+
+// Highlight returns input followed by a line with a caret under the
+// position of the error.
+func (e *SyntaxError) Highlight(input string) string {
+	pos := min(e.Pos, len(input))
+	return input + "\n" + strings.Repeat(" ", utf8.RuneCountInString(input[:pos])) + "^"
+}
+
+// Parse compiles an expression into audience filters.
+//
+// Conditions have the form "<field> <comparator> <values>" and are combined
+// with and, or, not and parentheses. Fields are user.id, user.email,
+// user.country, user.platform, user.platformVersion, user.appVersion,
+// user.deviceModel (the user. prefix is optional) and customData.<key>.
+// Lists of values are written as ("a", "b") or a, b. The keywords all,
+// optIn and audience("key", ...) are also accepted.
+func Parse(input string) (api.Filters, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return api.Filters{}, err
+	}
+	p := &parser{tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return api.Filters{}, &SyntaxError{Pos: 0, Msg: "empty expression"}
+	}
+
+	node, err := p.parseOr()
+	if err != nil {
+		return api.Filters{}, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return api.Filters{}, p.errorf(tok, "unexpected %s", describe(tok))
+	}
+
+	if isGroup(node) {
+		return api.Filters{Operator: node.Operator, Filters: node.Filters}, nil
+	}
+	return api.Filters{Operator: "and", Filters: []api.Filter{node}}, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) isKeyword(word string) bool {
+	tok := p.peek()
+	return tok.kind == tokenWord && strings.EqualFold(tok.text, word)
+}
+
+func (p *parser) errorf(tok token, format string, args ...any) error {
+	return &SyntaxError{Pos: tok.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) parseOr() (api.Filter, error) {
+	return p.parseBinary("or", p.parseAnd)
+}
+
+func (p *parser) parseAnd() (api.Filter, error) {
+	return p.parseBinary("and", p.parseUnary)
+}
+
+func (p *parser) parseBinary(op string, operand func() (api.Filter, error)) (api.Filter, error) {
+	left, err := operand()
+	if err != nil {
+		return api.Filter{}, err
+	}
+	if !p.isKeyword(op) {
+		return left, nil
+	}
+
+	group := api.Filter{Operator: op}
+	group.Filters = appendFlattened(group.Filters, left, op)
+	for p.isKeyword(op) {
+		p.next()
+		right, err := operand()
+		if err != nil {
+			return api.Filter{}, err
+		}
+		group.Filters = appendFlattened(group.Filters, right, op)
+	}
+	return group, nil
+}
+
+func appendFlattened(filters []api.Filter, f api.Filter, op string) []api.Filter {
+	if isGroup(f) && f.Operator == op {
+		return append(filters, f.Filters...)
+	}
+	return append(filters, f)
+}
+
+func (p *parser) parseUnary() (api.Filter, error) {
+	if !p.isKeyword("not") {
+		return p.parsePrimary()
+	}
+
+	tok := p.next()
+	f, err := p.parseUnary()
+	if err != nil {
+		return api.Filter{}, err
+	}
+	switch {
+	case isGroup(f):
+		return api.Filter{}, p.errorf(tok, "not cannot be applied to a group")
+	case f.Type == "audienceMatch" || f.Type == "user":
+		f.Comparator = negations[f.Comparator]
+		return f, nil
+	default:
+		return api.Filter{}, p.errorf(tok, "not cannot be applied to %s", f.Type)
+	}
+}
+
+func (p *parser) parsePrimary() (api.Filter, error) {
+	tok := p.peek()
+	switch tok.kind {
+	case tokenLParen:
+		p.next()
+		f, err := p.parseOr()
+		if err != nil {
+			return api.Filter{}, err
+		}
+		if closing := p.peek(); closing.kind != tokenRParen {
+			return api.Filter{}, p.errorf(closing, "expected ')' but found %s", describe(closing))
+		}
+		p.next()
+		return f, nil
+	case tokenWord:
+		switch {
+		case strings.EqualFold(tok.text, "all"):
+			p.next()
+			return api.Filter{Type: "all"}, nil
+		case strings.EqualFold(tok.text, "optIn"):
+			p.next()
+			return api.Filter{Type: "optIn"}, nil
+		case strings.EqualFold(tok.text, "audience") && p.tokens[p.pos+1].kind == tokenLParen:
+			p.next()
+			values, err := p.parseValues()
+			if err != nil {
+				return api.Filter{}, err
+			}
+			f := api.Filter{Type: "audienceMatch", Comparator: "="}
+			for _, v := range values {
+				f.Audiences = append(f.Audiences, v.text)
+			}
+			return f, nil
+		}
+		return p.parseComparison()
+	default:
+		return api.Filter{}, p.errorf(tok, "expected a condition but found %s", describe(tok))
+	}
+}
+
+func (p *parser) parseComparison() (api.Filter, error) {
+	field := p.next()
+
+	opTok := p.peek()
+	var op string
+	switch {
+	case opTok.kind == tokenOperator:
+		op = p.next().text
+	case opTok.kind == tokenWord && strings.EqualFold(opTok.text, "not"):
+		p.next()
+		negated := p.peek()
+		if negated.kind != tokenWord || !isComparator("not"+negated.text) {
+			return api.Filter{}, p.errorf(negated, "expected in, contains, startsWith, endsWith or exists after not")
+		}
+		op = "not" + p.next().text
+	case opTok.kind == tokenWord && isComparator(opTok.text):
+		op = p.next().text
+	default:
+		return api.Filter{}, p.errorf(opTok, "expected a comparator after %q but found %s", field.text, describe(opTok))
+	}
+
+	var values []literal
+	comparator := comparators[strings.ToLower(op)]
+	if comparator != "exist" && comparator != "!exist" {
+		var err error
+		if values, err = p.parseValues(); err != nil {
+			return api.Filter{}, err
+		}
+	}
+
+	f, err := newFilter(field.text, op, values)
+	if err != nil {
+		return api.Filter{}, p.errorf(field, "%s", err.Error())
+	}
+	return f, nil
+}
+
+// parseValues reads either a parenthesized list of values or one or more
+// comma-separated values.
+func (p *parser) parseValues() ([]literal, error) {
+	if p.peek().kind == tokenLParen {
+		p.next()
+		values, err := p.parseValueList()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.peek(); closing.kind != tokenRParen {
+			return nil, p.errorf(closing, "expected ')' but found %s", describe(closing))
+		}
+		p.next()
+		return values, nil
+	}
+	return p.parseValueList()
+}
+
+func (p *parser) parseValueList() ([]literal, error) {
+	var values []literal
+	for {
+		tok := p.peek()
+		switch tok.kind {
+		case tokenString:
+			values = append(values, literal{text: p.next().text, quoted: true})
+		case tokenWord:
+			values = append(values, literal{text: p.next().text})
+		default:
+			return nil, p.errorf(tok, "expected a value but found %s", describe(tok))
+		}
+		if p.peek().kind != tokenComma {
+			return values, nil
+		}
+		p.next()
+	}
+}
+
+func isGroup(f api.Filter) bool {
+	return f.Type == "" && f.Operator != ""
+}
+
+func describe(tok token) string {
+	switch tok.kind {
+	case tokenWord, tokenOperator:
+		return fmt.Sprintf("%q", tok.text)
+	case tokenString:
+		return fmt.Sprintf("string %q", tok.text)
+	default:
+		return tok.kind.String()
+	}
+}
@@ -0,0 +1,162 @@
+package inputs
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/135yshr/devcycle-cli/internal/jsonschema"
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+// Project is what the checks know about the project a file is for. Checks
+// that need a nil field are skipped.
+type Project struct {
+	// Audiences holds the keys and IDs of the audiences of the project.
+	Audiences map[string]bool
+	// Variations holds the keys and IDs of the variations of the feature
+	// that a targeting file is for.
+	Variations map[string]bool
+	// Variables holds the variables of the feature that a variation file is
+	// for.
+	Variables []api.VariableDefinition
+}
+
+// Validate checks the document doc, a value as decoded by encoding/json,
+// against the schema of kind k and, when it matches, runs the checks that
+// the schema cannot express. The problems are in document order.
+func (k Kind) Validate(doc any, project *Project) []jsonschema.Error {
+	if errs := jsonschema.Validate(k.Schema(), doc); len(errs) > 0 {
+		return errs
+	}
+	if project == nil {
+		project = &Project{}
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return []jsonschema.Error{{Message: err.Error()}}
+	}
+	v := k.value()
+	if err := json.Unmarshal(data, v); err != nil {
+		return []jsonschema.Error{{Message: err.Error()}}
+	}
+
+	c := &checker{project: project}
+	switch v := v.(type) {
+	case *api.CreateFeatureV2Request:
+		c.feature(v)
+	case *map[string]*api.EnvironmentConfig:
+		c.configurations("", *v, project.Variations)
+	case *api.CreateAudienceRequest:
+		c.filters(jsonschema.Key("", "filters"), v.Filters)
+	case *api.CreateVariationRequest:
+		if project.Variables != nil {
+			c.variationValues(jsonschema.Key("", "variables"), v.Variables, project.Variables, false)
+		}
+	}
+	return c.errs
+}
+
+type checker struct {
+	project *Project
+	errs    []jsonschema.Error
+}
+
+func (c *checker) errorf(path, format string, args ...any) {
+	c.errs = append(c.errs, jsonschema.Error{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (c *checker) feature(req *api.CreateFeatureV2Request) {
+	variables := make(map[string]bool, len(req.Variables))
+	for i, v := range req.Variables {
+		if variables[v.Key] {
+			c.errorf(jsonschema.Key(jsonschema.Index("variables", i), "key"), "duplicate variable %q", v.Key)
+		}
+		variables[v.Key] = true
+	}
+
+	variations := make(map[string]bool, len(req.Variations))
+	for i, v := range req.Variations {
+		path := jsonschema.Index("variations", i)
+		if variations[v.Key] {
+			c.errorf(jsonschema.Key(path, "key"), "duplicate variation %q", v.Key)
+		}
+		variations[v.Key] = true
+		c.variationValues(jsonschema.Key(path, "variables"), v.Variables, req.Variables, true)
+	}
+
+	if req.ControlVariation != "" && !variations[req.ControlVariation] {
+		c.errorf("controlVariation", "variation %q is not declared", req.ControlVariation)
+	}
+	if len(req.Variations) == 0 {
+		variations = nil // the API creates default variations
+	}
+	c.configurations("configurations", req.Configurations, variations)
+}
+
+// variationValues checks the variable values of a variation. Unless
+// complete is set, variables without a value are not reported.
+func (c *checker) variationValues(path string, values map[string]any, variables []api.VariableDefinition, complete bool) {
+	if complete {
+		c.errs = append(c.errs, ValidateVariationValues(path, values, variables)...)
+	} else {
+		c.errs = append(c.errs, ValidateVariableValues(path, values, variables)...)
+	}
+}
+
+// configurations checks the targeting of each environment. When
+// variations is not nil, distributions must serve one of them.
+func (c *checker) configurations(path string, configs map[string]*api.EnvironmentConfig, variations map[string]bool) {
+	for _, env := range sortedKeys(configs) {
+		cfg := configs[env]
+		if cfg == nil {
+			continue
+		}
+		envPath := jsonschema.Key(path, env)
+		for i, t := range cfg.Targets {
+			targetPath := jsonschema.Index(jsonschema.Key(envPath, "targets"), i)
+			distPath := jsonschema.Key(targetPath, "distribution")
+			var total float64
+			for j, d := range t.Distribution {
+				total += d.Percentage
+				if variations != nil && !variations[d.Variation] {
+					c.errorf(jsonschema.Key(jsonschema.Index(distPath, j), "_variation"), "variation %q does not exist", d.Variation)
+				}
+			}
+			if math.Abs(total-1) > 0.01 {
+				c.errorf(distPath, "percentages add up to %.2f, must add up to 1", total)
+			}
+			c.filters(jsonschema.Key(jsonschema.Key(targetPath, "audience"), "filters"), t.Audience.Filters)
+		}
+	}
+}
+
+// filters checks that the audiences matched by filters exist.
+func (c *checker) filters(path string, filters api.Filters) {
+	c.filterList(jsonschema.Key(path, "filters"), filters.Filters)
+}
+
+func (c *checker) filterList(path string, filters []api.Filter) {
+	for i, f := range filters {
+		filterPath := jsonschema.Index(path, i)
+		if f.Type == "audienceMatch" && c.project.Audiences != nil {
+			for j, ref := range f.Audiences {
+				if !c.project.Audiences[ref] {
+					c.errorf(jsonschema.Index(jsonschema.Key(filterPath, "_audiences"), j), "audience %q does not exist", ref)
+				}
+			}
+		}
+		c.filterList(jsonschema.Key(filterPath, "filters"), f.Filters)
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,202 @@
+// Package inputs describes the files that dvcx reads with --from-file: their
+// JSON Schemas, how to recognize them, and the checks that a schema cannot
+// express, such as distributions naming variations that exist.
+package inputs
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/135yshr/devcycle-cli/internal/filterexpr"
+	"github.com/135yshr/devcycle-cli/internal/jsonschema"
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+// Names of the kinds of input files.
+const (
+	KindFeature   = "feature"
+	KindTargeting = "targeting"
+	KindAudience  = "audience"
+	KindVariation = "variation"
+)
+
+// Kind is a kind of input file.
+type Kind struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// value returns a new value of the Go type the file is decoded into.
+	value func() any
+}
+
+var kinds = []Kind{
+	{
+		Name:        KindFeature,
+		Description: "feature with variables, variations and configurations (features create/update --from-file)",
+		value:       func() any { return new(api.CreateFeatureV2Request) },
+	},
+	{
+		Name:        KindTargeting,
+		Description: "targeting configurations by environment key (targeting update --from-file)",
+		value:       func() any { return new(map[string]*api.EnvironmentConfig) },
+	},
+	{
+		Name:        KindAudience,
+		Description: "audience definition (audiences create --from-file)",
+		value:       func() any { return new(api.CreateAudienceRequest) },
+	},
+	{
+		Name:        KindVariation,
+		Description: "variation with its variable values (variations create/update)",
+		value:       func() any { return new(api.CreateVariationRequest) },
+	},
+}
+
+// Kinds returns every kind of input file.
+func Kinds() []Kind {
+	return slices.Clone(kinds)
+}
+
+// Lookup returns the kind called name.
+func Lookup(name string) (Kind, bool) {
+	for _, k := range kinds {
+		if k.Name == name {
+			return k, true
+		}
+	}
+	return Kind{}, false
+}
+
+// keyPattern matches the keys of features, variables, variations and
+// audiences.
+const keyPattern = `^[a-z0-9._-]+$`
+
+// Schema returns the JSON Schema of files of kind k.
+func (k Kind) Schema() *jsonschema.Schema {
+	s := jsonschema.For(k.value())
+	s.Title = "dvcx " + k.Name + " file"
+	s.Description = strings.ToUpper(k.Description[:1]) + k.Description[1:] + "."
+
+	switch k.Name {
+	case KindFeature:
+		s.Required = []string{"name", "key"}
+		s.Properties["key"].Pattern = keyPattern
+		s.Properties["type"].Enum = anys(api.FeatureTypes)
+		s.Properties["type"].Description = "Defaults to release."
+		s.Properties["controlVariation"].Description = "Key of the control variation."
+	case KindTargeting:
+		s.Description += " Each property is an environment key."
+	case KindAudience, KindVariation:
+		s.Required = []string{"name", "key"}
+		s.Properties["key"].Pattern = keyPattern
+	}
+	constrainDefs(s)
+	return s
+}
+
+// constrainDefs adds the constraints of the shared types found in the
+// $defs of s.
+func constrainDefs(s *jsonschema.Schema) {
+	if d := s.Def("VariableDefinition"); d != nil {
+		d.Required = []string{"key", "type"}
+		d.Properties["key"].Pattern = keyPattern
+		d.Properties["type"].Enum = anys(api.VariableTypes)
+	}
+	if d := s.Def("VariableValidationSchema"); d != nil {
+		d.Required = []string{"schemaType"}
+		d.Properties["schemaType"].Enum = anys(api.ValidationSchemaTypes)
+		d.Properties["jsonSchema"].Description = "JSON Schema that values must match, as a string."
+	}
+	if d := s.Def("VariationDefinition"); d != nil {
+		d.Required = []string{"key", "name"}
+		d.Properties["key"].Pattern = keyPattern
+		d.Properties["variables"].Description = "Values of the feature's variables, by variable key."
+	}
+	if d := s.Def("EnvironmentConfig"); d != nil {
+		d.Properties["status"].Enum = anys(api.ConfigurationStatuses)
+	}
+	if d := s.Def("Target"); d != nil {
+		d.Required = []string{"distribution"}
+		d.Properties["distribution"].MinItems = ptr(1)
+	}
+	if d := s.Def("Distribution"); d != nil {
+		d.Required = []string{"_variation", "percentage"}
+		d.Properties["_variation"].Description = "Key or ID of the variation to serve."
+		d.Properties["percentage"].Minimum = ptr(0.0)
+		d.Properties["percentage"].Maximum = ptr(1.0)
+	}
+	operators := []any{"and", "or"}
+	if d := s.Def("Filters"); d != nil {
+		d.Required = []string{"operator", "filters"}
+		d.Properties["operator"].Enum = operators
+	}
+	if d := s.Def("Filter"); d != nil {
+		d.Properties["type"].Enum = []any{"all", "user", "audienceMatch", "optIn"}
+		d.Properties["subType"].Enum = anys(filterexpr.SubTypes())
+		d.Properties["comparator"].Enum = anys(filterexpr.Comparators())
+		d.Properties["dataKeyType"].Enum = []any{"String", "Boolean", "Number"}
+		d.Properties["_audiences"].Description = "Keys or IDs of the audiences to match."
+		d.Properties["operator"].Enum = operators
+	}
+}
+
+// Detect returns the kind of the document doc, a value as decoded by
+// encoding/json, when it can be told from its properties.
+func Detect(doc any) (Kind, bool) {
+	obj, ok := doc.(map[string]any)
+	if !ok || len(obj) == 0 {
+		return Kind{}, false
+	}
+	has := func(name string) bool {
+		_, ok := obj[name]
+		return ok
+	}
+	_, variablesList := obj["variables"].([]any)
+	_, variablesMap := obj["variables"].(map[string]any)
+	switch {
+	case variablesList || has("variations") || has("configurations") || has("controlVariation") || has("sdkVisibility"):
+		return Lookup(KindFeature)
+	case has("filters"):
+		return Lookup(KindAudience)
+	case variablesMap:
+		return Lookup(KindVariation)
+	case isTargeting(obj):
+		return Lookup(KindTargeting)
+	case has("key") && slices.Contains(api.FeatureTypes, stringValue(obj["type"])):
+		return Lookup(KindFeature)
+	}
+	return Kind{}, false
+}
+
+// isTargeting reports whether every property of obj looks like an
+// environment configuration.
+func isTargeting(obj map[string]any) bool {
+	for _, v := range obj {
+		cfg, ok := v.(map[string]any)
+		if !ok {
+			return false
+		}
+		for name := range cfg {
+			if name != "status" && name != "targets" {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func stringValue(v any) string {
+	s, _ := v.(string)
+	return s
+}
+
+func anys[T any](values []T) []any {
+	out := make([]any, len(values))
+	for i, v := range values {
+		out[i] = v
+	}
+	return out
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,178 @@
+package inputs
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+func decode(t *testing.T, data string) any {
+	t.Helper()
+	var v any
+	if err := json.Unmarshal([]byte(data), &v); err != nil {
+		t.Fatalf("invalid test JSON: %v", err)
+	}
+	return v
+}
+
+func TestKindSchema(t *testing.T) {
+	for _, k := range Kinds() {
+		s := k.Schema()
+		if s.Title == "" || s.Description == "" {
+			t.Errorf("%s: expected a title and description", k.Name)
+		}
+		if _, err := json.Marshal(s); err != nil {
+			t.Errorf("%s: failed to marshal schema: %v", k.Name, err)
+		}
+	}
+
+	feature, _ := Lookup(KindFeature)
+	s := feature.Schema()
+	if len(s.Property("type").Enum) != len(api.FeatureTypes) {
+		t.Errorf("expected feature types enum, got %v", s.Property("type").Enum)
+	}
+	if s.Def("Filter") == nil || len(s.Def("Filter").Properties["comparator"].Enum) == 0 {
+		t.Error("expected filter comparators enum")
+	}
+	if _, ok := Lookup("unknown"); ok {
+		t.Error("expected unknown kind not to be found")
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		doc  string
+		want string
+	}{
+		{`{"name": "A", "key": "a", "variables": [{"key": "v", "type": "Boolean"}]}`, KindFeature},
+		{`{"name": "A", "key": "a", "type": "ops"}`, KindFeature},
+		{`{"name": "A", "key": "a", "filters": {"operator": "and", "filters": []}}`, KindAudience},
+		{`{"name": "On", "key": "on", "variables": {"enabled": true}}`, KindVariation},
+		{`{"development": {"status": "active", "targets": []}, "production": {"status": "inactive"}}`, KindTargeting},
+		{`{"something": 1}`, ""},
+		{`[1, 2]`, ""},
+	}
+	for _, tt := range tests {
+		k, ok := Detect(decode(t, tt.doc))
+		if tt.want == "" {
+			if ok {
+				t.Errorf("%s: expected no kind, got %s", tt.doc, k.Name)
+			}
+			continue
+		}
+		if !ok || k.Name != tt.want {
+			t.Errorf("%s: expected %s, got %q", tt.doc, tt.want, k.Name)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	feature, _ := Lookup(KindFeature)
+	targeting, _ := Lookup(KindTargeting)
+	audience, _ := Lookup(KindAudience)
+	variation, _ := Lookup(KindVariation)
+	project := &Project{
+		Audiences:  map[string]bool{"beta": true, "aud-id-1": true},
+		Variations: map[string]bool{"on": true, "off": true},
+		Variables:  []api.VariableDefinition{{Key: "enabled", Type: "Boolean"}},
+	}
+
+	validFeature := `{
+		"name": "Dark Mode", "key": "dark-mode", "type": "release",
+		"variables": [{"key": "enabled", "type": "Boolean"}, {"key": "theme", "type": "JSON"}],
+		"variations": [
+			{"key": "off", "name": "Off", "variables": {"enabled": false, "theme": {}}},
+			{"key": "on", "name": "On", "variables": {"enabled": true, "theme": {"bg": "#000"}}}
+		],
+		"controlVariation": "off",
+		"configurations": {"development": {"status": "active", "targets": [{
+			"audience": {"filters": {"operator": "and", "filters": [{"type": "audienceMatch", "comparator": "=", "_audiences": ["beta"]}]}},
+			"distribution": [{"_variation": "on", "percentage": 0.5}, {"_variation": "off", "percentage": 0.5}]
+		}]}}
+	}`
+
+	tests := []struct {
+		name string
+		kind Kind
+		doc  string
+		want []string
+	}{
+		{name: "valid feature", kind: feature, doc: validFeature},
+		{
+			name: "structural errors",
+			kind: feature,
+			doc:  `{"key": "Dark Mode", "type": "flag", "variables": [{"key": "enabled", "type": "bool"}], "colour": "red"}`,
+			want: []string{
+				"name: is required",
+				"colour: unknown property",
+				`key: must match the pattern ^[a-z0-9._-]+$`,
+				`type: must be one of "release", "experiment", "permission", "ops", got "flag"`,
+				`variables[0].type: must be one of "String", "Boolean", "Number", "JSON", got "bool"`,
+			},
+		},
+		{
+			name: "semantic errors",
+			kind: feature,
+			doc: `{
+				"name": "A", "key": "a",
+				"variables": [{"key": "enabled", "type": "Boolean"}],
+				"variations": [{"key": "on", "name": "On", "variables": {"enabled": "yes", "extra": 1}}],
+				"controlVariation": "off",
+				"configurations": {"production": {"status": "active", "targets": [{
+					"audience": {"filters": {"operator": "and", "filters": [{"type": "audienceMatch", "_audiences": ["gamma"]}]}},
+					"distribution": [{"_variation": "blue", "percentage": 0.4}]
+				}]}}
+			}`,
+			want: []string{
+				`variations[0].variables.enabled: expected a Boolean value, got string`,
+				`variations[0].variables.extra: variable "extra" is not declared`,
+				`controlVariation: variation "off" is not declared`,
+				`configurations.production.targets[0].distribution[0]._variation: variation "blue" does not exist`,
+				`configurations.production.targets[0].distribution: percentages add up to 0.40, must add up to 1`,
+				`configurations.production.targets[0].audience.filters.filters[0]._audiences[0]: audience "gamma" does not exist`,
+			},
+		},
+		{
+			name: "targeting",
+			kind: targeting,
+			doc:  `{"production": {"status": "on", "targets": [{"distribution": [{"_variation": "blue", "percentage": 1}]}]}}`,
+			want: []string{`production.status: must be one of "active", "inactive", got "on"`},
+		},
+		{
+			name: "targeting variations",
+			kind: targeting,
+			doc:  `{"production": {"status": "active", "targets": [{"audience": {"filters": {"operator": "and", "filters": [{"type": "all"}]}}, "distribution": [{"_variation": "blue", "percentage": 1}]}]}}`,
+			want: []string{`production.targets[0].distribution[0]._variation: variation "blue" does not exist`},
+		},
+		{
+			name: "audience",
+			kind: audience,
+			doc:  `{"name": "A", "key": "a", "filters": {"operator": "or", "filters": [{"operator": "and", "filters": [{"type": "audienceMatch", "_audiences": ["aud-id-1", "nope"]}]}]}}`,
+			want: []string{`filters.filters[0].filters[0]._audiences[1]: audience "nope" does not exist`},
+		},
+		{
+			name: "variation",
+			kind: variation,
+			doc:  `{"name": "On", "key": "on", "variables": {"enabled": 1}}`,
+			want: []string{`variables.enabled: expected a Boolean value, got integer`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.kind.Validate(decode(t, tt.doc), project)
+			if len(errs) != len(tt.want) {
+				t.Fatalf("expected %d problems, got %v", len(tt.want), errs)
+			}
+			for i, err := range errs {
+				if err.Error() != tt.want[i] {
+					t.Errorf("expected %q, got %q", tt.want[i], err.Error())
+				}
+			}
+		})
+	}
+
+	if errs := audience.Validate(decode(t, `{"name": "A", "key": "a", "filters": {"operator": "and", "filters": [{"type": "audienceMatch", "_audiences": ["nope"]}]}}`), nil); len(errs) != 0 {
+		t.Errorf("expected project checks to be skipped without a project, got %v", errs)
+	}
+}
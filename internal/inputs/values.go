@@ -0,0 +1,190 @@
+package inputs
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/135yshr/devcycle-cli/internal/jsonschema"
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+// Errors lists every problem found in a value.
+type Errors []jsonschema.Error
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// ValidateFeatureValues checks what api.ValidateFeatureRequest cannot: that
+// the validation schemas of the variables are usable and that each
+// variation gives every variable a value of its type that matches its
+// schema.
+func ValidateFeatureValues(req *api.CreateFeatureV2Request) error {
+	for _, v := range req.Variables {
+		if v.ValidationSchema != nil {
+			if err := ValidateSchema(v.ValidationSchema); err != nil {
+				return fmt.Errorf("invalid validation schema for variable %s: %w", v.Key, err)
+			}
+		}
+	}
+	for i, v := range req.Variations {
+		path := jsonschema.Key(jsonschema.Index("variations", i), "variables")
+		if errs := ValidateVariationValues(path, v.Variables, req.Variables); errs != nil {
+			return errs
+		}
+	}
+	return nil
+}
+
+// ValidateVariationValues checks that values, the variable values of a
+// variation found at path, holds a value for every variable and only for
+// declared variables, and that each value has the type of its variable and
+// matches its validation schema. It returns nil when values is valid.
+func ValidateVariationValues(path string, values map[string]any, variables []api.VariableDefinition) Errors {
+	return validateValues(path, values, variables, true)
+}
+
+// ValidateVariableValues is like ValidateVariationValues for a partial
+// update: variables without a value are not reported.
+func ValidateVariableValues(path string, values map[string]any, variables []api.VariableDefinition) Errors {
+	return validateValues(path, values, variables, false)
+}
+
+func validateValues(path string, values map[string]any, variables []api.VariableDefinition, complete bool) Errors {
+	defs := make(map[string]api.VariableDefinition, len(variables))
+	keys := make([]string, 0, len(variables)+len(values))
+	for _, v := range variables {
+		defs[v.Key] = v
+		keys = append(keys, v.Key)
+	}
+	for key := range values {
+		if _, ok := defs[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	keys = slices.Compact(keys)
+
+	var errs Errors
+	for _, key := range keys {
+		valuePath := jsonschema.Key(path, key)
+		value, hasValue := values[key]
+		def, declared := defs[key]
+		switch {
+		case !declared:
+			errs = append(errs, jsonschema.Error{Path: valuePath, Message: fmt.Sprintf("variable %q is not declared", key)})
+		case !hasValue:
+			if complete {
+				errs = append(errs, jsonschema.Error{Path: valuePath, Message: "is required"})
+			}
+		default:
+			errs = append(errs, validateValue(valuePath, value, def)...)
+		}
+	}
+	return errs
+}
+
+// validateValue checks value against the type and validation schema of the
+// variable def.
+func validateValue(path string, value any, def api.VariableDefinition) Errors {
+	value, err := normalizeValue(value)
+	if err != nil {
+		return Errors{{Path: path, Message: err.Error()}}
+	}
+	if !valueHasType(value, def.Type) {
+		return Errors{{Path: path, Message: fmt.Sprintf("expected a %s value, got %s", def.Type, jsonschema.TypeName(value))}}
+	}
+	if def.ValidationSchema == nil {
+		return nil
+	}
+	schema, err := validationSchema(def.ValidationSchema)
+	if err != nil {
+		return Errors{{Path: path, Message: fmt.Sprintf("cannot check the value of variable %q: %v", def.Key, err)}}
+	}
+	if errs := jsonschema.ValidateAt(schema, value, path); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// valueHasType reports whether v, a value as decoded by encoding/json, is a
+// value of the variable type typ. JSON variables hold objects.
+func valueHasType(v any, typ string) bool {
+	switch typ {
+	case "String":
+		_, ok := v.(string)
+		return ok
+	case "Boolean":
+		_, ok := v.(bool)
+		return ok
+	case "Number":
+		_, ok := v.(float64)
+		return ok
+	case "JSON":
+		_, ok := v.(map[string]any)
+		return ok
+	}
+	return true
+}
+
+// normalizeValue returns v as encoding/json decodes it, so that values
+// built in Go compare like values read from a file.
+func normalizeValue(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value: %w", err)
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("invalid value: %w", err)
+	}
+	return out, nil
+}
+
+// ValidateSchema checks that s is a usable validation schema.
+func ValidateSchema(s *api.VariableValidationSchema) error {
+	_, err := validationSchema(s)
+	return err
+}
+
+// validationSchema returns the JSON Schema that values restricted by s must
+// match.
+func validationSchema(s *api.VariableValidationSchema) (*jsonschema.Schema, error) {
+	switch s.SchemaType {
+	case "enum":
+		if len(s.EnumValues) == 0 {
+			return nil, fmt.Errorf("enumValues is required for an enum validation schema")
+		}
+		values, err := normalizeValue(s.EnumValues)
+		if err != nil {
+			return nil, err
+		}
+		return &jsonschema.Schema{Enum: values.([]any)}, nil
+	case "regex":
+		if s.RegexPattern == "" {
+			return nil, fmt.Errorf("regexPattern is required for a regex validation schema")
+		}
+		if _, err := regexp.Compile(s.RegexPattern); err != nil {
+			return nil, fmt.Errorf("invalid regexPattern: %w", err)
+		}
+		return &jsonschema.Schema{Pattern: s.RegexPattern}, nil
+	case "jsonSchema":
+		if s.JSONSchema == "" {
+			return nil, fmt.Errorf("jsonSchema is required for a jsonSchema validation schema")
+		}
+		schema, err := jsonschema.Parse([]byte(s.JSONSchema))
+		if err != nil {
+			return nil, fmt.Errorf("invalid jsonSchema: %w", err)
+		}
+		return schema, nil
+	}
+	return nil, fmt.Errorf("invalid schemaType: %q (must be one of: %s)", s.SchemaType, strings.Join(api.ValidationSchemaTypes, ", "))
+}
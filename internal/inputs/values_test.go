@@ -0,0 +1,155 @@
+package inputs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+func TestValidateVariationValues(t *testing.T) {
+	variables := []api.VariableDefinition{
+		{Key: "enabled", Type: "Boolean"},
+		{Key: "limit", Type: "Number"},
+		{Key: "title", Type: "String"},
+		{
+			Key:  "theme",
+			Type: "JSON",
+			ValidationSchema: &api.VariableValidationSchema{
+				SchemaType: "jsonSchema",
+				JSONSchema: `{
+					"type": "object",
+					"properties": {
+						"bg": {"type": "string", "pattern": "^#[0-9a-f]{3,6}$"},
+						"colors": {"type": "array", "items": {"type": "string"}}
+					},
+					"required": ["bg"]
+				}`,
+			},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		values map[string]any
+		want   []string
+	}{
+		{
+			name:   "valid",
+			values: map[string]any{"enabled": true, "limit": 10, "title": "Hi", "theme": map[string]any{"bg": "#000", "colors": []string{"red"}}},
+		},
+		{
+			name:   "missing and extra",
+			values: map[string]any{"enabled": true, "limit": 1.5, "theme": map[string]any{"bg": "#fff"}, "colour": "red"},
+			want: []string{
+				`variables.colour: variable "colour" is not declared`,
+				`variables.title: is required`,
+			},
+		},
+		{
+			name:   "mistyped",
+			values: map[string]any{"enabled": "true", "limit": "10", "title": 1, "theme": []any{}},
+			want: []string{
+				`variables.enabled: expected a Boolean value, got string`,
+				`variables.limit: expected a Number value, got string`,
+				`variables.theme: expected a JSON value, got array`,
+				`variables.title: expected a String value, got integer`,
+			},
+		},
+		{
+			name:   "JSON schema",
+			values: map[string]any{"enabled": true, "limit": 1, "title": "", "theme": map[string]any{"bg": "black", "colors": []any{"red", 2}}},
+			want: []string{
+				`variables.theme.bg: must match the pattern ^#[0-9a-f]{3,6}$`,
+				`variables.theme.colors[1]: expected string, got integer`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateVariationValues("variables", tt.values, variables)
+			if len(errs) != len(tt.want) {
+				t.Fatalf("expected %d errors, got %v", len(tt.want), errs)
+			}
+			for i, err := range errs {
+				if err.Error() != tt.want[i] {
+					t.Errorf("expected %q, got %q", tt.want[i], err.Error())
+				}
+			}
+		})
+	}
+
+	t.Run("partial", func(t *testing.T) {
+		if errs := ValidateVariableValues("", map[string]any{"enabled": false}, variables); errs != nil {
+			t.Errorf("expected missing values to be allowed, got %v", errs)
+		}
+		errs := ValidateVariableValues("", map[string]any{"enabled": 0}, variables)
+		if len(errs) != 1 || errs.Error() != "enabled: expected a Boolean value, got integer" {
+			t.Errorf("unexpected errors: %v", errs)
+		}
+	})
+}
+
+func TestVariableValidationSchema(t *testing.T) {
+	variables := []api.VariableDefinition{
+		{Key: "plan", Type: "String", ValidationSchema: &api.VariableValidationSchema{SchemaType: "enum", EnumValues: []any{"free", "pro"}}},
+		{Key: "code", Type: "String", ValidationSchema: &api.VariableValidationSchema{SchemaType: "regex", RegexPattern: `^[A-Z]{2}$`}},
+		{Key: "size", Type: "Number", ValidationSchema: &api.VariableValidationSchema{SchemaType: "enum", EnumValues: []any{1, 2}}},
+	}
+	if errs := ValidateVariationValues("", map[string]any{"plan": "pro", "code": "JP", "size": 2.0}, variables); errs != nil {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+	errs := ValidateVariationValues("", map[string]any{"plan": "team", "code": "jp", "size": 3}, variables)
+	want := `code: must match the pattern ^[A-Z]{2}$; plan: must be one of "free", "pro", got "team"; size: must be one of 1, 2, got 3`
+	if errs.Error() != want {
+		t.Errorf("expected %q, got %q", want, errs.Error())
+	}
+
+	for _, s := range []*api.VariableValidationSchema{
+		{SchemaType: "enum"},
+		{SchemaType: "regex", RegexPattern: "("},
+		{SchemaType: "jsonSchema", JSONSchema: "{"},
+		{SchemaType: "range"},
+	} {
+		if err := ValidateSchema(s); err == nil {
+			t.Errorf("expected an error for %+v", s)
+		}
+	}
+
+	broken := []api.VariableDefinition{{Key: "code", Type: "String", ValidationSchema: &api.VariableValidationSchema{SchemaType: "regex", RegexPattern: "("}}}
+	errs = ValidateVariationValues("", map[string]any{"code": "x"}, broken)
+	if len(errs) != 1 || !strings.Contains(errs[0].Message, "invalid regexPattern") {
+		t.Errorf("expected an invalid schema error, got %v", errs)
+	}
+}
+
+func TestValidateFeatureValues(t *testing.T) {
+	req := &api.CreateFeatureV2Request{
+		Name: "Test",
+		Key:  "test",
+		Type: "release",
+		Variables: []api.VariableDefinition{
+			{Key: "enabled", Type: "Boolean"},
+			{Key: "label", Type: "String"},
+		},
+		Variations: []api.VariationDefinition{
+			{Key: "off", Name: "Off", Variables: map[string]any{"enabled": false, "label": "Off"}},
+			{Key: "on", Name: "On", Variables: map[string]any{"enabled": "yes"}},
+		},
+	}
+	err := ValidateFeatureValues(req)
+	want := "variations[1].variables.enabled: expected a Boolean value, got string; variations[1].variables.label: is required"
+	if err == nil || err.Error() != want {
+		t.Errorf("expected %q, got %v", want, err)
+	}
+
+	req.Variations[1].Variables = map[string]any{"enabled": true, "label": "On"}
+	if err := ValidateFeatureValues(req); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	req.Variables = append(req.Variables, api.VariableDefinition{Key: "theme", Type: "JSON", ValidationSchema: &api.VariableValidationSchema{SchemaType: "jsonSchema"}})
+	if err := ValidateFeatureValues(req); err == nil || !strings.Contains(err.Error(), "invalid validation schema for variable theme") {
+		t.Errorf("expected an invalid validation schema error, got %v", err)
+	}
+}
@@ -0,0 +1,192 @@
+// Package jsondiff computes RFC 6902 JSON Patch operations between two values
+// and renders them as a human-readable diff.
+package jsondiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/135yshr/devcycle-cli/internal/output"
+)
+
+// Operation names defined by RFC 6902.
+const (
+	OpAdd     = "add"
+	OpRemove  = "remove"
+	OpReplace = "replace"
+	OpMove    = "move"
+	OpTest    = "test"
+)
+
+// Op is a single JSON Patch operation.
+// Old holds the previous value for remove and replace operations; it is only
+// used for rendering and is not part of the serialized patch.
+type Op struct {
+	Op    string `json:"op" yaml:"op"`
+	From  string `json:"from,omitempty" yaml:"from,omitempty"`
+	Path  string `json:"path" yaml:"path"`
+	Value any    `json:"value,omitempty" yaml:"value,omitempty"`
+	Old   any    `json:"-" yaml:"-"`
+}
+
+// MarshalJSON always writes the value of add, replace and test operations,
+// even when it is null, as RFC 6902 requires it for them.
+func (o Op) MarshalJSON() ([]byte, error) {
+	type op Op
+	switch o.Op {
+	case OpAdd, OpReplace, OpTest:
+		return json.Marshal(struct {
+			op
+			Value any `json:"value"`
+		}{op(o), o.Value})
+	}
+	return json.Marshal(op(o))
+}
+
+// Normalize round-trips v through encoding/json so that structs, maps and
+// slices can be compared as plain JSON values.
+func Normalize(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal value: %w", err)
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal value: %w", err)
+	}
+	return out, nil
+}
+
+// Diff returns the operations that transform a into b.
+func Diff(a, b any) ([]Op, error) {
+	na, err := Normalize(a)
+	if err != nil {
+		return nil, err
+	}
+	nb, err := Normalize(b)
+	if err != nil {
+		return nil, err
+	}
+	return Compare("", na, nb), nil
+}
+
+// Compare returns the operations that transform a into b, rooted at path.
+// Both values must already be normalized.
+func Compare(path string, a, b any) []Op {
+	switch av := a.(type) {
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok {
+			break
+		}
+		var ops []Op
+		for _, k := range sortedKeys(av) {
+			p := path + "/" + EscapePointer(k)
+			if nv, ok := bv[k]; ok {
+				ops = append(ops, Compare(p, av[k], nv)...)
+			} else {
+				ops = append(ops, Op{Op: OpRemove, Path: p, Old: av[k]})
+			}
+		}
+		for _, k := range sortedKeys(bv) {
+			if _, ok := av[k]; !ok {
+				ops = append(ops, Op{Op: OpAdd, Path: path + "/" + EscapePointer(k), Value: bv[k]})
+			}
+		}
+		return ops
+	case []any:
+		bv, ok := b.([]any)
+		if !ok {
+			break
+		}
+		var ops []Op
+		common := min(len(av), len(bv))
+		for i := 0; i < common; i++ {
+			ops = append(ops, Compare(path+"/"+strconv.Itoa(i), av[i], bv[i])...)
+		}
+		for i := len(av) - 1; i >= common; i-- {
+			ops = append(ops, Op{Op: OpRemove, Path: path + "/" + strconv.Itoa(i), Old: av[i]})
+		}
+		for i := common; i < len(bv); i++ {
+			ops = append(ops, Op{Op: OpAdd, Path: path + "/" + strconv.Itoa(i), Value: bv[i]})
+		}
+		return ops
+	}
+
+	if reflect.DeepEqual(a, b) {
+		return nil
+	}
+	return []Op{{Op: OpReplace, Path: path, Value: b, Old: a}}
+}
+
+// MergePatch applies patch to target following JSON Merge Patch (RFC 7386):
+// objects are merged recursively, null removes a member and any other value
+// replaces the target value. Both values must already be normalized; target
+// is not modified.
+func MergePatch(target, patch any) any {
+	pm, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	tm, ok := target.(map[string]any)
+	out := make(map[string]any, len(tm)+len(pm))
+	if ok {
+		for k, v := range tm {
+			out[k] = v
+		}
+	}
+	for k, v := range pm {
+		if v == nil {
+			delete(out, k)
+			continue
+		}
+		out[k] = MergePatch(out[k], v)
+	}
+	return out
+}
+
+// EscapePointer escapes a single JSON Pointer reference token (RFC 6901).
+func EscapePointer(s string) string {
+	s = strings.ReplaceAll(s, "~", "~0")
+	return strings.ReplaceAll(s, "/", "~1")
+}
+
+// Write renders ops as a line-oriented diff. When color is true, additions,
+// removals and replacements are highlighted with ANSI colors.
+func Write(w io.Writer, ops []Op, color bool) {
+	for _, op := range ops {
+		switch op.Op {
+		case OpAdd:
+			fmt.Fprintln(w, output.Colorize(fmt.Sprintf("+ %s: %s", op.Path, FormatValue(op.Value)), output.ColorGreen, color))
+		case OpRemove:
+			fmt.Fprintln(w, output.Colorize(fmt.Sprintf("- %s: %s", op.Path, FormatValue(op.Old)), output.ColorRed, color))
+		case OpReplace:
+			fmt.Fprintln(w, output.Colorize(fmt.Sprintf("~ %s: %s -> %s", op.Path, FormatValue(op.Old), FormatValue(op.Value)), output.ColorYellow, color))
+		case OpMove:
+			fmt.Fprintln(w, output.Colorize(fmt.Sprintf("> %s -> %s", op.From, op.Path), output.ColorCyan, color))
+		}
+	}
+}
+
+// FormatValue renders v as compact JSON.
+func FormatValue(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,151 @@
+package jsondiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	t.Run("identical values", func(t *testing.T) {
+		ops, err := Diff(map[string]any{"a": 1}, map[string]any{"a": 1})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(ops) != 0 {
+			t.Errorf("expected no ops, got %v", ops)
+		}
+	})
+
+	t.Run("object changes", func(t *testing.T) {
+		a := map[string]any{"keep": "x", "change": 1, "drop": true}
+		b := map[string]any{"keep": "x", "change": 2, "new": "y"}
+
+		ops, err := Diff(a, b)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(ops) != 3 {
+			t.Fatalf("expected 3 ops, got %d: %v", len(ops), ops)
+		}
+		if ops[0].Op != OpReplace || ops[0].Path != "/change" {
+			t.Errorf("expected replace /change, got %s %s", ops[0].Op, ops[0].Path)
+		}
+		if ops[1].Op != OpRemove || ops[1].Path != "/drop" {
+			t.Errorf("expected remove /drop, got %s %s", ops[1].Op, ops[1].Path)
+		}
+		if ops[2].Op != OpAdd || ops[2].Path != "/new" {
+			t.Errorf("expected add /new, got %s %s", ops[2].Op, ops[2].Path)
+		}
+	})
+
+	t.Run("array shrink removes from the end", func(t *testing.T) {
+		ops, err := Diff([]int{1, 2, 3}, []int{1})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(ops) != 2 {
+			t.Fatalf("expected 2 ops, got %v", ops)
+		}
+		if ops[0].Path != "/2" || ops[1].Path != "/1" {
+			t.Errorf("expected removals at /2 then /1, got %s and %s", ops[0].Path, ops[1].Path)
+		}
+	})
+
+	t.Run("array grow", func(t *testing.T) {
+		ops, err := Diff([]string{"a"}, []string{"a", "b"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(ops) != 1 || ops[0].Op != OpAdd || ops[0].Path != "/1" {
+			t.Errorf("expected add /1, got %v", ops)
+		}
+	})
+
+	t.Run("type change is a replace", func(t *testing.T) {
+		ops, err := Diff(map[string]any{"v": []any{1}}, map[string]any{"v": "x"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(ops) != 1 || ops[0].Op != OpReplace {
+			t.Errorf("expected single replace, got %v", ops)
+		}
+	})
+}
+
+func TestMergePatch(t *testing.T) {
+	target := map[string]any{
+		"name":     "Old",
+		"tags":     []any{"a", "b"},
+		"settings": map[string]any{"publicName": "x", "optInEnabled": true},
+		"drop":     "me",
+	}
+	patch := map[string]any{
+		"name":     "New",
+		"tags":     []any{"c"},
+		"settings": map[string]any{"optInEnabled": false},
+		"drop":     nil,
+	}
+	want := map[string]any{
+		"name":     "New",
+		"tags":     []any{"c"},
+		"settings": map[string]any{"publicName": "x", "optInEnabled": false},
+	}
+
+	got := MergePatch(target, patch)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if target["name"] != "Old" || target["drop"] != "me" {
+		t.Error("expected target to be left unchanged")
+	}
+	if got := MergePatch(target, "scalar"); got != "scalar" {
+		t.Errorf("expected non-object patch to replace the target, got %v", got)
+	}
+}
+
+func TestEscapePointer(t *testing.T) {
+	if got := EscapePointer("a/b~c"); got != "a~1b~0c" {
+		t.Errorf("expected a~1b~0c, got %s", got)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	ops := []Op{
+		{Op: OpAdd, Path: "/a", Value: 1},
+		{Op: OpRemove, Path: "/b", Old: "x"},
+		{Op: OpReplace, Path: "/c", Old: true, Value: false},
+	}
+
+	var buf bytes.Buffer
+	Write(&buf, ops, false)
+
+	out := buf.String()
+	for _, want := range []string{`+ /a: 1`, `- /b: "x"`, `~ /c: true -> false`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got %s", want, out)
+		}
+	}
+	if strings.Contains(out, "\033[") {
+		t.Error("expected no color codes when color is disabled")
+	}
+}
+
+func TestOpMarshalJSON(t *testing.T) {
+	ops := []Op{
+		{Op: OpReplace, Path: "/a", Old: 1, Value: nil},
+		{Op: OpAdd, Path: "/b", Value: nil},
+		{Op: OpRemove, Path: "/c", Old: "x"},
+		{Op: OpMove, From: "/d", Path: "/e"},
+	}
+	data, err := json.Marshal(ops)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `[{"op":"replace","path":"/a","value":null},{"op":"add","path":"/b","value":null},{"op":"remove","path":"/c"},{"op":"move","from":"/d","path":"/e"}]`
+	if string(data) != want {
+		t.Errorf("expected %s, got %s", want, data)
+	}
+}
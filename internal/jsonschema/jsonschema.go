@@ -0,0 +1,279 @@
+// Package jsonschema generates JSON Schemas (draft 2020-12) from Go types and
+// validates JSON values against them.
+//
+// Only the keywords dvcx needs are supported: type, enum, const, the object,
+// array, string and number constraints, the allOf, anyOf, oneOf and not
+// combinators, and $ref to the $defs (or definitions) of the root schema.
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Draft is the $schema URI of the schemas generated by this package.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema. The zero Schema accepts any value.
+type Schema struct {
+	Schema      string `json:"$schema,omitempty"`
+	ID          string `json:"$id,omitempty"`
+	Ref         string `json:"$ref,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Type        Types  `json:"type,omitempty"`
+	Format      string `json:"format,omitempty"`
+	Enum        []any  `json:"enum,omitempty"`
+	Const       any    `json:"const,omitempty"`
+	Default     any    `json:"default,omitempty"`
+
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+
+	Items       *Schema `json:"items,omitempty"`
+	MinItems    *int    `json:"minItems,omitempty"`
+	MaxItems    *int    `json:"maxItems,omitempty"`
+	UniqueItems bool    `json:"uniqueItems,omitempty"`
+
+	MinLength *int   `json:"minLength,omitempty"`
+	MaxLength *int   `json:"maxLength,omitempty"`
+	Pattern   string `json:"pattern,omitempty"`
+
+	Minimum          *float64 `json:"minimum,omitempty"`
+	Maximum          *float64 `json:"maximum,omitempty"`
+	ExclusiveMinimum *float64 `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum *float64 `json:"exclusiveMaximum,omitempty"`
+
+	AllOf []*Schema `json:"allOf,omitempty"`
+	AnyOf []*Schema `json:"anyOf,omitempty"`
+	OneOf []*Schema `json:"oneOf,omitempty"`
+	Not   *Schema   `json:"not,omitempty"`
+
+	Defs        map[string]*Schema `json:"$defs,omitempty"`
+	Definitions map[string]*Schema `json:"definitions,omitempty"`
+
+	// boolean is set for the schemas true, which accepts any value, and
+	// false, which accepts none.
+	boolean *bool
+}
+
+// Bool returns the schema true or false.
+func Bool(b bool) *Schema {
+	return &Schema{boolean: &b}
+}
+
+type schemaFields Schema
+
+// MarshalJSON writes boolean schemas as true or false. Characters such as
+// '<' are not escaped, so that patterns and enums stay readable.
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	if s.boolean != nil {
+		return json.Marshal(*s.boolean)
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode((*schemaFields)(s)); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// UnmarshalJSON accepts boolean schemas as well as objects.
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	var b bool
+	if err := json.Unmarshal(data, &b); err == nil {
+		*s = Schema{boolean: &b}
+		return nil
+	}
+	return json.Unmarshal(data, (*schemaFields)(s))
+}
+
+// Parse decodes a schema from JSON.
+func Parse(data []byte) (*Schema, error) {
+	var s Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("invalid JSON Schema: %w", err)
+	}
+	return &s, nil
+}
+
+// Types is the value of the type keyword, either a single type or a list.
+type Types []string
+
+// MarshalJSON writes a single type as a string.
+func (t Types) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+// UnmarshalJSON accepts a string or a list of strings.
+func (t *Types) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*t = Types{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return fmt.Errorf("type must be a string or an array of strings")
+	}
+	*t = many
+	return nil
+}
+
+// Property returns the schema of the property reached by following names
+// from s, through the properties of each object, or nil when there is none.
+// References to $defs are followed.
+func (s *Schema) Property(names ...string) *Schema {
+	cur := s
+	for _, name := range names {
+		cur = s.resolve(cur)
+		if cur == nil || cur.Properties == nil {
+			return nil
+		}
+		cur = cur.Properties[name]
+	}
+	return s.resolve(cur)
+}
+
+// Def returns the schema defined in $defs under name, or nil.
+func (s *Schema) Def(name string) *Schema {
+	return s.Defs[name]
+}
+
+// resolve follows the $ref of sub, which must belong to s.
+func (s *Schema) resolve(sub *Schema) *Schema {
+	for sub != nil && sub.Ref != "" {
+		next := s.lookup(sub.Ref)
+		if next == nil || next == sub {
+			return sub
+		}
+		sub = next
+	}
+	return sub
+}
+
+// lookup returns the schema that ref points to within s, or nil. Only
+// references to the root and to its $defs and definitions are supported.
+func (s *Schema) lookup(ref string) *Schema {
+	switch {
+	case ref == "#":
+		return s
+	case strings.HasPrefix(ref, "#/$defs/"):
+		return s.Defs[strings.TrimPrefix(ref, "#/$defs/")]
+	case strings.HasPrefix(ref, "#/definitions/"):
+		return s.Definitions[strings.TrimPrefix(ref, "#/definitions/")]
+	}
+	return nil
+}
+
+// For returns the schema of the Go type of v.
+//
+// Struct fields are named after their json tags, and structs do not allow
+// properties they do not declare. Named struct types other than the root
+// are placed in $defs and referenced, so recursive types can be described.
+// Interface values accept anything. No property is required; callers add
+// the constraints that the types cannot express.
+func For(v any) *Schema {
+	r := &reflector{defs: make(map[string]*Schema)}
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	var s *Schema
+	if t.Kind() == reflect.Struct {
+		s = r.object(t)
+	} else {
+		s = r.schema(t)
+	}
+	s.Schema = Draft
+	if len(r.defs) > 0 {
+		s.Defs = r.defs
+	}
+	return s
+}
+
+type reflector struct {
+	defs map[string]*Schema
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func (r *reflector) schema(t reflect.Type) *Schema {
+	if t == timeType {
+		return &Schema{Type: Types{"string"}, Format: "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return r.schema(t.Elem())
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.String:
+		return &Schema{Type: Types{"string"}}
+	case reflect.Bool:
+		return &Schema{Type: Types{"boolean"}}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: Types{"integer"}}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: Types{"number"}}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: Types{"array"}, Items: r.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: Types{"object"}, AdditionalProperties: r.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return r.object(t)
+		}
+		name := t.Name()
+		if _, ok := r.defs[name]; !ok {
+			r.defs[name] = nil // reserve the name while recursing
+			r.defs[name] = r.object(t)
+		}
+		return &Schema{Ref: "#/$defs/" + name}
+	}
+	return &Schema{}
+}
+
+// object returns the schema of the struct type t.
+func (r *reflector) object(t reflect.Type) *Schema {
+	s := &Schema{Type: Types{"object"}, Properties: make(map[string]*Schema), AdditionalProperties: Bool(false)}
+	r.fields(s, t)
+	return s
+}
+
+func (r *reflector) fields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				r.fields(s, ft)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		s.Properties[name] = r.schema(f.Type)
+	}
+}
@@ -0,0 +1,168 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testNode struct {
+	Name     string            `json:"name"`
+	Weight   float64           `json:"weight,omitempty"`
+	Count    int               `json:"count,omitempty"`
+	Enabled  *bool             `json:"enabled,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Value    any               `json:"value,omitempty"`
+	Children []testNode        `json:"children,omitempty"`
+	Created  time.Time         `json:"created"`
+	Ignored  string            `json:"-"`
+	internal string
+}
+
+func decode(t *testing.T, data string) any {
+	t.Helper()
+	var v any
+	if err := json.Unmarshal([]byte(data), &v); err != nil {
+		t.Fatalf("invalid test JSON: %v", err)
+	}
+	return v
+}
+
+func TestFor(t *testing.T) {
+	s := For(&testNode{})
+	if s.Schema != Draft || len(s.Type) != 1 || s.Type[0] != "object" {
+		t.Fatalf("unexpected root: %+v", s)
+	}
+	for name, want := range map[string]string{"name": "string", "weight": "number", "count": "integer", "enabled": "boolean", "labels": "object", "children": "array", "created": "string"} {
+		prop := s.Properties[name]
+		if prop == nil || len(prop.Type) != 1 || prop.Type[0] != want {
+			t.Errorf("property %s: expected type %s, got %+v", name, want, prop)
+		}
+	}
+	if _, ok := s.Properties["Ignored"]; ok {
+		t.Error("expected fields tagged - to be skipped")
+	}
+	if _, ok := s.Properties["internal"]; ok {
+		t.Error("expected unexported fields to be skipped")
+	}
+	if s.Properties["children"].Items.Ref != "#/$defs/testNode" || s.Def("testNode") == nil {
+		t.Errorf("expected recursive type in $defs, got %+v", s.Properties["children"].Items)
+	}
+	if s.Property("children") == nil || s.Property("labels").AdditionalProperties.Type[0] != "string" {
+		t.Error("expected Property to find nested schemas")
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("failed to marshal schema: %v", err)
+	}
+	if !strings.Contains(string(data), `"additionalProperties":false`) || !strings.Contains(string(data), `"$schema":"`+Draft+`"`) {
+		t.Errorf("unexpected encoding: %s", data)
+	}
+	parsed, err := Parse(data)
+	if err != nil {
+		t.Fatalf("failed to parse schema: %v", err)
+	}
+	if errs := Validate(parsed, decode(t, `{"name": "a", "extra": 1}`)); len(errs) != 1 || errs[0].Path != "extra" {
+		t.Errorf("expected the parsed schema to reject unknown properties, got %v", errs)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	s := For(testNode{})
+	s.Required = []string{"name"}
+	s.Properties["name"].MinLength = new(int)
+	*s.Properties["name"].MinLength = 1
+	s.Def("testNode").Required = []string{"name"}
+
+	tests := []struct {
+		name  string
+		value string
+		want  []string
+	}{
+		{name: "valid", value: `{"name": "a", "count": 2, "children": [{"name": "b", "value": [1, "x"]}]}`},
+		{name: "missing required", value: `{}`, want: []string{"name: is required"}},
+		{name: "wrong type", value: `{"name": 1}`, want: []string{"name: expected string, got integer"}},
+		{name: "not an integer", value: `{"name": "a", "count": 1.5}`, want: []string{"count: expected integer, got number"}},
+		{name: "unknown property", value: `{"name": "a", "colour": "red"}`, want: []string{"colour: unknown property"}},
+		{name: "nested", value: `{"name": "a", "children": [{"name": "b"}, {"enabled": "yes"}]}`, want: []string{
+			"children[1].name: is required",
+			"children[1].enabled: expected boolean, got string",
+		}},
+		{name: "map values", value: `{"name": "a", "labels": {"team x": 1}}`, want: []string{`labels["team x"]: expected string, got integer`}},
+		{name: "min length", value: `{"name": ""}`, want: []string{"name: must be at least 1 characters long"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := Validate(s, decode(t, tt.value))
+			if len(errs) != len(tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, errs)
+			}
+			for i, err := range errs {
+				if err.Error() != tt.want[i] {
+					t.Errorf("expected %q, got %q", tt.want[i], err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestValidateKeywords(t *testing.T) {
+	schema := `{
+		"type": "object",
+		"properties": {
+			"theme": {"enum": ["light", "dark"]},
+			"size": {"type": "number", "minimum": 1, "exclusiveMaximum": 10},
+			"code": {"type": "string", "pattern": "^[A-Z]{2}$"},
+			"tags": {"type": "array", "items": {"type": "string"}, "uniqueItems": true, "maxItems": 3},
+			"id": {"anyOf": [{"type": "string"}, {"type": "integer"}]},
+			"mode": {"oneOf": [{"const": "a"}, {"type": "string", "maxLength": 1}]},
+			"nullable": {"type": ["string", "null"]},
+			"never": false
+		},
+		"required": ["theme"]
+	}`
+	s, err := Parse([]byte(schema))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	valid := `{"theme": "dark", "size": 9.5, "code": "JP", "tags": ["a", "b"], "id": 3, "mode": "b", "nullable": null}`
+	if errs := Validate(s, decode(t, valid)); len(errs) != 0 {
+		t.Errorf("expected no errors, got %v", errs)
+	}
+
+	invalid := `{"theme": "blue", "size": 10, "code": "jp", "tags": ["a", "a", "b", "c"], "id": true, "mode": "a", "never": 1}`
+	want := []string{
+		"code: must match the pattern ^[A-Z]{2}$",
+		"id: does not match any of the allowed schemas",
+		"mode: must match exactly one of the allowed schemas, matches 2",
+		"never: is not allowed",
+		"size: must be less than 10",
+		"tags: must have at most 3 items, has 4",
+		"tags[1]: duplicates item 0",
+		`theme: must be one of "light", "dark", got "blue"`,
+	}
+	errs := Validate(s, decode(t, invalid))
+	if len(errs) != len(want) {
+		t.Fatalf("expected %d errors, got %v", len(want), errs)
+	}
+	for i, err := range errs {
+		if err.Error() != want[i] {
+			t.Errorf("expected %q, got %q", want[i], err.Error())
+		}
+	}
+}
+
+func TestKeyAndIndex(t *testing.T) {
+	if got := Index(Key(Key("", "variations"), "x"), 2); got != "variations.x[2]" {
+		t.Errorf("unexpected path %q", got)
+	}
+	if got := Key("configs", "prod env"); got != `configs["prod env"]` {
+		t.Errorf("unexpected path %q", got)
+	}
+	if got := Key("", "_variation"); got != "_variation" {
+		t.Errorf("unexpected path %q", got)
+	}
+}
@@ -0,0 +1,335 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Error is a part of a value that does not match its schema, or that a
+// check found wrong. Path locates the part, as in variations[0].key; it is
+// empty for the value itself.
+type Error struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (e Error) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+// Key returns the path of the property name of the value at path.
+func Key(path, name string) string {
+	if !isIdentifier(name) {
+		return path + "[" + strconv.Quote(name) + "]"
+	}
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// Index returns the path of the element i of the array at path.
+func Index(path string, i int) string {
+	return path + "[" + strconv.Itoa(i) + "]"
+}
+
+func isIdentifier(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r == '_' || r == '$' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z':
+		case i > 0 && (r == '-' || r >= '0' && r <= '9'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Validate checks v, a value as decoded by encoding/json into an any,
+// against s. The errors are in document order, with the properties of
+// objects in name order.
+func Validate(s *Schema, v any) []Error {
+	return ValidateAt(s, v, "")
+}
+
+// ValidateAt is like Validate for a value found at path in a larger
+// document; the paths of the errors start with path.
+func ValidateAt(s *Schema, v any, path string) []Error {
+	vd := &validator{root: s}
+	vd.validate(s, v, path)
+	return vd.errs
+}
+
+type validator struct {
+	root *Schema
+	errs []Error
+}
+
+func (vd *validator) errorf(path, format string, args ...any) {
+	vd.errs = append(vd.errs, Error{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// matches reports whether v is valid against s, without recording errors.
+func (vd *validator) matches(s *Schema, v any, path string) bool {
+	sub := &validator{root: vd.root}
+	sub.validate(s, v, path)
+	return len(sub.errs) == 0
+}
+
+func (vd *validator) validate(s *Schema, v any, path string) {
+	if s == nil {
+		return
+	}
+	if s.boolean != nil {
+		if !*s.boolean {
+			vd.errorf(path, "is not allowed")
+		}
+		return
+	}
+	if s.Ref != "" {
+		ref := vd.root.lookup(s.Ref)
+		if ref == nil {
+			vd.errorf(path, "schema reference %s cannot be resolved", s.Ref)
+			return
+		}
+		vd.validate(ref, v, path)
+	}
+
+	if len(s.Type) > 0 && !matchesAnyType(s.Type, v) {
+		vd.errorf(path, "expected %s, got %s", strings.Join(s.Type, " or "), TypeName(v))
+		return
+	}
+	if len(s.Enum) > 0 && !containsValue(s.Enum, v) {
+		vd.errorf(path, "must be one of %s, got %s", formatValues(s.Enum), formatValue(v))
+	}
+	if s.Const != nil && !equal(s.Const, v) {
+		vd.errorf(path, "must be %s, got %s", formatValue(s.Const), formatValue(v))
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		vd.validateObject(s, v, path)
+	case []any:
+		vd.validateArray(s, v, path)
+	case string:
+		vd.validateString(s, v, path)
+	case float64:
+		vd.validateNumber(s, v, path)
+	}
+
+	for _, sub := range s.AllOf {
+		vd.validate(sub, v, path)
+	}
+	if len(s.AnyOf) > 0 {
+		matched := false
+		for _, sub := range s.AnyOf {
+			if vd.matches(sub, v, path) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			vd.errorf(path, "does not match any of the allowed schemas")
+		}
+	}
+	if len(s.OneOf) > 0 {
+		n := 0
+		for _, sub := range s.OneOf {
+			if vd.matches(sub, v, path) {
+				n++
+			}
+		}
+		if n != 1 {
+			vd.errorf(path, "must match exactly one of the allowed schemas, matches %d", n)
+		}
+	}
+	if s.Not != nil && vd.matches(s.Not, v, path) {
+		vd.errorf(path, "matches a schema it must not match")
+	}
+}
+
+func (vd *validator) validateObject(s *Schema, v map[string]any, path string) {
+	for _, name := range s.Required {
+		if _, ok := v[name]; !ok {
+			vd.errorf(Key(path, name), "is required")
+		}
+	}
+	names := make([]string, 0, len(v))
+	for name := range v {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if prop, ok := s.Properties[name]; ok {
+			vd.validate(prop, v[name], Key(path, name))
+			continue
+		}
+		if s.AdditionalProperties != nil {
+			if b := s.AdditionalProperties.boolean; b != nil && !*b {
+				vd.errorf(Key(path, name), "unknown property")
+				continue
+			}
+			vd.validate(s.AdditionalProperties, v[name], Key(path, name))
+		}
+	}
+}
+
+func (vd *validator) validateArray(s *Schema, v []any, path string) {
+	if s.MinItems != nil && len(v) < *s.MinItems {
+		vd.errorf(path, "must have at least %d items, has %d", *s.MinItems, len(v))
+	}
+	if s.MaxItems != nil && len(v) > *s.MaxItems {
+		vd.errorf(path, "must have at most %d items, has %d", *s.MaxItems, len(v))
+	}
+	if s.UniqueItems {
+		for i := range v {
+			for j := 0; j < i; j++ {
+				if equal(v[i], v[j]) {
+					vd.errorf(Index(path, i), "duplicates item %d", j)
+					break
+				}
+			}
+		}
+	}
+	if s.Items != nil {
+		for i, item := range v {
+			vd.validate(s.Items, item, Index(path, i))
+		}
+	}
+}
+
+func (vd *validator) validateString(s *Schema, v string, path string) {
+	n := utf8.RuneCountInString(v)
+	if s.MinLength != nil && n < *s.MinLength {
+		vd.errorf(path, "must be at least %d characters long", *s.MinLength)
+	}
+	if s.MaxLength != nil && n > *s.MaxLength {
+		vd.errorf(path, "must be at most %d characters long", *s.MaxLength)
+	}
+	if s.Pattern != "" {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			vd.errorf(path, "schema pattern %q is invalid: %v", s.Pattern, err)
+		} else if !re.MatchString(v) {
+			vd.errorf(path, "must match the pattern %s", s.Pattern)
+		}
+	}
+}
+
+func (vd *validator) validateNumber(s *Schema, v float64, path string) {
+	if s.Minimum != nil && v < *s.Minimum {
+		vd.errorf(path, "must be at least %v", *s.Minimum)
+	}
+	if s.Maximum != nil && v > *s.Maximum {
+		vd.errorf(path, "must be at most %v", *s.Maximum)
+	}
+	if s.ExclusiveMinimum != nil && v <= *s.ExclusiveMinimum {
+		vd.errorf(path, "must be greater than %v", *s.ExclusiveMinimum)
+	}
+	if s.ExclusiveMaximum != nil && v >= *s.ExclusiveMaximum {
+		vd.errorf(path, "must be less than %v", *s.ExclusiveMaximum)
+	}
+}
+
+func matchesAnyType(types Types, v any) bool {
+	for _, t := range types {
+		if matchesType(t, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesType(t string, v any) bool {
+	switch t {
+	case "object":
+		_, ok := v.(map[string]any)
+		return ok
+	case "array":
+		_, ok := v.([]any)
+		return ok
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	case "integer":
+		f, ok := v.(float64)
+		return ok && f == math.Trunc(f)
+	case "null":
+		return v == nil
+	}
+	return false
+}
+
+// TypeName returns the JSON type of v, a value as decoded by encoding/json:
+// object, array, string, boolean, integer, number or null.
+func TypeName(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func containsValue(values []any, v any) bool {
+	for _, e := range values {
+		if equal(e, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// equal compares two JSON values by their encoding, which sorts object
+// keys.
+func equal(a, b any) bool {
+	da, errA := json.Marshal(a)
+	db, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(da) == string(db)
+}
+
+func formatValue(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+func formatValues(values []any) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = formatValue(v)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,230 @@
+// Package listfilter filters, searches and sorts the resources shown by
+// list commands. Each resource describes its fields with a Fields value, and
+// the same Options apply to every resource that has the fields they use.
+package listfilter
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Fields returns the filterable fields of a resource of type T. A nil
+// function means the resource does not have the field; options that need
+// it are rejected by Apply.
+type Fields[T any] struct {
+	Key         func(T) string
+	Name        func(T) string
+	Description func(T) string
+	Type        func(T) string
+	Status      func(T) string
+	Tags        func(T) []string
+	CreatedAt   func(T) time.Time
+	UpdatedAt   func(T) time.Time
+}
+
+// Sort orders accepted in Options.Sort.
+const (
+	SortKey     = "key"
+	SortName    = "name"
+	SortCreated = "created"
+	SortUpdated = "updated"
+)
+
+// Options selects and orders resources. Zero values do not filter.
+type Options struct {
+	Type   string
+	Status string
+	// Tags keeps resources that have all of the tags.
+	Tags []string
+	// Search matches the key, name or description. It is a
+	// case-insensitive substring, or a regular expression when written
+	// as /expr/.
+	Search        string
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	UpdatedAfter  time.Time
+	UpdatedBefore time.Time
+	Sort          string
+	Reverse       bool
+}
+
+// IsRegexpSearch reports whether search is a regular expression (/expr/)
+// rather than a substring.
+func IsRegexpSearch(search string) bool {
+	return len(search) >= 2 && strings.HasPrefix(search, "/") && strings.HasSuffix(search, "/")
+}
+
+// matcher returns a function that reports whether a string matches search.
+func matcher(search string) (func(string) bool, error) {
+	if IsRegexpSearch(search) {
+		re, err := regexp.Compile(search[1 : len(search)-1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid search expression: %w", err)
+		}
+		return re.MatchString, nil
+	}
+	needle := strings.ToLower(search)
+	return func(s string) bool { return strings.Contains(strings.ToLower(s), needle) }, nil
+}
+
+// Apply returns the items that match opts, in the order opts selects. The
+// input slice is not modified.
+func Apply[T any](items []T, f Fields[T], opts Options) ([]T, error) {
+	if err := f.check(opts); err != nil {
+		return nil, err
+	}
+
+	var search func(string) bool
+	if opts.Search != "" {
+		m, err := matcher(opts.Search)
+		if err != nil {
+			return nil, err
+		}
+		search = m
+	}
+
+	out := make([]T, 0, len(items))
+	for _, item := range items {
+		if opts.Type != "" && !strings.EqualFold(f.Type(item), opts.Type) {
+			continue
+		}
+		if opts.Status != "" && !strings.EqualFold(f.Status(item), opts.Status) {
+			continue
+		}
+		if len(opts.Tags) > 0 && !hasTags(f.Tags(item), opts.Tags) {
+			continue
+		}
+		if search != nil && !f.matches(item, search) {
+			continue
+		}
+		if !inRange(f.CreatedAt, item, opts.CreatedAfter, opts.CreatedBefore) ||
+			!inRange(f.UpdatedAt, item, opts.UpdatedAfter, opts.UpdatedBefore) {
+			continue
+		}
+		out = append(out, item)
+	}
+
+	if less := f.less(opts.Sort); less != nil {
+		sort.SliceStable(out, func(i, j int) bool {
+			if opts.Reverse {
+				return less(out[j], out[i])
+			}
+			return less(out[i], out[j])
+		})
+	} else if opts.Reverse {
+		for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+			out[i], out[j] = out[j], out[i]
+		}
+	}
+	return out, nil
+}
+
+// check rejects options that need fields the resource does not have.
+func (f Fields[T]) check(opts Options) error {
+	unsupported := func(option string) error {
+		return fmt.Errorf("filtering by %s is not supported for this resource", option)
+	}
+	switch {
+	case opts.Type != "" && f.Type == nil:
+		return unsupported("type")
+	case opts.Status != "" && f.Status == nil:
+		return unsupported("status")
+	case len(opts.Tags) > 0 && f.Tags == nil:
+		return unsupported("tag")
+	case (!opts.CreatedAfter.IsZero() || !opts.CreatedBefore.IsZero()) && f.CreatedAt == nil:
+		return unsupported("creation date")
+	case (!opts.UpdatedAfter.IsZero() || !opts.UpdatedBefore.IsZero()) && f.UpdatedAt == nil:
+		return unsupported("update date")
+	}
+	switch opts.Sort {
+	case "":
+		return nil
+	case SortKey, SortName, SortCreated, SortUpdated:
+		if f.less(opts.Sort) == nil {
+			return fmt.Errorf("sorting by %s is not supported for this resource", opts.Sort)
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid sort order %q (must be key, name, created or updated)", opts.Sort)
+	}
+}
+
+func (f Fields[T]) matches(item T, search func(string) bool) bool {
+	for _, field := range []func(T) string{f.Key, f.Name, f.Description} {
+		if field != nil && search(field(item)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f Fields[T]) less(by string) func(a, b T) bool {
+	byString := func(field func(T) string) func(a, b T) bool {
+		if field == nil {
+			return nil
+		}
+		return func(a, b T) bool { return strings.ToLower(field(a)) < strings.ToLower(field(b)) }
+	}
+	byTime := func(field func(T) time.Time) func(a, b T) bool {
+		if field == nil {
+			return nil
+		}
+		return func(a, b T) bool { return field(a).Before(field(b)) }
+	}
+	switch by {
+	case SortKey:
+		return byString(f.Key)
+	case SortName:
+		return byString(f.Name)
+	case SortCreated:
+		return byTime(f.CreatedAt)
+	case SortUpdated:
+		return byTime(f.UpdatedAt)
+	}
+	return nil
+}
+
+func hasTags(have, want []string) bool {
+	for _, w := range want {
+		found := false
+		for _, h := range have {
+			if strings.EqualFold(h, w) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func inRange[T any](field func(T) time.Time, item T, after, before time.Time) bool {
+	if after.IsZero() && before.IsZero() {
+		return true
+	}
+	t := field(item)
+	return (after.IsZero() || t.After(after)) && (before.IsZero() || t.Before(before))
+}
+
+// ParseTime parses a date filter: a date (2006-01-02), an RFC 3339
+// timestamp, or a number of days before now such as "30d".
+func ParseTime(s string, now time.Time) (time.Time, error) {
+	if n, ok := strings.CutSuffix(s, "d"); ok {
+		if days, err := strconv.Atoi(n); err == nil && days >= 0 {
+			return now.AddDate(0, 0, -days), nil
+		}
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q (expected YYYY-MM-DD, an RFC 3339 time or a number of days such as 30d)", s)
+}
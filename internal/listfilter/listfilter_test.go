@@ -0,0 +1,117 @@
+package listfilter
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type item struct {
+	key, name, desc, typ, status string
+	tags                         []string
+	created, updated             time.Time
+}
+
+var fields = Fields[item]{
+	Key:         func(i item) string { return i.key },
+	Name:        func(i item) string { return i.name },
+	Description: func(i item) string { return i.desc },
+	Type:        func(i item) string { return i.typ },
+	Status:      func(i item) string { return i.status },
+	Tags:        func(i item) []string { return i.tags },
+	CreatedAt:   func(i item) time.Time { return i.created },
+	UpdatedAt:   func(i item) time.Time { return i.updated },
+}
+
+func day(n int) time.Time {
+	return time.Date(2026, 1, n, 0, 0, 0, 0, time.UTC)
+}
+
+var items = []item{
+	{key: "dark-mode", name: "Dark Mode", typ: "release", status: "active", tags: []string{"ui", "web"}, created: day(3), updated: day(20)},
+	{key: "checkout-v2", name: "Checkout", desc: "New checkout flow", typ: "experiment", status: "active", tags: []string{"payments"}, created: day(1), updated: day(5)},
+	{key: "beta", name: "Beta Access", typ: "permission", status: "archived", tags: []string{"UI"}, created: day(10), updated: day(10)},
+}
+
+func keys(items []item) []string {
+	var k []string
+	for _, i := range items {
+		k = append(k, i.key)
+	}
+	return k
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		want []string
+	}{
+		{name: "no options", want: []string{"dark-mode", "checkout-v2", "beta"}},
+		{name: "type", opts: Options{Type: "Release"}, want: []string{"dark-mode"}},
+		{name: "status", opts: Options{Status: "active"}, want: []string{"dark-mode", "checkout-v2"}},
+		{name: "tags", opts: Options{Tags: []string{"ui"}}, want: []string{"dark-mode", "beta"}},
+		{name: "all tags", opts: Options{Tags: []string{"ui", "web"}}, want: []string{"dark-mode"}},
+		{name: "substring search in description", opts: Options{Search: "FLOW"}, want: []string{"checkout-v2"}},
+		{name: "regexp search", opts: Options{Search: "/^(beta|dark)/"}, want: []string{"dark-mode", "beta"}},
+		{name: "created after", opts: Options{CreatedAfter: day(2)}, want: []string{"dark-mode", "beta"}},
+		{name: "updated before", opts: Options{UpdatedBefore: day(15)}, want: []string{"checkout-v2", "beta"}},
+		{name: "sort by name", opts: Options{Sort: SortName}, want: []string{"beta", "checkout-v2", "dark-mode"}},
+		{name: "sort by created reversed", opts: Options{Sort: SortCreated, Reverse: true}, want: []string{"beta", "dark-mode", "checkout-v2"}},
+		{name: "reverse without sort", opts: Options{Reverse: true}, want: []string{"beta", "checkout-v2", "dark-mode"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply(items, fields, tt.opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(keys(got), tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, keys(got))
+			}
+		})
+	}
+	if keys(items)[0] != "dark-mode" {
+		t.Error("expected input to be left unchanged")
+	}
+}
+
+func TestApplyErrors(t *testing.T) {
+	limited := Fields[item]{Key: fields.Key, Name: fields.Name}
+	for _, opts := range []Options{
+		{Type: "release"},
+		{Tags: []string{"ui"}},
+		{UpdatedAfter: day(1)},
+		{Sort: SortCreated},
+	} {
+		if _, err := Apply(items, limited, opts); err == nil {
+			t.Errorf("expected error for unsupported options %+v", opts)
+		}
+	}
+	if _, err := Apply(items, fields, Options{Sort: "size"}); err == nil {
+		t.Error("expected error for invalid sort order")
+	}
+	if _, err := Apply(items, fields, Options{Search: "/([/"}); err == nil {
+		t.Error("expected error for invalid regular expression")
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+	tests := map[string]time.Time{
+		"2026-01-15":           time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC),
+		"2026-01-15T10:00:00Z": time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC),
+		"30d":                  time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+	for in, want := range tests {
+		got, err := ParseTime(in, now)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("ParseTime(%q) = %v, %v; expected %v", in, got, err, want)
+		}
+	}
+	for _, bad := range []string{"yesterday", "-3d", "2026-13-01"} {
+		if _, err := ParseTime(bad, now); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
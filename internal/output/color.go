@@ -0,0 +1,18 @@
+package output
+
+const (
+	ColorRed    = "31"
+	ColorGreen  = "32"
+	ColorYellow = "33"
+	ColorCyan   = "36"
+	ColorBold   = "1"
+	ColorDim    = "2"
+)
+
+// Colorize wraps s in the ANSI escape sequence for color when enabled is true.
+func Colorize(s, color string, enabled bool) string {
+	if !enabled || s == "" {
+		return s
+	}
+	return "\033[" + color + "m" + s + "\033[0m"
+}
@@ -1,7 +1,9 @@
 package output
 
 import (
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -17,6 +19,7 @@ const (
 	FormatTable Format = "table"
 	FormatJSON  Format = "json"
 	FormatYAML  Format = "yaml"
+	FormatCSV   Format = "csv"
 )
 
 func ParseFormat(s string) Format {
@@ -25,6 +28,8 @@ func ParseFormat(s string) Format {
 		return FormatJSON
 	case "yaml", "yml":
 		return FormatYAML
+	case "csv":
+		return FormatCSV
 	default:
 		return FormatTable
 	}
@@ -52,6 +57,8 @@ func (p *Printer) Print(data any) error {
 		return p.printJSON(data)
 	case FormatYAML:
 		return p.printYAML(data)
+	case FormatCSV:
+		return p.printCSV(data)
 	default:
 		return p.printTable(data)
 	}
@@ -69,6 +76,23 @@ func (p *Printer) printYAML(data any) error {
 	return encoder.Encode(data)
 }
 
+// printCSV writes table data as comma-separated values, with the headers
+// as the first record. Other data has no CSV form.
+func (p *Printer) printCSV(data any) error {
+	tableData, ok := data.(TableData)
+	if !ok {
+		return errors.New("csv output is not supported here; use table, json or yaml")
+	}
+	w := csv.NewWriter(p.writer)
+	if err := w.Write(tableData.Headers()); err != nil {
+		return err
+	}
+	if err := w.WriteAll(tableData.Rows()); err != nil {
+		return err
+	}
+	return w.Error()
+}
+
 func (p *Printer) printTable(data any) error {
 	if tableData, ok := data.(TableData); ok {
 		return p.renderTable(tableData)
@@ -18,6 +18,8 @@ func TestParseFormat(t *testing.T) {
 		{"YAML", FormatYAML},
 		{"yml", FormatYAML},
 		{"YML", FormatYAML},
+		{"csv", FormatCSV},
+		{"CSV", FormatCSV},
 		{"table", FormatTable},
 		{"TABLE", FormatTable},
 		{"", FormatTable},
@@ -148,6 +150,32 @@ func TestPrinter_PrintTable_NonTableData(t *testing.T) {
 	}
 }
 
+func TestPrinter_PrintCSV(t *testing.T) {
+	data := testTableData{
+		items: [][]string{
+			{"item1", "a, b"},
+			{"item2", `say "hi"`},
+		},
+	}
+
+	var buf bytes.Buffer
+	printer := NewPrinter(FormatCSV)
+	printer.SetWriter(&buf)
+
+	if err := printer.Print(data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "NAME,VALUE\nitem1,\"a, b\"\nitem2,\"say \"\"hi\"\"\"\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+
+	if err := printer.Print(map[string]string{"key": "value"}); err == nil {
+		t.Error("expected an error for data that is not a table")
+	}
+}
+
 func TestPrinter_PrintSuccess(t *testing.T) {
 	var buf bytes.Buffer
 	printer := NewPrinter(FormatTable)
@@ -184,3 +212,15 @@ func TestPrinter_PrintSlice(t *testing.T) {
 		t.Errorf("expected output to contain item2, got %s", output)
 	}
 }
+
+func TestColorize(t *testing.T) {
+	if got := Colorize("text", ColorRed, false); got != "text" {
+		t.Errorf("expected plain text when disabled, got %q", got)
+	}
+	if got := Colorize("text", ColorRed, true); got != "\033[31mtext\033[0m" {
+		t.Errorf("expected colored text, got %q", got)
+	}
+	if got := Colorize("", ColorRed, true); got != "" {
+		t.Errorf("expected empty string, got %q", got)
+	}
+}
@@ -0,0 +1,128 @@
+// Package prompt asks questions on a terminal, one line at a time. Answers
+// that fail their check are reported and asked again.
+package prompt
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ErrAborted is returned when the input ends before a question is
+// answered.
+var ErrAborted = errors.New("aborted")
+
+// Prompter asks questions on out and reads the answers from in.
+type Prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// New returns a Prompter that reads answers from in and writes questions
+// to out.
+func New(in io.Reader, out io.Writer) *Prompter {
+	return &Prompter{in: bufio.NewReader(in), out: out}
+}
+
+// Printf writes a message between questions.
+func (p *Prompter) Printf(format string, args ...any) {
+	fmt.Fprintf(p.out, format, args...)
+}
+
+func (p *Prompter) readLine() (string, error) {
+	line, err := p.in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		if err == io.EOF {
+			fmt.Fprintln(p.out)
+			return "", ErrAborted
+		}
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// Ask asks a question and returns the answer, or def when the answer is
+// empty. When check is not nil, answers it rejects are asked again.
+func (p *Prompter) Ask(label, def string, check func(string) error) (string, error) {
+	for {
+		if def != "" {
+			fmt.Fprintf(p.out, "%s [%s]: ", label, def)
+		} else {
+			fmt.Fprintf(p.out, "%s: ", label)
+		}
+		answer, err := p.readLine()
+		if err != nil {
+			return "", err
+		}
+		if answer == "" {
+			answer = def
+		}
+		if check != nil {
+			if err := check(answer); err != nil {
+				fmt.Fprintf(p.out, "  %v\n", err)
+				continue
+			}
+		}
+		return answer, nil
+	}
+}
+
+// Confirm asks a yes or no question and returns def when the answer is
+// empty.
+func (p *Prompter) Confirm(label string, def bool) (bool, error) {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	for {
+		fmt.Fprintf(p.out, "%s [%s]: ", label, hint)
+		answer, err := p.readLine()
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(answer) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		fmt.Fprintln(p.out, "  answer y or n")
+	}
+}
+
+// Choose lists options and returns the index of the one chosen by number
+// or by text, or def when the answer is empty.
+func (p *Prompter) Choose(label string, options []string, def int) (int, error) {
+	fmt.Fprintf(p.out, "%s:\n", label)
+	for i, o := range options {
+		fmt.Fprintf(p.out, "  %d) %s\n", i+1, o)
+	}
+	choice := -1
+	_, err := p.Ask("Choose", strconv.Itoa(def+1), func(answer string) error {
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(options) {
+			choice = n - 1
+			return nil
+		}
+		for i, o := range options {
+			if strings.EqualFold(answer, o) {
+				choice = i
+				return nil
+			}
+		}
+		return fmt.Errorf("choose a number from 1 to %d", len(options))
+	})
+	return choice, err
+}
+
+// Required rejects empty answers.
+func Required(answer string) error {
+	if answer == "" {
+		return errors.New("a value is required")
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package prompt
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAsk(t *testing.T) {
+	var out bytes.Buffer
+	p := New(strings.NewReader("\n  dark mode \n"), &out)
+
+	got, err := p.Ask("Name", "", Required)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "dark mode" {
+		t.Errorf("expected trimmed answer, got %q", got)
+	}
+	if !strings.Contains(out.String(), "a value is required") {
+		t.Errorf("expected the empty answer to be rejected, got %q", out.String())
+	}
+
+	if _, err := p.Ask("Key", "", nil); !errors.Is(err, ErrAborted) {
+		t.Errorf("expected ErrAborted at the end of the input, got %v", err)
+	}
+}
+
+func TestAskDefault(t *testing.T) {
+	var out bytes.Buffer
+	p := New(strings.NewReader("\n"), &out)
+	got, err := p.Ask("Key", "dark-mode", nil)
+	if err != nil || got != "dark-mode" {
+		t.Errorf("expected the default, got %q, %v", got, err)
+	}
+	if out.String() != "Key [dark-mode]: " {
+		t.Errorf("unexpected prompt %q", out.String())
+	}
+}
+
+func TestConfirm(t *testing.T) {
+	p := New(strings.NewReader("maybe\nY\n\nno"), &bytes.Buffer{})
+	for _, want := range []bool{true, false, false} {
+		got, err := p.Confirm("Continue?", false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != want {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestChoose(t *testing.T) {
+	var out bytes.Buffer
+	p := New(strings.NewReader("5\n2\nOPS\n\n"), &out)
+	options := []string{"release", "experiment", "permission", "ops"}
+	for _, want := range []int{1, 3, 0} {
+		got, err := p.Choose("Type", options, 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != want {
+			t.Errorf("expected %d, got %d", want, got)
+		}
+	}
+	if !strings.Contains(out.String(), "  4) ops\n") || !strings.Contains(out.String(), "choose a number from 1 to 4") {
+		t.Errorf("unexpected output %q", out.String())
+	}
+}
@@ -0,0 +1,151 @@
+// Package retire implements the steps of retiring a fully rolled out
+// feature: checking that it serves the kept variation everywhere, pinning
+// it, and tracking the grace period before the feature is deleted.
+package retire
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/135yshr/devcycle-cli/internal/stale"
+	"github.com/135yshr/devcycle-cli/internal/targeting"
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+// EnvironmentCheck is the result of checking one environment.
+type EnvironmentCheck struct {
+	Environment string `json:"environment"`
+	OK          bool   `json:"ok"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+// Check reports, for each environment, whether the feature serves variation
+// to every user. The result is sorted by environment key.
+func Check(configs map[string]*api.EnvironmentConfig, variation api.Variation) []EnvironmentCheck {
+	envs := make([]string, 0, len(configs))
+	for env := range configs {
+		envs = append(envs, env)
+	}
+	sort.Strings(envs)
+
+	checks := make([]EnvironmentCheck, len(envs))
+	for i, env := range envs {
+		cfg := configs[env]
+		c := EnvironmentCheck{Environment: env}
+		served, ok := "", false
+		if cfg != nil {
+			served, ok = stale.ServedVariation(cfg.Targets)
+		}
+		switch {
+		case cfg == nil || cfg.Status != "active":
+			c.Reason = "feature is not active"
+		case !ok:
+			c.Reason = "not every user is served a single variation"
+		case served != variation.ID && served != variation.Key:
+			c.Reason = fmt.Sprintf("serves variation %s", served)
+		default:
+			c.OK = true
+		}
+		checks[i] = c
+	}
+	return checks
+}
+
+// Pin returns configurations that serve variation to every user in every
+// environment of configs: each one is active with a single catch-all rule.
+func Pin(configs map[string]*api.EnvironmentConfig, variations []api.Variation, variation string) (map[string]*api.EnvironmentConfig, error) {
+	pinned := make(map[string]*api.EnvironmentConfig, len(configs))
+	for env, cfg := range configs {
+		var dist []api.Distribution
+		if cfg != nil {
+			for _, t := range cfg.Targets {
+				dist = append(dist, t.Distribution...)
+			}
+		}
+		ref, err := targeting.ResolveVariation(variations, dist, variation)
+		if err != nil {
+			return nil, err
+		}
+		out := &api.EnvironmentConfig{Status: "active"}
+		targeting.SetDefault(out, []api.Distribution{{Variation: ref, Percentage: 1}})
+		pinned[env] = out
+	}
+	return pinned, nil
+}
+
+// State records a feature that has been marked complete and is waiting for
+// its grace period to end before it is deleted. The end of the grace period
+// is fixed when the feature is marked.
+type State struct {
+	Project     string    `json:"project"`
+	Feature     string    `json:"feature"`
+	Variation   string    `json:"variation"`
+	Status      string    `json:"status"`
+	CompletedAt time.Time `json:"completedAt"`
+	DeleteAfter time.Time `json:"deleteAfter"`
+}
+
+// StatePath returns the state file path for a feature retirement stored
+// under dir, in a directory per project.
+func StatePath(dir, project, feature string) string {
+	return filepath.Join(dir, project, feature+".json")
+}
+
+// LoadState reads a retirement state file.
+// It returns nil without an error when the file does not exist.
+func LoadState(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read retirement state: %w", err)
+	}
+
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse retirement state %s: %w", path, err)
+	}
+	return &s, nil
+}
+
+// Verify reports an error when the feature has changed since it was marked:
+// its status is no longer the one recorded, or an active environment no
+// longer serves the kept variation to every user. Inactive environments are
+// ignored, as they serve nothing.
+func (s *State) Verify(feature *api.Feature, configs map[string]*api.EnvironmentConfig, variations []api.Variation) error {
+	if feature.Status != s.Status {
+		return fmt.Errorf("feature '%s' is %s, no longer %s", s.Feature, feature.Status, s.Status)
+	}
+	i := slices.IndexFunc(variations, func(v api.Variation) bool { return v.Key == s.Variation })
+	if i < 0 {
+		return fmt.Errorf("variation '%s' of feature '%s' no longer exists", s.Variation, s.Feature)
+	}
+	for _, c := range Check(configs, variations[i]) {
+		if cfg := configs[c.Environment]; !c.OK && cfg != nil && cfg.Status == "active" {
+			return fmt.Errorf("feature '%s' is active in %s and %s", s.Feature, c.Environment, c.Reason)
+		}
+	}
+	return nil
+}
+
+// Save writes the state to path, creating its directory if needed.
+func (s *State) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create retirement state directory: %w", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal retirement state: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write retirement state: %w", err)
+	}
+	return nil
+}
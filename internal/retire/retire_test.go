@@ -0,0 +1,126 @@
+package retire
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/135yshr/devcycle-cli/internal/targeting"
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+var variations = []api.Variation{{ID: "var-on", Key: "on"}, {ID: "var-off", Key: "off"}}
+
+func serve(dist ...api.Distribution) *api.EnvironmentConfig {
+	return &api.EnvironmentConfig{
+		Status: "active",
+		Targets: []api.Target{{
+			Name:         "everyone",
+			Audience:     api.Audience{Filters: api.Filters{Operator: "and", Filters: []api.Filter{{Type: "all"}}}},
+			Distribution: dist,
+		}},
+	}
+}
+
+func TestCheck(t *testing.T) {
+	configs := map[string]*api.EnvironmentConfig{
+		"production":  serve(api.Distribution{Variation: "var-on", Percentage: 1}),
+		"staging":     serve(api.Distribution{Variation: "var-off", Percentage: 1}),
+		"development": serve(api.Distribution{Variation: "var-on", Percentage: 0.5}, api.Distribution{Variation: "var-off", Percentage: 0.5}),
+		"sandbox":     {Status: "inactive"},
+	}
+	checks := Check(configs, variations[0])
+
+	want := []EnvironmentCheck{
+		{Environment: "development", Reason: "not every user is served a single variation"},
+		{Environment: "production", OK: true},
+		{Environment: "sandbox", Reason: "feature is not active"},
+		{Environment: "staging", Reason: "serves variation var-off"},
+	}
+	if !reflect.DeepEqual(checks, want) {
+		t.Errorf("expected %+v, got %+v", want, checks)
+	}
+}
+
+func TestPin(t *testing.T) {
+	configs := map[string]*api.EnvironmentConfig{
+		"production": serve(api.Distribution{Variation: "var-on", Percentage: 0.5}, api.Distribution{Variation: "var-off", Percentage: 0.5}),
+		"sandbox":    {Status: "inactive"},
+	}
+	pinned, err := Pin(configs, variations, "on")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for env, cfg := range pinned {
+		if cfg.Status != "active" || len(cfg.Targets) != 1 || !targeting.IsCatchAll(cfg.Targets[0]) {
+			t.Errorf("%s: expected a single active catch-all rule, got %+v", env, cfg)
+		}
+	}
+	if checks := Check(pinned, variations[0]); !checks[0].OK || !checks[1].OK {
+		t.Errorf("expected pinned configs to pass the check, got %+v", checks)
+	}
+	if configs["production"].Targets[0].Name != "everyone" {
+		t.Error("expected input configs to be left unchanged")
+	}
+
+	if _, err := Pin(configs, variations, "missing"); err == nil {
+		t.Error("expected error for unknown variation")
+	}
+}
+
+func TestState(t *testing.T) {
+	dir := t.TempDir()
+	path := StatePath(dir, "proj", "feat")
+	if path != filepath.Join(dir, "proj", "feat.json") {
+		t.Errorf("unexpected state path %s", path)
+	}
+	if StatePath(dir, "a_b", "c") == StatePath(dir, "a", "b_c") {
+		t.Error("expected different features to have different state files")
+	}
+
+	s, err := LoadState(path)
+	if err != nil || s != nil {
+		t.Fatalf("expected no state, got %v, %v", s, err)
+	}
+
+	completed := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	want := &State{Project: "proj", Feature: "feat", Variation: "on", Status: api.FeatureStatusComplete, CompletedAt: completed, DeleteAfter: completed.AddDate(0, 0, 14)}
+	if err := want.Save(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := LoadState(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestStateVerify(t *testing.T) {
+	state := &State{Project: "proj", Feature: "feat", Variation: "on", Status: api.FeatureStatusComplete}
+	pinned := map[string]*api.EnvironmentConfig{
+		"production": serve(api.Distribution{Variation: "var-on", Percentage: 1}),
+		"sandbox":    {Status: "inactive"},
+	}
+	complete := &api.Feature{Key: "feat", Status: api.FeatureStatusComplete}
+	if err := state.Verify(complete, pinned, variations); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if err := state.Verify(&api.Feature{Key: "feat", Status: api.FeatureStatusActive}, pinned, variations); err == nil || err.Error() != "feature 'feat' is active, no longer complete" {
+		t.Errorf("expected a status error, got %v", err)
+	}
+
+	changed := map[string]*api.EnvironmentConfig{
+		"production": serve(api.Distribution{Variation: "var-on", Percentage: 0.5}, api.Distribution{Variation: "var-off", Percentage: 0.5}),
+	}
+	if err := state.Verify(complete, changed, variations); err == nil || err.Error() != "feature 'feat' is active in production and not every user is served a single variation" {
+		t.Errorf("expected a targeting error, got %v", err)
+	}
+
+	if err := state.Verify(complete, pinned, variations[1:]); err == nil {
+		t.Error("expected an error for a deleted variation")
+	}
+}
@@ -0,0 +1,67 @@
+package rollout
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+// RegressionError is returned by CheckRegression when the rolled out
+// variation performs worse than the baseline by more than the allowed amount.
+type RegressionError struct {
+	Metric     string
+	Variation  string
+	Baseline   string
+	Regression float64
+	Allowed    float64
+}
+
+func (e *RegressionError) Error() string {
+	return fmt.Sprintf("guard metric '%s' regressed by %.2f%% for variation '%s' compared to '%s' (allowed %.2f%%)",
+		e.Metric, e.Regression*100, e.Variation, e.Baseline, e.Allowed*100)
+}
+
+// CheckRegression compares the metric value of variation against baseline.
+// optimizeFor is the metric's optimization direction ("increase" or
+// "decrease"). It returns a *RegressionError when the relative regression
+// exceeds maxRegression, and ok=false when the results do not contain data
+// for both variations.
+func CheckRegression(metric string, results *api.MetricResults, variation, baseline, optimizeFor string, maxRegression float64) (ok bool, err error) {
+	if results == nil {
+		return false, nil
+	}
+
+	var treatment, control *api.MetricResultData
+	for i := range results.Data {
+		switch results.Data[i].VariationKey {
+		case variation:
+			treatment = &results.Data[i]
+		case baseline:
+			control = &results.Data[i]
+		}
+	}
+	if treatment == nil || control == nil || treatment.Count == 0 || control.Count == 0 {
+		return false, nil
+	}
+	if control.Value == 0 {
+		return true, nil
+	}
+
+	change := (treatment.Value - control.Value) / math.Abs(control.Value)
+	regression := -change
+	if optimizeFor == "decrease" {
+		regression = change
+	}
+
+	if regression > maxRegression {
+		return true, &RegressionError{
+			Metric:     metric,
+			Variation:  variation,
+			Baseline:   baseline,
+			Regression: regression,
+			Allowed:    maxRegression,
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,93 @@
+// Package rollout implements progressive percentage rollouts of a feature
+// variation, including resumable progress state and metric guard checks.
+package rollout
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+// ParseSteps parses a comma-separated list of percentages such as
+// "1,5,25,50,100" into fractions between 0 and 1.
+// Steps must be strictly increasing and within (0, 100].
+func ParseSteps(s string) ([]float64, error) {
+	parts := strings.Split(s, ",")
+	steps := make([]float64, 0, len(parts))
+	for _, p := range parts {
+		p = strings.TrimSuffix(strings.TrimSpace(p), "%")
+		if p == "" {
+			continue
+		}
+		v, err := strconv.ParseFloat(p, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid step %q: must be a number", p)
+		}
+		if v <= 0 || v > 100 {
+			return nil, fmt.Errorf("invalid step %q: must be greater than 0 and at most 100", p)
+		}
+		if len(steps) > 0 && v/100 <= steps[len(steps)-1] {
+			return nil, fmt.Errorf("invalid step %q: steps must be increasing", p)
+		}
+		steps = append(steps, v/100)
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("at least one step is required")
+	}
+	return steps, nil
+}
+
+// Distribute returns a distribution serving variation to pct of users.
+// The remaining traffic goes to fallback when it is set; otherwise it is
+// shared among the other variations of current in proportion to their
+// existing percentages.
+func Distribute(current []api.Distribution, variation, fallback string, pct float64) ([]api.Distribution, error) {
+	if pct < 0 || pct > 1 {
+		return nil, fmt.Errorf("percentage must be between 0 and 1")
+	}
+	rest := round(1 - pct)
+
+	dist := []api.Distribution{{Variation: variation, Percentage: round(pct)}}
+	if rest == 0 {
+		return dist, nil
+	}
+
+	if fallback != "" {
+		if fallback == variation {
+			return nil, fmt.Errorf("fallback variation must differ from the rolled out variation")
+		}
+		return append(dist, api.Distribution{Variation: fallback, Percentage: rest}), nil
+	}
+
+	var others []api.Distribution
+	var total float64
+	for _, d := range current {
+		if d.Variation == variation || d.Percentage <= 0 {
+			continue
+		}
+		others = append(others, d)
+		total += d.Percentage
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("no other variation is currently served to receive the remaining traffic; specify a fallback variation")
+	}
+
+	assigned := 0.0
+	for i, d := range others {
+		share := round(rest * d.Percentage / total)
+		if i == len(others)-1 {
+			share = round(rest - assigned)
+		}
+		assigned += share
+		dist = append(dist, api.Distribution{Variation: d.Variation, Percentage: share})
+	}
+	return dist, nil
+}
+
+// round limits floating point noise in percentages to 6 decimal places.
+func round(v float64) float64 {
+	return math.Round(v*1e6) / 1e6
+}
@@ -0,0 +1,188 @@
+package rollout
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+func TestParseSteps(t *testing.T) {
+	t.Run("valid steps", func(t *testing.T) {
+		steps, err := ParseSteps("1, 5,25%,50,100")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []float64{0.01, 0.05, 0.25, 0.5, 1}
+		if !reflect.DeepEqual(steps, want) {
+			t.Errorf("expected %v, got %v", want, steps)
+		}
+	})
+
+	for _, input := range []string{"", "abc", "0,50", "50,25", "10,150"} {
+		t.Run("invalid "+input, func(t *testing.T) {
+			if _, err := ParseSteps(input); err == nil {
+				t.Errorf("expected error for %q", input)
+			}
+		})
+	}
+}
+
+func TestDistribute(t *testing.T) {
+	t.Run("with fallback", func(t *testing.T) {
+		dist, err := Distribute(nil, "on", "off", 0.25)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []api.Distribution{{Variation: "on", Percentage: 0.25}, {Variation: "off", Percentage: 0.75}}
+		if !reflect.DeepEqual(dist, want) {
+			t.Errorf("expected %v, got %v", want, dist)
+		}
+	})
+
+	t.Run("proportional to existing variations", func(t *testing.T) {
+		current := []api.Distribution{
+			{Variation: "on", Percentage: 0.1},
+			{Variation: "a", Percentage: 0.6},
+			{Variation: "b", Percentage: 0.3},
+		}
+		dist, err := Distribute(current, "on", "", 0.4)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []api.Distribution{
+			{Variation: "on", Percentage: 0.4},
+			{Variation: "a", Percentage: 0.4},
+			{Variation: "b", Percentage: 0.2},
+		}
+		if !reflect.DeepEqual(dist, want) {
+			t.Errorf("expected %v, got %v", want, dist)
+		}
+	})
+
+	t.Run("full rollout", func(t *testing.T) {
+		dist, err := Distribute(nil, "on", "", 1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(dist) != 1 || dist[0].Percentage != 1 {
+			t.Errorf("expected single 100%% entry, got %v", dist)
+		}
+	})
+
+	t.Run("no remaining variation", func(t *testing.T) {
+		if _, err := Distribute([]api.Distribution{{Variation: "on", Percentage: 1}}, "on", "", 0.5); err == nil {
+			t.Error("expected error when no variation can take the remaining traffic")
+		}
+	})
+
+	t.Run("fallback equal to variation", func(t *testing.T) {
+		if _, err := Distribute(nil, "on", "on", 0.5); err == nil {
+			t.Error("expected error when fallback equals variation")
+		}
+	})
+}
+
+func TestState(t *testing.T) {
+	dir := t.TempDir()
+	path := StatePath(dir, "proj", "feat", "production")
+	if path != filepath.Join(dir, "proj", "feat", "production.json") {
+		t.Errorf("unexpected state file name: %s", path)
+	}
+	if StatePath(dir, "a_b", "c", "d") == StatePath(dir, "a", "b_c", "d") {
+		t.Error("expected different rollouts to have different state files")
+	}
+
+	s, err := LoadState(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s != nil {
+		t.Fatal("expected nil state for missing file")
+	}
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	state := &State{
+		Project:     "proj",
+		Feature:     "feat",
+		Environment: "production",
+		Target:      "All Users",
+		Variation:   "on",
+		Steps:       []float64{0.1, 0.5, 1},
+		Completed:   1,
+		Status:      StatusInProgress,
+		UpdatedAt:   now,
+	}
+	if err := state.Save(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	loaded, err := LoadState(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !loaded.Matches(state) {
+		t.Errorf("expected loaded state to match, got %+v", loaded)
+	}
+	if loaded.Completed != 1 || !loaded.UpdatedAt.Equal(now) {
+		t.Errorf("unexpected progress: %+v", loaded)
+	}
+
+	other := *state
+	other.Steps = []float64{0.2, 1}
+	if state.Matches(&other) {
+		t.Error("expected states with different steps not to match")
+	}
+
+	if wait := state.NextWait(30*time.Minute, now.Add(10*time.Minute)); wait != 20*time.Minute {
+		t.Errorf("expected 20m wait, got %s", wait)
+	}
+	if wait := state.NextWait(30*time.Minute, now.Add(time.Hour)); wait != 0 {
+		t.Errorf("expected no wait, got %s", wait)
+	}
+}
+
+func TestCheckRegression(t *testing.T) {
+	results := &api.MetricResults{Data: []api.MetricResultData{
+		{VariationKey: "on", Count: 100, Value: 0.9},
+		{VariationKey: "off", Count: 100, Value: 1.0},
+	}}
+
+	t.Run("regression beyond threshold", func(t *testing.T) {
+		ok, err := CheckRegression("conversion", results, "on", "off", "increase", 0.05)
+		if !ok {
+			t.Fatal("expected data to be available")
+		}
+		var regErr *RegressionError
+		if !errors.As(err, &regErr) {
+			t.Fatalf("expected RegressionError, got %v", err)
+		}
+		if regErr.Regression < 0.099 || regErr.Regression > 0.101 {
+			t.Errorf("expected ~10%% regression, got %f", regErr.Regression)
+		}
+	})
+
+	t.Run("within threshold", func(t *testing.T) {
+		_, err := CheckRegression("conversion", results, "on", "off", "increase", 0.2)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("lower is better", func(t *testing.T) {
+		_, err := CheckRegression("latency", results, "on", "off", "decrease", 0.05)
+		if err != nil {
+			t.Errorf("expected improvement, got %v", err)
+		}
+	})
+
+	t.Run("missing data", func(t *testing.T) {
+		ok, err := CheckRegression("conversion", &api.MetricResults{}, "on", "off", "increase", 0.05)
+		if ok || err != nil {
+			t.Errorf("expected ok=false and no error, got %v %v", ok, err)
+		}
+	})
+}
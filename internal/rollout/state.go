@@ -0,0 +1,98 @@
+package rollout
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// Rollout status values recorded in State.
+const (
+	StatusInProgress = "in_progress"
+	StatusCompleted  = "completed"
+	StatusAborted    = "aborted"
+)
+
+// State records the progress of a rollout so it can resume after an
+// interruption.
+type State struct {
+	Project     string    `json:"project"`
+	Feature     string    `json:"feature"`
+	Environment string    `json:"environment"`
+	Target      string    `json:"target"`
+	Variation   string    `json:"variation"`
+	Fallback    string    `json:"fallback,omitempty"`
+	Steps       []float64 `json:"steps"`
+	Completed   int       `json:"completed"`
+	Status      string    `json:"status"`
+	Reason      string    `json:"reason,omitempty"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// StatePath returns the state file path for a rollout stored under dir.
+// Each key is its own path element, so keys that contain separators such as
+// "_" cannot make two rollouts share a file.
+func StatePath(dir, project, feature, environment string) string {
+	return filepath.Join(dir, project, feature, environment+".json")
+}
+
+// LoadState reads a rollout state file.
+// It returns nil without an error when the file does not exist.
+func LoadState(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rollout state: %w", err)
+	}
+
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse rollout state %s: %w", path, err)
+	}
+	return &s, nil
+}
+
+// Save writes the state to path, creating its directory if needed.
+func (s *State) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create rollout state directory: %w", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal rollout state: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write rollout state: %w", err)
+	}
+	return nil
+}
+
+// Matches reports whether s describes the same rollout plan as other.
+func (s *State) Matches(other *State) bool {
+	return s.Project == other.Project &&
+		s.Feature == other.Feature &&
+		s.Environment == other.Environment &&
+		s.Target == other.Target &&
+		s.Variation == other.Variation &&
+		s.Fallback == other.Fallback &&
+		slices.Equal(s.Steps, other.Steps)
+}
+
+// NextWait returns how long to wait before applying the next step so that
+// consecutive steps are at least interval apart.
+func (s *State) NextWait(interval time.Duration, now time.Time) time.Duration {
+	if s.Completed == 0 || s.UpdatedAt.IsZero() {
+		return 0
+	}
+	wait := s.UpdatedAt.Add(interval).Sub(now)
+	if wait < 0 {
+		return 0
+	}
+	return wait
+}
@@ -0,0 +1,241 @@
+// Package stale classifies features that are candidates for cleanup, based
+// on their age, their latest change and how they are served in each
+// environment.
+package stale
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/135yshr/devcycle-cli/internal/targeting"
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+// Classifications of a feature.
+const (
+	ReadyToRemove     = "ready to remove"
+	FullyRolledOut    = "fully rolled out"
+	PossiblyAbandoned = "possibly abandoned"
+	InUse             = "in use"
+)
+
+// classOrder orders classifications from most to least actionable.
+var classOrder = map[string]int{ReadyToRemove: 0, FullyRolledOut: 1, PossiblyAbandoned: 2, InUse: 3}
+
+// Thresholds are the ages after which features are considered stale.
+type Thresholds struct {
+	// RolledOut is how long a feature must have served one variation
+	// everywhere, without changes, before it is ready to remove.
+	RolledOut time.Duration
+	// Abandoned is how long a feature that is not fully rolled out may go
+	// without changes before it is possibly abandoned.
+	Abandoned time.Duration
+}
+
+// DefaultThresholds are the thresholds used when none are configured.
+var DefaultThresholds = Thresholds{RolledOut: 14 * 24 * time.Hour, Abandoned: 90 * 24 * time.Hour}
+
+// Input is what is known about a feature.
+type Input struct {
+	Feature api.Feature
+	// LastAudit is the time of the latest audit log entry, if any.
+	LastAudit time.Time
+	// Configs are the feature's configurations by environment key.
+	Configs map[string]*api.EnvironmentConfig
+	// Variations are used to report the served variation by key.
+	Variations []api.Variation
+	// Usages is the number of code references to the feature's variables.
+	// It is only used when UsagesScanned is set.
+	Usages        int
+	UsagesScanned bool
+}
+
+// Result is the classification of a feature.
+type Result struct {
+	Key          string    `json:"key"`
+	Name         string    `json:"name"`
+	Class        string    `json:"classification"`
+	CreatedAt    time.Time `json:"createdAt"`
+	LastActivity time.Time `json:"lastActivity"`
+	AgeDays      int       `json:"ageDays"`
+	IdleDays     int       `json:"idleDays"`
+	State        string    `json:"state"`
+	Variation    string    `json:"variation,omitempty"`
+	Usages       *int      `json:"usages,omitempty"`
+	Reasons      []string  `json:"reasons"`
+}
+
+// Serving states of a feature across environments.
+const (
+	StateInactive   = "inactive everywhere"
+	StateRolledOut  = "one variation everywhere"
+	StateTargeted   = "targeted"
+	StateUnassigned = "no configuration"
+)
+
+// Classify classifies a feature as of now.
+func Classify(in Input, th Thresholds, now time.Time) Result {
+	f := in.Feature
+	last := f.UpdatedAt
+	if in.LastAudit.After(last) {
+		last = in.LastAudit
+	}
+	if last.Before(f.CreatedAt) {
+		last = f.CreatedAt
+	}
+	idle := now.Sub(last)
+
+	r := Result{
+		Key:          f.Key,
+		Name:         f.Name,
+		CreatedAt:    f.CreatedAt,
+		LastActivity: last,
+		AgeDays:      days(now.Sub(f.CreatedAt)),
+		IdleDays:     days(idle),
+		Class:        InUse,
+	}
+	if in.UsagesScanned {
+		n := in.Usages
+		r.Usages = &n
+	}
+
+	state, variation := servingState(in.Configs)
+	r.State = state
+	if variation != "" {
+		r.Variation = variationKey(in.Variations, variation)
+	}
+	unreferenced := in.UsagesScanned && in.Usages == 0
+
+	// Missing code references only make a feature ready to remove once it
+	// has also been idle for the threshold of its state, so that features
+	// whose code has not landed yet are not reported.
+	switch state {
+	case StateRolledOut:
+		r.Reasons = append(r.Reasons, fmt.Sprintf("serves %s to everyone in every environment", r.Variation))
+		if idle >= th.RolledOut {
+			r.Class = ReadyToRemove
+			r.Reasons = append(r.Reasons, fmt.Sprintf("unchanged for %d days", r.IdleDays))
+		} else {
+			r.Class = FullyRolledOut
+		}
+	case StateInactive, StateUnassigned:
+		r.Reasons = append(r.Reasons, state)
+		if idle >= th.Abandoned {
+			r.Class = PossiblyAbandoned
+			if unreferenced {
+				r.Class = ReadyToRemove
+			}
+			r.Reasons = append(r.Reasons, fmt.Sprintf("unchanged for %d days", r.IdleDays))
+		}
+	default:
+		if idle >= th.Abandoned {
+			r.Class = PossiblyAbandoned
+			r.Reasons = append(r.Reasons, fmt.Sprintf("partially rolled out and unchanged for %d days", r.IdleDays))
+		}
+	}
+	if unreferenced {
+		r.Reasons = append(r.Reasons, "no code references")
+	}
+	return r
+}
+
+// servingState returns how a feature is served across environments. For
+// StateRolledOut the variation ID is also returned.
+func servingState(configs map[string]*api.EnvironmentConfig) (state, variation string) {
+	if len(configs) == 0 {
+		return StateUnassigned, ""
+	}
+	active := 0
+	for _, cfg := range configs {
+		if cfg == nil || cfg.Status != "active" {
+			continue
+		}
+		active++
+		v, ok := ServedVariation(cfg.Targets)
+		if !ok || (variation != "" && v != variation) {
+			return StateTargeted, ""
+		}
+		variation = v
+	}
+	if active == 0 {
+		return StateInactive, ""
+	}
+	if active < len(configs) {
+		return StateTargeted, ""
+	}
+	return StateRolledOut, variation
+}
+
+// ServedVariation returns the variation that targets serve to every user:
+// every target serves that variation at 100% and one of them targets all
+// users. It reports false when users may get different variations or none.
+func ServedVariation(targets []api.Target) (string, bool) {
+	variation := ""
+	all := false
+	for _, t := range targets {
+		if len(t.Distribution) != 1 || t.Distribution[0].Percentage < 1 {
+			return "", false
+		}
+		v := t.Distribution[0].Variation
+		if variation != "" && v != variation {
+			return "", false
+		}
+		variation = v
+		all = all || targeting.IsCatchAll(t)
+	}
+	return variation, all && variation != ""
+}
+
+func variationKey(variations []api.Variation, id string) string {
+	for _, v := range variations {
+		if v.ID == id || v.Key == id {
+			return v.Key
+		}
+	}
+	return id
+}
+
+func days(d time.Duration) int {
+	if d < 0 {
+		return 0
+	}
+	return int(d / (24 * time.Hour))
+}
+
+// sortKeys are the orders accepted by Sort.
+var sortKeys = []string{"class", "key", "age", "idle"}
+
+// Sort sorts results by "class" (most actionable first), "key", "age" or
+// "idle" (oldest first). Ties are broken by key.
+func Sort(results []Result, by string) error {
+	var less func(a, b Result) bool
+	switch by {
+	case "class", "":
+		less = func(a, b Result) bool {
+			if classOrder[a.Class] != classOrder[b.Class] {
+				return classOrder[a.Class] < classOrder[b.Class]
+			}
+			return a.IdleDays > b.IdleDays
+		}
+	case "key":
+		less = func(a, b Result) bool { return false }
+	case "age":
+		less = func(a, b Result) bool { return a.AgeDays > b.AgeDays }
+	case "idle":
+		less = func(a, b Result) bool { return a.IdleDays > b.IdleDays }
+	default:
+		return fmt.Errorf("invalid sort order %q (must be one of %s)", by, strings.Join(sortKeys, ", "))
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if less(results[i], results[j]) {
+			return true
+		}
+		if less(results[j], results[i]) {
+			return false
+		}
+		return results[i].Key < results[j].Key
+	})
+	return nil
+}
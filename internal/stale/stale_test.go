@@ -0,0 +1,212 @@
+package stale
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+var now = time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+
+func daysAgo(n int) time.Time {
+	return now.Add(-time.Duration(n) * 24 * time.Hour)
+}
+
+func everyone(variation string) *api.EnvironmentConfig {
+	return &api.EnvironmentConfig{
+		Status: "active",
+		Targets: []api.Target{{
+			Audience:     api.Audience{Filters: api.Filters{Operator: "and", Filters: []api.Filter{{Type: "all"}}}},
+			Distribution: []api.Distribution{{Variation: variation, Percentage: 1}},
+		}},
+	}
+}
+
+func split() *api.EnvironmentConfig {
+	cfg := everyone("var-on")
+	cfg.Targets[0].Distribution = []api.Distribution{{Variation: "var-on", Percentage: 0.5}, {Variation: "var-off", Percentage: 0.5}}
+	return cfg
+}
+
+func inactive() *api.EnvironmentConfig {
+	return &api.EnvironmentConfig{Status: "inactive"}
+}
+
+func TestClassify(t *testing.T) {
+	variations := []api.Variation{{ID: "var-on", Key: "on"}, {ID: "var-off", Key: "off"}}
+	tests := []struct {
+		name      string
+		updated   int
+		audit     int
+		configs   map[string]*api.EnvironmentConfig
+		usages    int
+		scanned   bool
+		wantClass string
+		wantState string
+	}{
+		{
+			name:      "rolled out long ago",
+			updated:   40,
+			configs:   map[string]*api.EnvironmentConfig{"development": everyone("var-on"), "production": everyone("var-on")},
+			wantClass: ReadyToRemove,
+			wantState: StateRolledOut,
+		},
+		{
+			name:      "rolled out recently according to audit log",
+			updated:   40,
+			audit:     3,
+			configs:   map[string]*api.EnvironmentConfig{"production": everyone("var-on")},
+			wantClass: FullyRolledOut,
+			wantState: StateRolledOut,
+		},
+		{
+			name:      "rolled out recently without code references",
+			updated:   3,
+			configs:   map[string]*api.EnvironmentConfig{"production": everyone("var-on")},
+			scanned:   true,
+			wantClass: FullyRolledOut,
+			wantState: StateRolledOut,
+		},
+		{
+			name:      "different variations per environment",
+			updated:   40,
+			configs:   map[string]*api.EnvironmentConfig{"development": everyone("var-off"), "production": everyone("var-on")},
+			wantClass: InUse,
+			wantState: StateTargeted,
+		},
+		{
+			name:      "inactive in one environment",
+			updated:   40,
+			configs:   map[string]*api.EnvironmentConfig{"development": inactive(), "production": everyone("var-on")},
+			wantClass: InUse,
+			wantState: StateTargeted,
+		},
+		{
+			name:      "inactive everywhere for a long time",
+			updated:   120,
+			configs:   map[string]*api.EnvironmentConfig{"development": inactive(), "production": inactive()},
+			usages:    2,
+			scanned:   true,
+			wantClass: PossiblyAbandoned,
+			wantState: StateInactive,
+		},
+		{
+			name:      "inactive everywhere for a long time without code references",
+			updated:   120,
+			configs:   map[string]*api.EnvironmentConfig{"production": inactive()},
+			scanned:   true,
+			wantClass: ReadyToRemove,
+			wantState: StateInactive,
+		},
+		{
+			name:      "inactive everywhere recently without code references",
+			updated:   10,
+			configs:   map[string]*api.EnvironmentConfig{"production": inactive()},
+			scanned:   true,
+			wantClass: InUse,
+			wantState: StateInactive,
+		},
+		{
+			name:      "inactive everywhere recently",
+			updated:   10,
+			configs:   map[string]*api.EnvironmentConfig{"production": inactive()},
+			wantClass: InUse,
+			wantState: StateInactive,
+		},
+		{
+			name:      "stuck partial rollout",
+			updated:   200,
+			configs:   map[string]*api.EnvironmentConfig{"production": split()},
+			wantClass: PossiblyAbandoned,
+			wantState: StateTargeted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := Input{
+				Feature:       api.Feature{Key: "f", CreatedAt: daysAgo(365), UpdatedAt: daysAgo(tt.updated)},
+				Configs:       tt.configs,
+				Variations:    variations,
+				Usages:        tt.usages,
+				UsagesScanned: tt.scanned,
+			}
+			if tt.audit > 0 {
+				in.LastAudit = daysAgo(tt.audit)
+			}
+			r := Classify(in, DefaultThresholds, now)
+			if r.Class != tt.wantClass || r.State != tt.wantState {
+				t.Errorf("expected %q/%q, got %q/%q (reasons %v)", tt.wantClass, tt.wantState, r.Class, r.State, r.Reasons)
+			}
+			if r.AgeDays != 365 {
+				t.Errorf("expected age 365, got %d", r.AgeDays)
+			}
+			if tt.wantState == StateRolledOut && r.Variation != "on" {
+				t.Errorf("expected variation on, got %q", r.Variation)
+			}
+			if (r.Usages != nil) != tt.scanned {
+				t.Errorf("expected usages to be reported only when scanned")
+			}
+		})
+	}
+}
+
+func TestClassifyNewFeatureWithoutCodeReferences(t *testing.T) {
+	created := now.Add(-time.Hour)
+	for name, configs := range map[string]map[string]*api.EnvironmentConfig{
+		"inactive":   {"production": inactive()},
+		"rolled out": {"production": everyone("var-on")},
+		"unassigned": nil,
+	} {
+		t.Run(name, func(t *testing.T) {
+			in := Input{
+				Feature:       api.Feature{Key: "f", CreatedAt: created, UpdatedAt: created},
+				Configs:       configs,
+				UsagesScanned: true,
+			}
+			r := Classify(in, DefaultThresholds, now)
+			if r.Class == ReadyToRemove {
+				t.Errorf("expected a new feature not to be ready to remove, got reasons %v", r.Reasons)
+			}
+			if !slices.Contains(r.Reasons, "no code references") {
+				t.Errorf("expected the missing code references as a reason, got %v", r.Reasons)
+			}
+		})
+	}
+}
+
+func TestSort(t *testing.T) {
+	results := []Result{
+		{Key: "c", Class: InUse, AgeDays: 5, IdleDays: 1},
+		{Key: "a", Class: PossiblyAbandoned, AgeDays: 50, IdleDays: 100},
+		{Key: "b", Class: ReadyToRemove, AgeDays: 20, IdleDays: 20},
+		{Key: "d", Class: ReadyToRemove, AgeDays: 90, IdleDays: 30},
+	}
+	keys := func() []string {
+		var k []string
+		for _, r := range results {
+			k = append(k, r.Key)
+		}
+		return k
+	}
+
+	for by, want := range map[string][]string{
+		"class": {"d", "b", "a", "c"},
+		"key":   {"a", "b", "c", "d"},
+		"age":   {"d", "a", "b", "c"},
+		"idle":  {"a", "d", "b", "c"},
+	} {
+		if err := Sort(results, by); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := keys(); !reflect.DeepEqual(got, want) {
+			t.Errorf("sort by %s: expected %v, got %v", by, want, got)
+		}
+	}
+	if err := Sort(results, "name"); err == nil {
+		t.Error("expected error for unknown sort order")
+	}
+}
@@ -0,0 +1,190 @@
+// Package status summarizes where features are on: for each feature, its
+// status in each environment, how many targets it has there and whether
+// users are split between variations.
+package status
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/135yshr/devcycle-cli/internal/workpool"
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+// Filters accepted by Matrix.Only.
+const (
+	// OnlyActive keeps features that are on in at least one environment.
+	OnlyActive = "active"
+	// OnlyInactive keeps features that are off in every environment.
+	OnlyInactive = "inactive"
+	// OnlyPartial keeps features partially rolled out somewhere.
+	OnlyPartial = "partial"
+	// OnlyMixed keeps features that are on in some environments and off
+	// in others.
+	OnlyMixed = "mixed"
+)
+
+// Cell is the state of a feature in one environment.
+type Cell struct {
+	// Status is active or inactive, or empty when the feature has no
+	// configuration in the environment.
+	Status  string `json:"status,omitempty"`
+	Targets int    `json:"targets"`
+	// Partial is set when the feature is active and at least one target
+	// splits its users between variations.
+	Partial bool `json:"partial"`
+}
+
+// Active reports whether the feature is on in the environment.
+func (c Cell) Active() bool {
+	return c.Status == "active"
+}
+
+// Row is a feature and its state in each environment, by environment key.
+type Row struct {
+	Key          string          `json:"key"`
+	Name         string          `json:"name"`
+	Type         string          `json:"type"`
+	Environments map[string]Cell `json:"environments"`
+}
+
+// Matrix is the state of features in the environments of a project.
+type Matrix struct {
+	Project      string   `json:"project"`
+	Environments []string `json:"environments"`
+	Features     []Row    `json:"features"`
+}
+
+// ConfigSource returns the configurations of a feature by environment key.
+type ConfigSource interface {
+	FeatureConfigurations(ctx context.Context, projectKey, featureKey string) (map[string]*api.EnvironmentConfig, error)
+}
+
+// Fetch gets the configurations of features, by feature key, with at most
+// workers requests running at a time. The first failure cancels the others.
+func Fetch(ctx context.Context, src ConfigSource, projectKey string, features []api.Feature, workers int) (map[string]map[string]*api.EnvironmentConfig, error) {
+	keys := make([]string, len(features))
+	for i, f := range features {
+		keys[i] = f.Key
+	}
+	return workpool.Map(ctx, keys, workers, func(ctx context.Context, key string) (map[string]*api.EnvironmentConfig, error) {
+		cfg, err := src.FeatureConfigurations(ctx, projectKey, key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get configuration for feature '%s': %w", key, err)
+		}
+		return cfg, nil
+	})
+}
+
+// Build returns the matrix of features by environments, in the order of
+// both, from the configurations returned by Fetch.
+func Build(projectKey string, features []api.Feature, environments []api.Environment, configs map[string]map[string]*api.EnvironmentConfig) *Matrix {
+	m := &Matrix{Project: projectKey, Environments: make([]string, len(environments)), Features: make([]Row, len(features))}
+	for i, env := range environments {
+		m.Environments[i] = env.Key
+	}
+	for i, f := range features {
+		row := Row{Key: f.Key, Name: f.Name, Type: f.Type, Environments: make(map[string]Cell, len(environments))}
+		for _, env := range m.Environments {
+			cfg := configs[f.Key][env]
+			if cfg == nil {
+				row.Environments[env] = Cell{}
+				continue
+			}
+			row.Environments[env] = Cell{
+				Status:  cfg.Status,
+				Targets: len(cfg.Targets),
+				Partial: cfg.Status == "active" && Partial(cfg.Targets),
+			}
+		}
+		m.Features[i] = row
+	}
+	return m
+}
+
+// Partial reports whether any of targets splits its users between
+// variations rather than serving one variation to all of them.
+func Partial(targets []api.Target) bool {
+	for _, t := range targets {
+		served := 0
+		for _, d := range t.Distribution {
+			if d.Percentage > 0 {
+				served++
+			}
+		}
+		if served > 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// Select keeps the columns of environments, in that order. Unknown or
+// repeated environments are an error.
+func (m *Matrix) Select(environments []string) error {
+	for i, env := range environments {
+		if !slices.Contains(m.Environments, env) {
+			return fmt.Errorf("environment '%s' not found (available: %v)", env, m.Environments)
+		}
+		if slices.Contains(environments[:i], env) {
+			return fmt.Errorf("environment '%s' is selected more than once", env)
+		}
+	}
+	m.Environments = environments
+	for _, row := range m.Features {
+		for env := range row.Environments {
+			if !slices.Contains(environments, env) {
+				delete(row.Environments, env)
+			}
+		}
+	}
+	return nil
+}
+
+// CheckFilter reports an error unless filter is one of the Only constants.
+func CheckFilter(filter string) error {
+	switch filter {
+	case OnlyActive, OnlyInactive, OnlyPartial, OnlyMixed:
+		return nil
+	}
+	return fmt.Errorf("invalid filter %q (must be %s, %s, %s or %s)", filter, OnlyActive, OnlyInactive, OnlyPartial, OnlyMixed)
+}
+
+// Only keeps the features that match filter, one of the Only constants,
+// in the environments of the matrix.
+func (m *Matrix) Only(filter string) error {
+	if err := CheckFilter(filter); err != nil {
+		return err
+	}
+
+	rows := m.Features[:0]
+	for _, row := range m.Features {
+		active, partial := 0, 0
+		for _, env := range m.Environments {
+			cell := row.Environments[env]
+			if cell.Active() {
+				active++
+			}
+			if cell.Partial {
+				partial++
+			}
+		}
+		var keep bool
+		switch filter {
+		case OnlyActive:
+			keep = active > 0
+		case OnlyInactive:
+			keep = active == 0
+		case OnlyPartial:
+			keep = partial > 0
+		case OnlyMixed:
+			keep = active > 0 && active < len(m.Environments)
+		}
+		if keep {
+			rows = append(rows, row)
+		}
+	}
+	m.Features = rows
+	return nil
+}
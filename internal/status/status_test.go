@@ -0,0 +1,204 @@
+package status
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+type fakeSource struct {
+	configs map[string]map[string]*api.EnvironmentConfig
+	fail    string
+
+	mu      sync.Mutex
+	running int32
+	peak    int32
+	calls   []string
+}
+
+func (s *fakeSource) FeatureConfigurations(ctx context.Context, projectKey, featureKey string) (map[string]*api.EnvironmentConfig, error) {
+	n := atomic.AddInt32(&s.running, 1)
+	defer atomic.AddInt32(&s.running, -1)
+	s.mu.Lock()
+	s.peak = max(s.peak, n)
+	s.calls = append(s.calls, featureKey)
+	s.mu.Unlock()
+
+	time.Sleep(5 * time.Millisecond)
+	if featureKey == s.fail {
+		return nil, errors.New("boom")
+	}
+	return s.configs[featureKey], nil
+}
+
+func features(keys ...string) []api.Feature {
+	out := make([]api.Feature, len(keys))
+	for i, k := range keys {
+		out[i] = api.Feature{Key: k, Name: strings.ToUpper(k), Type: "release"}
+	}
+	return out
+}
+
+func serve(dist ...api.Distribution) api.Target {
+	return api.Target{Distribution: dist}
+}
+
+func TestFetch(t *testing.T) {
+	src := &fakeSource{configs: map[string]map[string]*api.EnvironmentConfig{
+		"a": {"production": {Status: "active"}},
+	}}
+	list := features("a", "b", "c", "d", "e", "f")
+
+	configs, err := Fetch(context.Background(), src, "web", list, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(configs) != 6 || configs["a"]["production"].Status != "active" {
+		t.Errorf("unexpected configurations %v", configs)
+	}
+	if src.peak > 3 || src.peak < 2 {
+		t.Errorf("expected at most 3 concurrent requests, got %d", src.peak)
+	}
+
+	src = &fakeSource{fail: "b"}
+	_, err = Fetch(context.Background(), src, "web", features("a", "b", "c", "d", "e", "f", "g", "h"), 1)
+	if err == nil || err.Error() != "failed to get configuration for feature 'b': boom" {
+		t.Errorf("expected the failure of b, got %v", err)
+	}
+	if len(src.calls) > 3 {
+		t.Errorf("expected the failure to stop the other requests, got %v", src.calls)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	envs := []api.Environment{{Key: "development"}, {Key: "production"}}
+	configs := map[string]map[string]*api.EnvironmentConfig{
+		"a": {
+			"development": {Status: "active", Targets: []api.Target{serve(api.Distribution{Variation: "on", Percentage: 1})}},
+			"production": {Status: "active", Targets: []api.Target{
+				serve(api.Distribution{Variation: "on", Percentage: 1}),
+				serve(api.Distribution{Variation: "on", Percentage: 0.1}, api.Distribution{Variation: "off", Percentage: 0.9}),
+			}},
+		},
+		"b": {
+			"development": {Status: "inactive", Targets: []api.Target{serve(api.Distribution{Variation: "on", Percentage: 0.5}, api.Distribution{Variation: "off", Percentage: 0.5})}},
+		},
+	}
+
+	m := Build("web", features("a", "b"), envs, configs)
+	want := []Row{
+		{Key: "a", Name: "A", Type: "release", Environments: map[string]Cell{
+			"development": {Status: "active", Targets: 1},
+			"production":  {Status: "active", Targets: 2, Partial: true},
+		}},
+		{Key: "b", Name: "B", Type: "release", Environments: map[string]Cell{
+			"development": {Status: "inactive", Targets: 1},
+			"production":  {},
+		}},
+	}
+	if !reflect.DeepEqual(m.Features, want) {
+		t.Errorf("expected %+v, got %+v", want, m.Features)
+	}
+	if !reflect.DeepEqual(m.Environments, []string{"development", "production"}) {
+		t.Errorf("unexpected environments %v", m.Environments)
+	}
+}
+
+func TestPartial(t *testing.T) {
+	tests := []struct {
+		name    string
+		targets []api.Target
+		want    bool
+	}{
+		{"none", nil, false},
+		{"single variation", []api.Target{serve(api.Distribution{Variation: "on", Percentage: 1})}, false},
+		{"unused variation", []api.Target{serve(api.Distribution{Variation: "on", Percentage: 1}, api.Distribution{Variation: "off", Percentage: 0})}, false},
+		{"split", []api.Target{serve(api.Distribution{Variation: "on", Percentage: 0.2}, api.Distribution{Variation: "off", Percentage: 0.8})}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Partial(tt.targets); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func testMatrix() *Matrix {
+	on := Cell{Status: "active"}
+	off := Cell{Status: "inactive"}
+	split := Cell{Status: "active", Partial: true}
+	return &Matrix{
+		Environments: []string{"development", "staging", "production"},
+		Features: []Row{
+			{Key: "everywhere", Environments: map[string]Cell{"development": on, "staging": on, "production": on}},
+			{Key: "nowhere", Environments: map[string]Cell{"development": off, "staging": {}, "production": off}},
+			{Key: "dev-only", Environments: map[string]Cell{"development": on, "staging": off, "production": off}},
+			{Key: "rolling-out", Environments: map[string]Cell{"development": on, "staging": on, "production": split}},
+		},
+	}
+}
+
+func keys(m *Matrix) string {
+	var out []string
+	for _, row := range m.Features {
+		out = append(out, row.Key)
+	}
+	return strings.Join(out, ",")
+}
+
+func TestOnly(t *testing.T) {
+	tests := map[string]string{
+		OnlyActive:   "everywhere,dev-only,rolling-out",
+		OnlyInactive: "nowhere",
+		OnlyPartial:  "rolling-out",
+		OnlyMixed:    "dev-only",
+	}
+	for filter, want := range tests {
+		t.Run(filter, func(t *testing.T) {
+			m := testMatrix()
+			if err := m.Only(filter); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := keys(m); got != want {
+				t.Errorf("expected %s, got %s", want, got)
+			}
+		})
+	}
+
+	if err := testMatrix().Only("on"); err == nil {
+		t.Error("expected an error for an unknown filter")
+	}
+}
+
+func TestSelect(t *testing.T) {
+	m := testMatrix()
+	if err := m.Select([]string{"production", "development"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := m.Features[0].Environments["staging"]; ok {
+		t.Error("expected staging to be removed")
+	}
+
+	// Filters only consider the selected environments
+	if err := m.Only(OnlyMixed); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := keys(m); got != "dev-only" {
+		t.Errorf("expected dev-only, got %s", got)
+	}
+
+	if err := testMatrix().Select([]string{"qa"}); err == nil || !strings.Contains(err.Error(), "'qa' not found") {
+		t.Errorf("expected an unknown environment error, got %v", err)
+	}
+	if err := testMatrix().Select([]string{"staging", "production", "staging"}); err == nil || !strings.Contains(err.Error(), "'staging' is selected more than once") {
+		t.Errorf("expected a repeated environment error, got %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+// Package tags edits and summarizes feature tags. Tags are compared without
+// regard to case, and the spelling of the first occurrence is kept.
+package tags
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+// Normalize trims the tags and drops empty and duplicate ones.
+func Normalize(tags []string) []string {
+	out := make([]string, 0, len(tags))
+	for _, t := range tags {
+		t = strings.TrimSpace(t)
+		if t != "" && indexOf(out, t) < 0 {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// Add returns current with the tags it does not have appended.
+func Add(current, tags []string) []string {
+	return Normalize(append(append([]string(nil), current...), tags...))
+}
+
+// Remove returns current without tags.
+func Remove(current, tags []string) []string {
+	out := make([]string, 0, len(current))
+	for _, t := range Normalize(current) {
+		if indexOf(tags, t) < 0 {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// Equal reports whether a and b hold the same tags, ignoring order.
+func Equal(a, b []string) bool {
+	a, b = Normalize(a), Normalize(b)
+	if len(a) != len(b) {
+		return false
+	}
+	for _, t := range a {
+		if indexOf(b, t) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Usage is the number of features that have a tag.
+type Usage struct {
+	Tag      string   `json:"tag"`
+	Count    int      `json:"count"`
+	Features []string `json:"features"`
+}
+
+// Count returns the usage of every tag of features, most used first and
+// then by tag.
+func Count(features []api.Feature) []Usage {
+	byTag := map[string]*Usage{}
+	var order []*Usage
+	for _, f := range features {
+		for _, t := range Normalize(f.Tags) {
+			key := strings.ToLower(t)
+			u, ok := byTag[key]
+			if !ok {
+				u = &Usage{Tag: t}
+				byTag[key] = u
+				order = append(order, u)
+			}
+			u.Count++
+			u.Features = append(u.Features, f.Key)
+		}
+	}
+
+	usages := make([]Usage, len(order))
+	for i, u := range order {
+		sort.Strings(u.Features)
+		usages[i] = *u
+	}
+	sort.SliceStable(usages, func(i, j int) bool {
+		if usages[i].Count != usages[j].Count {
+			return usages[i].Count > usages[j].Count
+		}
+		return strings.ToLower(usages[i].Tag) < strings.ToLower(usages[j].Tag)
+	})
+	return usages
+}
+
+func indexOf(tags []string, tag string) int {
+	for i, t := range tags {
+		if strings.EqualFold(strings.TrimSpace(t), tag) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,49 @@
+package tags
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+func TestEdit(t *testing.T) {
+	current := []string{"ui", "Payments"}
+
+	if got, want := Add(current, []string{"payments", " beta ", ""}), []string{"ui", "Payments", "beta"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Add: expected %v, got %v", want, got)
+	}
+	if got, want := Remove(current, []string{"PAYMENTS", "missing"}), []string{"ui"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Remove: expected %v, got %v", want, got)
+	}
+	if got := Remove(current, current); len(got) != 0 {
+		t.Errorf("Remove: expected no tags, got %v", got)
+	}
+	if current[1] != "Payments" || len(current) != 2 {
+		t.Error("expected input to be left unchanged")
+	}
+
+	if !Equal([]string{"a", "B"}, []string{"b", "a"}) {
+		t.Error("expected tags to be equal")
+	}
+	if Equal([]string{"a"}, []string{"a", "b"}) {
+		t.Error("expected tags to differ")
+	}
+}
+
+func TestCount(t *testing.T) {
+	features := []api.Feature{
+		{Key: "dark-mode", Tags: []string{"ui", "web"}},
+		{Key: "checkout", Tags: []string{"payments", "Web"}},
+		{Key: "beta", Tags: []string{"UI", "web"}},
+		{Key: "untagged"},
+	}
+	want := []Usage{
+		{Tag: "web", Count: 3, Features: []string{"beta", "checkout", "dark-mode"}},
+		{Tag: "ui", Count: 2, Features: []string{"beta", "dark-mode"}},
+		{Tag: "payments", Count: 1, Features: []string{"checkout"}},
+	}
+	if got := Count(features); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
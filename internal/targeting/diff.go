@@ -0,0 +1,433 @@
+// Package targeting provides helpers for comparing and manipulating
+// feature targeting configurations.
+package targeting
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/135yshr/devcycle-cli/internal/filterexpr"
+	"github.com/135yshr/devcycle-cli/internal/jsondiff"
+	"github.com/135yshr/devcycle-cli/internal/output"
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+// ChangeKind describes how a target differs between two configurations.
+type ChangeKind string
+
+const (
+	Unchanged ChangeKind = "unchanged"
+	Added     ChangeKind = "added"
+	Removed   ChangeKind = "removed"
+	Modified  ChangeKind = "modified"
+)
+
+// DistributionChange describes a variation whose percentage differs.
+type DistributionChange struct {
+	Variation string  `json:"variation"`
+	From      float64 `json:"from"`
+	To        float64 `json:"to"`
+}
+
+// TargetChange describes the difference for a single aligned target.
+// FromIndex and ToIndex are -1 when the target is absent on that side.
+type TargetChange struct {
+	Kind            ChangeKind           `json:"kind"`
+	Name            string               `json:"name"`
+	FromIndex       int                  `json:"fromIndex"`
+	ToIndex         int                  `json:"toIndex"`
+	AudienceChanged bool                 `json:"audienceChanged,omitempty"`
+	FilterChanges   []jsondiff.Op        `json:"filterChanges,omitempty"`
+	Distribution    []DistributionChange `json:"distribution,omitempty"`
+	From            *api.Target          `json:"-"`
+	To              *api.Target          `json:"-"`
+}
+
+// Moved reports whether the target changed position.
+func (c TargetChange) Moved() bool {
+	return c.FromIndex >= 0 && c.ToIndex >= 0 && c.FromIndex != c.ToIndex
+}
+
+// Diff is the result of comparing two environment configurations.
+type Diff struct {
+	FromStatus string         `json:"fromStatus"`
+	ToStatus   string         `json:"toStatus"`
+	Targets    []TargetChange `json:"targets"`
+
+	from *api.EnvironmentConfig
+	to   *api.EnvironmentConfig
+}
+
+// HasChanges reports whether the two configurations differ.
+func (d *Diff) HasChanges() bool {
+	if d.FromStatus != d.ToStatus {
+		return true
+	}
+	for _, t := range d.Targets {
+		if t.Kind != Unchanged || t.Moved() {
+			return true
+		}
+	}
+	return false
+}
+
+// Compare aligns the targets of two configurations by name and then by
+// audience, and reports how each aligned pair differs. A nil configuration
+// is treated as an inactive configuration without targets.
+func Compare(from, to *api.EnvironmentConfig) (*Diff, error) {
+	if from == nil {
+		from = &api.EnvironmentConfig{}
+	}
+	if to == nil {
+		to = &api.EnvironmentConfig{}
+	}
+
+	d := &Diff{
+		FromStatus: from.Status,
+		ToStatus:   to.Status,
+		from:       from,
+		to:         to,
+	}
+
+	toMatch, err := alignTargets(from.Targets, to.Targets)
+	if err != nil {
+		return nil, err
+	}
+	matchedFrom := make(map[int]bool, len(toMatch))
+	for _, fi := range toMatch {
+		matchedFrom[fi] = true
+	}
+
+	for fi := range from.Targets {
+		if matchedFrom[fi] {
+			continue
+		}
+		d.Targets = append(d.Targets, TargetChange{
+			Kind:      Removed,
+			Name:      targetLabel(&from.Targets[fi], fi),
+			FromIndex: fi,
+			ToIndex:   -1,
+			From:      &from.Targets[fi],
+		})
+	}
+
+	for ti := range to.Targets {
+		fi, ok := toMatch[ti]
+		if !ok {
+			d.Targets = append(d.Targets, TargetChange{
+				Kind:      Added,
+				Name:      targetLabel(&to.Targets[ti], ti),
+				FromIndex: -1,
+				ToIndex:   ti,
+				To:        &to.Targets[ti],
+			})
+			continue
+		}
+
+		change, err := compareTarget(&from.Targets[fi], &to.Targets[ti])
+		if err != nil {
+			return nil, err
+		}
+		change.Name = targetLabel(&to.Targets[ti], ti)
+		change.FromIndex = fi
+		change.ToIndex = ti
+		d.Targets = append(d.Targets, change)
+	}
+
+	return d, nil
+}
+
+// alignTargets returns a map from target index in to to the matching
+// target index in from. Targets are matched by name first, then by
+// identical audience.
+func alignTargets(from, to []api.Target) (map[int]int, error) {
+	matches := make(map[int]int)
+	used := make(map[int]bool)
+
+	for ti, t := range to {
+		if t.Name == "" {
+			continue
+		}
+		for fi, f := range from {
+			if !used[fi] && f.Name == t.Name {
+				matches[ti] = fi
+				used[fi] = true
+				break
+			}
+		}
+	}
+
+	for ti, t := range to {
+		if _, ok := matches[ti]; ok {
+			continue
+		}
+		ta, err := jsondiff.Normalize(t.Audience)
+		if err != nil {
+			return nil, err
+		}
+		for fi, f := range from {
+			if used[fi] {
+				continue
+			}
+			fa, err := jsondiff.Normalize(f.Audience)
+			if err != nil {
+				return nil, err
+			}
+			if reflect.DeepEqual(fa, ta) {
+				matches[ti] = fi
+				used[fi] = true
+				break
+			}
+		}
+	}
+
+	return matches, nil
+}
+
+func compareTarget(from, to *api.Target) (TargetChange, error) {
+	change := TargetChange{Kind: Unchanged, From: from, To: to}
+
+	fa, err := jsondiff.Normalize(from.Audience.Filters)
+	if err != nil {
+		return change, err
+	}
+	ta, err := jsondiff.Normalize(to.Audience.Filters)
+	if err != nil {
+		return change, err
+	}
+	change.FilterChanges = jsondiff.Compare("", fa, ta)
+	change.AudienceChanged = len(change.FilterChanges) > 0 || from.Audience.Name != to.Audience.Name
+	change.Distribution = compareDistribution(from.Distribution, to.Distribution)
+
+	if change.AudienceChanged || len(change.Distribution) > 0 || from.Name != to.Name {
+		change.Kind = Modified
+	}
+	return change, nil
+}
+
+func compareDistribution(from, to []api.Distribution) []DistributionChange {
+	fromPct := make(map[string]float64)
+	toPct := make(map[string]float64)
+	var keys []string
+	for _, d := range from {
+		if _, ok := fromPct[d.Variation]; !ok {
+			keys = append(keys, d.Variation)
+		}
+		fromPct[d.Variation] += d.Percentage
+	}
+	for _, d := range to {
+		if _, seen := fromPct[d.Variation]; !seen {
+			if _, ok := toPct[d.Variation]; !ok {
+				keys = append(keys, d.Variation)
+			}
+		}
+		toPct[d.Variation] += d.Percentage
+	}
+
+	var changes []DistributionChange
+	for _, k := range keys {
+		if fromPct[k] != toPct[k] {
+			changes = append(changes, DistributionChange{Variation: k, From: fromPct[k], To: toPct[k]})
+		}
+	}
+	return changes
+}
+
+func targetLabel(t *api.Target, index int) string {
+	if t.Name != "" {
+		return t.Name
+	}
+	if t.Audience.Name != "" {
+		return t.Audience.Name
+	}
+	return fmt.Sprintf("(unnamed #%d)", index+1)
+}
+
+// Patch returns JSON Patch operations that transform the source configuration
+// into the destination configuration when applied in order.
+func (d *Diff) Patch() ([]jsondiff.Op, error) {
+	var ops []jsondiff.Op
+
+	if d.from.Status != d.to.Status {
+		ops = append(ops, jsondiff.Op{Op: jsondiff.OpReplace, Path: "/status", Value: d.to.Status, Old: d.from.Status})
+	}
+
+	switch {
+	case len(d.from.Targets) == 0 && len(d.to.Targets) == 0:
+		return ops, nil
+	case len(d.from.Targets) == 0:
+		return append(ops, jsondiff.Op{Op: jsondiff.OpAdd, Path: "/targets", Value: d.to.Targets}), nil
+	case len(d.to.Targets) == 0:
+		return append(ops, jsondiff.Op{Op: jsondiff.OpRemove, Path: "/targets", Old: d.from.Targets}), nil
+	}
+
+	toMatch := make(map[int]int)
+	var removed []int
+	for _, t := range d.Targets {
+		switch {
+		case t.Kind == Removed:
+			removed = append(removed, t.FromIndex)
+		case t.Kind != Added:
+			toMatch[t.ToIndex] = t.FromIndex
+		}
+	}
+
+	sort.Sort(sort.Reverse(sort.IntSlice(removed)))
+	for _, fi := range removed {
+		ops = append(ops, jsondiff.Op{Op: jsondiff.OpRemove, Path: targetPath(fi), Old: d.from.Targets[fi]})
+	}
+
+	// working tracks the source index of each target currently in the list
+	// as the patch is applied; -1 marks targets added by the patch.
+	var working []int
+	removedSet := make(map[int]bool, len(removed))
+	for _, fi := range removed {
+		removedSet[fi] = true
+	}
+	for fi := range d.from.Targets {
+		if !removedSet[fi] {
+			working = append(working, fi)
+		}
+	}
+
+	for ti := range d.to.Targets {
+		fi, ok := toMatch[ti]
+		if !ok {
+			ops = append(ops, jsondiff.Op{Op: jsondiff.OpAdd, Path: targetPath(ti), Value: d.to.Targets[ti]})
+			working = insertAt(working, ti, -1)
+			continue
+		}
+
+		k := indexOf(working, fi)
+		if k != ti {
+			ops = append(ops, jsondiff.Op{Op: jsondiff.OpMove, From: targetPath(k), Path: targetPath(ti)})
+			working = insertAt(append(working[:k], working[k+1:]...), ti, fi)
+		}
+
+		// Target IDs are assigned per environment, so they are not compared.
+		from, to := d.from.Targets[fi], d.to.Targets[ti]
+		from.ID, to.ID = "", ""
+		ft, err := jsondiff.Normalize(from)
+		if err != nil {
+			return nil, err
+		}
+		tt, err := jsondiff.Normalize(to)
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, jsondiff.Compare(targetPath(ti), ft, tt)...)
+	}
+
+	return ops, nil
+}
+
+func targetPath(i int) string {
+	return "/targets/" + strconv.Itoa(i)
+}
+
+func indexOf(s []int, v int) int {
+	for i, x := range s {
+		if x == v {
+			return i
+		}
+	}
+	return -1
+}
+
+func insertAt(s []int, i, v int) []int {
+	s = append(s, 0)
+	copy(s[i+1:], s[i:])
+	s[i] = v
+	return s
+}
+
+// WriteText renders the diff in a readable, line-oriented form.
+// fromLabel and toLabel identify the two sides in the header.
+func (d *Diff) WriteText(w io.Writer, fromLabel, toLabel string, color bool) {
+	fmt.Fprintln(w, output.Colorize("--- "+fromLabel, output.ColorRed, color))
+	fmt.Fprintln(w, output.Colorize("+++ "+toLabel, output.ColorGreen, color))
+
+	if d.FromStatus != d.ToStatus {
+		fmt.Fprintln(w, output.Colorize(fmt.Sprintf("~ status: %s -> %s", statusLabel(d.FromStatus), statusLabel(d.ToStatus)), output.ColorYellow, color))
+	} else {
+		fmt.Fprintf(w, "  status: %s\n", statusLabel(d.FromStatus))
+	}
+
+	if len(d.Targets) == 0 {
+		fmt.Fprintln(w, "  (no targets)")
+		return
+	}
+
+	for _, t := range d.Targets {
+		switch t.Kind {
+		case Added:
+			fmt.Fprintln(w, output.Colorize("+ "+t.Name, output.ColorGreen, color))
+			writeTargetDetails(w, "+", t.To, output.ColorGreen, color)
+		case Removed:
+			fmt.Fprintln(w, output.Colorize("- "+t.Name, output.ColorRed, color))
+			writeTargetDetails(w, "-", t.From, output.ColorRed, color)
+		default:
+			header := t.Name
+			if t.Moved() {
+				header += fmt.Sprintf(" (position %d -> %d)", t.FromIndex+1, t.ToIndex+1)
+			}
+			if t.Kind == Unchanged && !t.Moved() {
+				fmt.Fprintln(w, "  "+header)
+				continue
+			}
+			fmt.Fprintln(w, output.Colorize("~ "+header, output.ColorYellow, color))
+			if t.From.Audience.Name != t.To.Audience.Name {
+				fmt.Fprintln(w, output.Colorize(fmt.Sprintf("    - audience: %s", t.From.Audience.Name), output.ColorRed, color))
+				fmt.Fprintln(w, output.Colorize(fmt.Sprintf("    + audience: %s", t.To.Audience.Name), output.ColorGreen, color))
+			}
+			if len(t.FilterChanges) > 0 {
+				fmt.Fprintln(w, output.Colorize("    - filters: "+formatFilters(t.From.Audience.Filters), output.ColorRed, color))
+				fmt.Fprintln(w, output.Colorize("    + filters: "+formatFilters(t.To.Audience.Filters), output.ColorGreen, color))
+			}
+			for _, dc := range t.Distribution {
+				fmt.Fprintln(w, output.Colorize(fmt.Sprintf("    ~ %s: %s -> %s", dc.Variation, formatPercentage(dc.From), formatPercentage(dc.To)), output.ColorYellow, color))
+			}
+		}
+	}
+}
+
+func writeTargetDetails(w io.Writer, prefix string, t *api.Target, colorCode string, color bool) {
+	fmt.Fprintln(w, output.Colorize(fmt.Sprintf("    %s filters: %s", prefix, formatFilters(t.Audience.Filters)), colorCode, color))
+	fmt.Fprintln(w, output.Colorize(fmt.Sprintf("    %s serve: %s", prefix, FormatDistribution(t.Distribution)), colorCode, color))
+}
+
+func formatFilters(f api.Filters) string {
+	if len(f.Filters) == 0 {
+		return "(none)"
+	}
+	return filterexpr.Format(f)
+}
+
+// FormatDistribution renders a distribution as "variation pct, ...".
+func FormatDistribution(dist []api.Distribution) string {
+	if len(dist) == 0 {
+		return "(none)"
+	}
+	s := ""
+	for i, d := range dist {
+		if i > 0 {
+			s += ", "
+		}
+		s += d.Variation + " " + formatPercentage(d.Percentage)
+	}
+	return s
+}
+
+func formatPercentage(p float64) string {
+	return strconv.FormatFloat(p*100, 'f', -1, 64) + "%"
+}
+
+func statusLabel(s string) string {
+	if s == "" {
+		return "(unset)"
+	}
+	return s
+}
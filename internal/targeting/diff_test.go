@@ -0,0 +1,294 @@
+package targeting
+
+import (
+	"bytes"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/135yshr/devcycle-cli/internal/jsondiff"
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+func allUsersTarget(name string, dist ...api.Distribution) api.Target {
+	return api.Target{
+		Name: name,
+		Audience: api.Audience{
+			Filters: api.Filters{Operator: "and", Filters: []api.Filter{{Type: "all"}}},
+		},
+		Distribution: dist,
+	}
+}
+
+func emailTarget(name, domain string, dist ...api.Distribution) api.Target {
+	return api.Target{
+		Name: name,
+		Audience: api.Audience{
+			Filters: api.Filters{
+				Operator: "and",
+				Filters: []api.Filter{{
+					Type:       "user",
+					SubType:    "email",
+					Comparator: "endWith",
+					Values:     []any{domain},
+				}},
+			},
+		},
+		Distribution: dist,
+	}
+}
+
+func TestCompare(t *testing.T) {
+	on := api.Distribution{Variation: "on", Percentage: 1}
+
+	t.Run("identical configurations", func(t *testing.T) {
+		cfg := &api.EnvironmentConfig{Status: "active", Targets: []api.Target{allUsersTarget("All Users", on)}}
+		d, err := Compare(cfg, cfg)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if d.HasChanges() {
+			t.Error("expected no changes")
+		}
+	})
+
+	t.Run("status and distribution changes", func(t *testing.T) {
+		from := &api.EnvironmentConfig{Status: "active", Targets: []api.Target{
+			allUsersTarget("All Users", api.Distribution{Variation: "on", Percentage: 0.5}, api.Distribution{Variation: "off", Percentage: 0.5}),
+		}}
+		to := &api.EnvironmentConfig{Status: "inactive", Targets: []api.Target{allUsersTarget("All Users", on)}}
+
+		d, err := Compare(from, to)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !d.HasChanges() {
+			t.Fatal("expected changes")
+		}
+		if len(d.Targets) != 1 || d.Targets[0].Kind != Modified {
+			t.Fatalf("expected one modified target, got %+v", d.Targets)
+		}
+		dist := d.Targets[0].Distribution
+		if len(dist) != 2 {
+			t.Fatalf("expected 2 distribution changes, got %+v", dist)
+		}
+		if dist[0].Variation != "on" || dist[0].From != 0.5 || dist[0].To != 1 {
+			t.Errorf("unexpected change for on: %+v", dist[0])
+		}
+		if dist[1].Variation != "off" || dist[1].To != 0 {
+			t.Errorf("unexpected change for off: %+v", dist[1])
+		}
+	})
+
+	t.Run("aligns by name and audience", func(t *testing.T) {
+		from := &api.EnvironmentConfig{Status: "active", Targets: []api.Target{
+			emailTarget("beta", "@acme.com", on),
+			allUsersTarget("", on),
+			emailTarget("old", "@old.com", on),
+		}}
+		to := &api.EnvironmentConfig{Status: "active", Targets: []api.Target{
+			allUsersTarget("Everyone", on),
+			emailTarget("beta", "@example.com", on),
+			emailTarget("new", "@new.com", on),
+		}}
+
+		d, err := Compare(from, to)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		kinds := map[string]ChangeKind{}
+		for _, tc := range d.Targets {
+			kinds[tc.Name] = tc.Kind
+		}
+		if kinds["old"] != Removed {
+			t.Errorf("expected old to be removed, got %s", kinds["old"])
+		}
+		if kinds["new"] != Added {
+			t.Errorf("expected new to be added, got %s", kinds["new"])
+		}
+		if kinds["beta"] != Modified {
+			t.Errorf("expected beta to be modified, got %s", kinds["beta"])
+		}
+		if kinds["Everyone"] != Modified {
+			t.Errorf("expected Everyone (matched by audience) to be modified, got %s", kinds["Everyone"])
+		}
+	})
+}
+
+func TestDiff_Patch(t *testing.T) {
+	on := api.Distribution{Variation: "on", Percentage: 1}
+	half := []api.Distribution{{Variation: "on", Percentage: 0.5}, {Variation: "off", Percentage: 0.5}}
+
+	tests := []struct {
+		name string
+		from *api.EnvironmentConfig
+		to   *api.EnvironmentConfig
+	}{
+		{
+			name: "reorder, add and remove",
+			from: &api.EnvironmentConfig{Status: "active", Targets: []api.Target{
+				emailTarget("a", "@a.com", on),
+				emailTarget("b", "@b.com", on),
+				emailTarget("c", "@c.com", on),
+				allUsersTarget("all", half...),
+			}},
+			to: &api.EnvironmentConfig{Status: "inactive", Targets: []api.Target{
+				allUsersTarget("all", on),
+				emailTarget("d", "@d.com", on),
+				emailTarget("a", "@a.org", on),
+			}},
+		},
+		{
+			name: "from empty",
+			from: &api.EnvironmentConfig{Status: "inactive"},
+			to:   &api.EnvironmentConfig{Status: "active", Targets: []api.Target{allUsersTarget("all", on)}},
+		},
+		{
+			name: "to empty",
+			from: &api.EnvironmentConfig{Status: "active", Targets: []api.Target{allUsersTarget("all", on)}},
+			to:   &api.EnvironmentConfig{Status: "active"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := Compare(tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			ops, err := d.Patch()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			doc, _ := jsondiff.Normalize(tt.from)
+			for _, op := range ops {
+				doc = applyOp(t, doc, op)
+			}
+			want, _ := jsondiff.Normalize(tt.to)
+			if !reflect.DeepEqual(doc, want) {
+				t.Errorf("patched document mismatch\n got: %s\nwant: %s", jsondiff.FormatValue(doc), jsondiff.FormatValue(want))
+			}
+		})
+	}
+}
+
+func TestDiff_WriteText(t *testing.T) {
+	on := api.Distribution{Variation: "on", Percentage: 1}
+	from := &api.EnvironmentConfig{Status: "active", Targets: []api.Target{emailTarget("beta", "@acme.com", on)}}
+	to := &api.EnvironmentConfig{Status: "inactive", Targets: []api.Target{
+		emailTarget("beta", "@acme.com", api.Distribution{Variation: "on", Percentage: 0.25}, api.Distribution{Variation: "off", Percentage: 0.75}),
+		allUsersTarget("rest", api.Distribution{Variation: "off", Percentage: 1}),
+	}}
+
+	d, err := Compare(from, to)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	d.WriteText(&buf, "staging", "production", false)
+	out := buf.String()
+
+	for _, want := range []string{
+		"--- staging",
+		"+++ production",
+		"~ status: active -> inactive",
+		"~ beta",
+		"~ on: 100% -> 25%",
+		"~ off: 0% -> 75%",
+		"+ rest",
+		"+ serve: off 100%",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+// applyOp applies a single JSON Patch operation to a normalized document.
+func applyOp(t *testing.T, doc any, op jsondiff.Op) any {
+	t.Helper()
+	switch op.Op {
+	case jsondiff.OpMove:
+		v := getPath(t, doc, op.From)
+		doc = setPath(t, doc, op.From, nil, true, false)
+		v2, _ := jsondiff.Normalize(v)
+		return setPath(t, doc, op.Path, v2, false, true)
+	case jsondiff.OpRemove:
+		return setPath(t, doc, op.Path, nil, true, false)
+	case jsondiff.OpAdd:
+		v, _ := jsondiff.Normalize(op.Value)
+		return setPath(t, doc, op.Path, v, false, true)
+	default:
+		v, _ := jsondiff.Normalize(op.Value)
+		return setPath(t, doc, op.Path, v, false, false)
+	}
+}
+
+func splitPath(path string) []string {
+	parts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	for i, p := range parts {
+		p = strings.ReplaceAll(p, "~1", "/")
+		parts[i] = strings.ReplaceAll(p, "~0", "~")
+	}
+	return parts
+}
+
+func getPath(t *testing.T, doc any, path string) any {
+	for _, p := range splitPath(path) {
+		switch v := doc.(type) {
+		case map[string]any:
+			doc = v[p]
+		case []any:
+			i, _ := strconv.Atoi(p)
+			doc = v[i]
+		default:
+			t.Fatalf("cannot traverse %s", path)
+		}
+	}
+	return doc
+}
+
+func setPath(t *testing.T, doc any, path string, value any, remove, insert bool) any {
+	parts := splitPath(path)
+	var set func(node any, parts []string) any
+	set = func(node any, parts []string) any {
+		key := parts[0]
+		last := len(parts) == 1
+		switch v := node.(type) {
+		case map[string]any:
+			if last {
+				if remove {
+					delete(v, key)
+				} else {
+					v[key] = value
+				}
+				return v
+			}
+			v[key] = set(v[key], parts[1:])
+			return v
+		case []any:
+			i, _ := strconv.Atoi(key)
+			if last {
+				switch {
+				case remove:
+					return append(v[:i:i], v[i+1:]...)
+				case insert:
+					out := append(v[:i:i], value)
+					return append(out, v[i:]...)
+				default:
+					v[i] = value
+					return v
+				}
+			}
+			v[i] = set(v[i], parts[1:])
+			return v
+		}
+		t.Fatalf("cannot set %s", path)
+		return nil
+	}
+	return set(doc, parts)
+}
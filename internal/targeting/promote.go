@@ -0,0 +1,64 @@
+package targeting
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+// Promote returns a deep copy of src that can be written to another
+// environment. Target IDs are cleared so the destination assigns its own.
+// When keepStatus is non-empty it replaces the copied status.
+func Promote(src *api.EnvironmentConfig, keepStatus string) (*api.EnvironmentConfig, error) {
+	if src == nil {
+		return nil, fmt.Errorf("source configuration is empty")
+	}
+
+	data, err := json.Marshal(src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy configuration: %w", err)
+	}
+	var dst api.EnvironmentConfig
+	if err := json.Unmarshal(data, &dst); err != nil {
+		return nil, fmt.Errorf("failed to copy configuration: %w", err)
+	}
+
+	for i := range dst.Targets {
+		dst.Targets[i].ID = ""
+	}
+	if keepStatus != "" {
+		dst.Status = keepStatus
+	}
+	return &dst, nil
+}
+
+// MissingVariations returns the variation references used by the
+// distributions in cfg that match neither the ID nor the key of any of the
+// given variations. The result is sorted and contains no duplicates.
+func MissingVariations(cfg *api.EnvironmentConfig, variations []api.Variation) []string {
+	if cfg == nil {
+		return nil
+	}
+
+	known := make(map[string]bool, len(variations)*2)
+	for _, v := range variations {
+		known[v.ID] = true
+		known[v.Key] = true
+	}
+
+	seen := make(map[string]bool)
+	var missing []string
+	for _, t := range cfg.Targets {
+		for _, d := range t.Distribution {
+			if known[d.Variation] || seen[d.Variation] {
+				continue
+			}
+			seen[d.Variation] = true
+			missing = append(missing, d.Variation)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
@@ -0,0 +1,80 @@
+package targeting
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+func TestPromote(t *testing.T) {
+	src := &api.EnvironmentConfig{
+		Status:  "active",
+		Targets: []api.Target{allUsersTarget("All Users", api.Distribution{Variation: "on", Percentage: 1})},
+	}
+
+	t.Run("deep copy", func(t *testing.T) {
+		dst, err := Promote(src, "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(dst, src) {
+			t.Errorf("expected copy to equal source, got %+v", dst)
+		}
+		dst.Targets[0].Distribution[0].Percentage = 0.5
+		if src.Targets[0].Distribution[0].Percentage != 1 {
+			t.Error("expected source to be unaffected by changes to the copy")
+		}
+	})
+
+	t.Run("keep destination status", func(t *testing.T) {
+		dst, err := Promote(src, "inactive")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if dst.Status != "inactive" {
+			t.Errorf("expected inactive, got %s", dst.Status)
+		}
+	})
+
+	t.Run("clears target IDs", func(t *testing.T) {
+		withID := &api.EnvironmentConfig{Status: "active", Targets: []api.Target{src.Targets[0]}}
+		withID.Targets[0].ID = "target-1"
+		dst, err := Promote(withID, "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if dst.Targets[0].ID != "" {
+			t.Errorf("expected target ID to be cleared, got %q", dst.Targets[0].ID)
+		}
+	})
+
+	t.Run("nil source", func(t *testing.T) {
+		if _, err := Promote(nil, ""); err == nil {
+			t.Error("expected error for nil source")
+		}
+	})
+}
+
+func TestMissingVariations(t *testing.T) {
+	cfg := &api.EnvironmentConfig{
+		Targets: []api.Target{
+			allUsersTarget("a", api.Distribution{Variation: "var-id-1", Percentage: 0.5}, api.Distribution{Variation: "off", Percentage: 0.5}),
+			allUsersTarget("b", api.Distribution{Variation: "ghost", Percentage: 1}),
+			allUsersTarget("c", api.Distribution{Variation: "ghost", Percentage: 1}),
+		},
+	}
+	variations := []api.Variation{
+		{ID: "var-id-1", Key: "on"},
+		{ID: "var-id-2", Key: "off"},
+	}
+
+	missing := MissingVariations(cfg, variations)
+	if !reflect.DeepEqual(missing, []string{"ghost"}) {
+		t.Errorf("expected [ghost], got %v", missing)
+	}
+
+	if got := MissingVariations(nil, variations); got != nil {
+		t.Errorf("expected nil for nil config, got %v", got)
+	}
+}
@@ -0,0 +1,150 @@
+package targeting
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+// DefaultRuleName is the name given to the catch-all rule created by SetDefault.
+const DefaultRuleName = "All Users"
+
+// ParseServe parses a serve specification such as "on:50,off:50" into a
+// distribution keyed by variation. A single variation without a percentage
+// ("on") is served to everyone. Percentages must add up to 100.
+func ParseServe(s string) ([]api.Distribution, error) {
+	var dist []api.Distribution
+	var total float64
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, pctStr, hasPct := strings.Cut(part, ":")
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return nil, fmt.Errorf("invalid serve entry %q: missing variation", part)
+		}
+		if slices.ContainsFunc(dist, func(d api.Distribution) bool { return d.Variation == key }) {
+			return nil, fmt.Errorf("invalid serve entry %q: variation %q is listed more than once", part, key)
+		}
+		pct := 100.0
+		if hasPct {
+			v, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(pctStr), "%"), 64)
+			if err != nil || v < 0 || v > 100 {
+				return nil, fmt.Errorf("invalid serve entry %q: percentage must be between 0 and 100", part)
+			}
+			pct = v
+		}
+		total += pct
+		dist = append(dist, api.Distribution{Variation: key, Percentage: math.Round(pct*1e4) / 1e6})
+	}
+	if len(dist) == 0 {
+		return nil, fmt.Errorf("serve must name at least one variation")
+	}
+	if math.Abs(total-100) > 0.01 {
+		return nil, fmt.Errorf("serve percentages must add up to 100 (got %g)", total)
+	}
+	return dist, nil
+}
+
+// FormatServe renders a distribution as a serve specification accepted by
+// ParseServe, such as "on:50,off:50", or "on" for a single variation.
+func FormatServe(dist []api.Distribution) string {
+	if len(dist) == 1 && dist[0].Percentage == 1 {
+		return dist[0].Variation
+	}
+	parts := make([]string, len(dist))
+	for i, d := range dist {
+		parts[i] = d.Variation + ":" + strconv.FormatFloat(math.Round(d.Percentage*1e6)/1e4, 'f', -1, 64)
+	}
+	return strings.Join(parts, ",")
+}
+
+// IsCatchAll reports whether t targets all users.
+func IsCatchAll(t api.Target) bool {
+	f := t.Audience.Filters.Filters
+	return len(f) == 1 && f[0].Type == "all"
+}
+
+// AddRule inserts target into cfg at position (0-based). A negative position
+// appends the rule, keeping an existing trailing catch-all rule last so it
+// does not shadow the new rule. Rule names must be unique.
+func AddRule(cfg *api.EnvironmentConfig, target api.Target, position int) error {
+	if target.Name == "" {
+		return fmt.Errorf("rule name is required")
+	}
+	if FindTarget(cfg.Targets, target.Name) >= 0 {
+		return fmt.Errorf("rule '%s' already exists", target.Name)
+	}
+
+	n := len(cfg.Targets)
+	if position < 0 {
+		position = n
+		if n > 0 && IsCatchAll(cfg.Targets[n-1]) {
+			position = n - 1
+		}
+	}
+	if position > n {
+		return fmt.Errorf("position %d is out of range (1-%d)", position+1, n+1)
+	}
+
+	cfg.Targets = append(cfg.Targets, api.Target{})
+	copy(cfg.Targets[position+1:], cfg.Targets[position:])
+	cfg.Targets[position] = target
+	return nil
+}
+
+// RemoveRule removes the rule with the given name from cfg.
+func RemoveRule(cfg *api.EnvironmentConfig, name string) error {
+	idx := FindTarget(cfg.Targets, name)
+	if idx < 0 {
+		return fmt.Errorf("rule '%s' not found", name)
+	}
+	cfg.Targets = append(cfg.Targets[:idx], cfg.Targets[idx+1:]...)
+	return nil
+}
+
+// MoveRule moves the rule with the given name to position (0-based).
+func MoveRule(cfg *api.EnvironmentConfig, name string, position int) error {
+	idx := FindTarget(cfg.Targets, name)
+	if idx < 0 {
+		return fmt.Errorf("rule '%s' not found", name)
+	}
+	if position < 0 || position >= len(cfg.Targets) {
+		return fmt.Errorf("position %d is out of range (1-%d)", position+1, len(cfg.Targets))
+	}
+
+	target := cfg.Targets[idx]
+	cfg.Targets = append(cfg.Targets[:idx], cfg.Targets[idx+1:]...)
+	cfg.Targets = append(cfg.Targets, api.Target{})
+	copy(cfg.Targets[position+1:], cfg.Targets[position:])
+	cfg.Targets[position] = target
+	return nil
+}
+
+// SetDefault sets the distribution served to users who match no other rule.
+// The last catch-all rule is updated, or a new one is appended.
+func SetDefault(cfg *api.EnvironmentConfig, dist []api.Distribution) {
+	for i := len(cfg.Targets) - 1; i >= 0; i-- {
+		if IsCatchAll(cfg.Targets[i]) {
+			cfg.Targets[i].Distribution = dist
+			return
+		}
+	}
+	cfg.Targets = append(cfg.Targets, api.Target{
+		Name: DefaultRuleName,
+		Audience: api.Audience{
+			Name: DefaultRuleName,
+			Filters: api.Filters{
+				Operator: "and",
+				Filters:  []api.Filter{{Type: "all"}},
+			},
+		},
+		Distribution: dist,
+	})
+}
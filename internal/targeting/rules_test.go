@@ -0,0 +1,141 @@
+package targeting
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+func TestParseServe(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []api.Distribution
+	}{
+		{"on", []api.Distribution{{Variation: "on", Percentage: 1}}},
+		{"on:50,off:50", []api.Distribution{{Variation: "on", Percentage: 0.5}, {Variation: "off", Percentage: 0.5}}},
+		{"a:33.3, b:33.3%, c:33.4", []api.Distribution{{Variation: "a", Percentage: 0.333}, {Variation: "b", Percentage: 0.333}, {Variation: "c", Percentage: 0.334}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseServe(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	for _, input := range []string{"", "on:50", "on:abc", ":100", "on:150,off:-50", "a:50,a:50"} {
+		t.Run("invalid "+input, func(t *testing.T) {
+			if _, err := ParseServe(input); err == nil {
+				t.Errorf("expected error for %q", input)
+			}
+		})
+	}
+}
+
+func TestFormatServe(t *testing.T) {
+	for _, serve := range []string{"on", "on:50,off:50", "a:33.3,b:33.3,c:33.4"} {
+		dist, err := ParseServe(serve)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := FormatServe(dist); got != serve {
+			t.Errorf("expected %q, got %q", serve, got)
+		}
+	}
+}
+
+func ruleNames(cfg *api.EnvironmentConfig) []string {
+	return TargetNames(cfg.Targets)
+}
+
+func TestAddRule(t *testing.T) {
+	on := api.Distribution{Variation: "on", Percentage: 1}
+
+	t.Run("keeps catch-all last", func(t *testing.T) {
+		cfg := &api.EnvironmentConfig{Targets: []api.Target{emailTarget("a", "@a.com", on), allUsersTarget("all", on)}}
+		if err := AddRule(cfg, emailTarget("b", "@b.com", on), -1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := ruleNames(cfg); !reflect.DeepEqual(got, []string{"a", "b", "all"}) {
+			t.Errorf("unexpected order: %v", got)
+		}
+	})
+
+	t.Run("explicit position", func(t *testing.T) {
+		cfg := &api.EnvironmentConfig{Targets: []api.Target{emailTarget("a", "@a.com", on)}}
+		if err := AddRule(cfg, emailTarget("b", "@b.com", on), 0); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := ruleNames(cfg); !reflect.DeepEqual(got, []string{"b", "a"}) {
+			t.Errorf("unexpected order: %v", got)
+		}
+	})
+
+	t.Run("duplicate name", func(t *testing.T) {
+		cfg := &api.EnvironmentConfig{Targets: []api.Target{emailTarget("a", "@a.com", on)}}
+		if err := AddRule(cfg, emailTarget("a", "@b.com", on), -1); err == nil {
+			t.Error("expected error for duplicate rule")
+		}
+	})
+
+	t.Run("out of range", func(t *testing.T) {
+		cfg := &api.EnvironmentConfig{}
+		if err := AddRule(cfg, emailTarget("a", "@a.com", on), 3); err == nil {
+			t.Error("expected error for out of range position")
+		}
+	})
+}
+
+func TestRemoveAndMoveRule(t *testing.T) {
+	on := api.Distribution{Variation: "on", Percentage: 1}
+	cfg := &api.EnvironmentConfig{Targets: []api.Target{
+		emailTarget("a", "@a.com", on),
+		emailTarget("b", "@b.com", on),
+		emailTarget("c", "@c.com", on),
+	}}
+
+	if err := MoveRule(cfg, "c", 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := ruleNames(cfg); !reflect.DeepEqual(got, []string{"c", "a", "b"}) {
+		t.Errorf("unexpected order after move: %v", got)
+	}
+
+	if err := RemoveRule(cfg, "a"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := ruleNames(cfg); !reflect.DeepEqual(got, []string{"c", "b"}) {
+		t.Errorf("unexpected order after remove: %v", got)
+	}
+
+	if err := RemoveRule(cfg, "missing"); err == nil {
+		t.Error("expected error removing missing rule")
+	}
+	if err := MoveRule(cfg, "b", 5); err == nil {
+		t.Error("expected error for out of range move")
+	}
+}
+
+func TestSetDefault(t *testing.T) {
+	on := []api.Distribution{{Variation: "on", Percentage: 1}}
+	off := []api.Distribution{{Variation: "off", Percentage: 1}}
+
+	cfg := &api.EnvironmentConfig{Targets: []api.Target{emailTarget("a", "@a.com", on...)}}
+	SetDefault(cfg, off)
+	if len(cfg.Targets) != 2 || !IsCatchAll(cfg.Targets[1]) || cfg.Targets[1].Name != DefaultRuleName {
+		t.Fatalf("expected catch-all rule to be appended, got %+v", cfg.Targets)
+	}
+
+	SetDefault(cfg, on)
+	if len(cfg.Targets) != 2 {
+		t.Fatalf("expected existing catch-all to be updated, got %d targets", len(cfg.Targets))
+	}
+	if !reflect.DeepEqual(cfg.Targets[1].Distribution, on) {
+		t.Errorf("expected default distribution to be updated, got %v", cfg.Targets[1].Distribution)
+	}
+}
@@ -0,0 +1,83 @@
+package targeting
+
+import (
+	"fmt"
+
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+// FindTarget returns the index of the target whose name, or audience name,
+// equals name. It returns -1 when no target matches.
+func FindTarget(targets []api.Target, name string) int {
+	for i, t := range targets {
+		if t.Name == name {
+			return i
+		}
+	}
+	for i, t := range targets {
+		if t.Name == "" && t.Audience.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// TargetNames returns a display label for each target.
+func TargetNames(targets []api.Target) []string {
+	names := make([]string, len(targets))
+	for i := range targets {
+		names[i] = targetLabel(&targets[i], i)
+	}
+	return names
+}
+
+// ResolveVariation returns the identifier to use in a distribution for the
+// variation referenced by ref, which may be a variation key or ID. The
+// existing distribution decides the form: if it references variations by
+// key, the key is returned, otherwise the ID.
+func ResolveVariation(variations []api.Variation, dist []api.Distribution, ref string) (string, error) {
+	var found *api.Variation
+	for i := range variations {
+		if variations[i].Key == ref || variations[i].ID == ref {
+			found = &variations[i]
+			break
+		}
+	}
+	if found == nil {
+		return "", fmt.Errorf("variation '%s' not found", ref)
+	}
+	if found.ID == "" {
+		return found.Key, nil
+	}
+
+	for _, d := range dist {
+		for _, v := range variations {
+			if d.Variation == v.Key && v.Key != v.ID {
+				return found.Key, nil
+			}
+		}
+	}
+	return found.ID, nil
+}
+
+// ResolveServe parses a serve specification and maps its variation keys to
+// the form already used by the distributions of cfg.
+func ResolveServe(serve string, cfg *api.EnvironmentConfig, variations []api.Variation) ([]api.Distribution, error) {
+	dist, err := ParseServe(serve)
+	if err != nil {
+		return nil, err
+	}
+
+	var existing []api.Distribution
+	for _, t := range cfg.Targets {
+		existing = append(existing, t.Distribution...)
+	}
+	for i := range dist {
+		ref, err := ResolveVariation(variations, existing, dist[i].Variation)
+		if err != nil {
+			return nil, err
+		}
+		dist[i].Variation = ref
+	}
+	return dist, nil
+}
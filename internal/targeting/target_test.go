@@ -0,0 +1,95 @@
+package targeting
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+func TestFindTarget(t *testing.T) {
+	targets := []api.Target{
+		{Name: "beta"},
+		{Audience: api.Audience{Name: "Everyone"}},
+		{Name: "other", Audience: api.Audience{Name: "beta-audience"}},
+	}
+
+	tests := []struct {
+		name string
+		want int
+	}{
+		{"beta", 0},
+		{"Everyone", 1},
+		{"beta-audience", -1},
+		{"missing", -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FindTarget(targets, tt.name); got != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTargetNames(t *testing.T) {
+	targets := []api.Target{{Name: "beta"}, {Audience: api.Audience{Name: "Everyone"}}, {}}
+	want := []string{"beta", "Everyone", "(unnamed #3)"}
+	if got := TargetNames(targets); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestResolveVariation(t *testing.T) {
+	variations := []api.Variation{
+		{ID: "id-on", Key: "on"},
+		{ID: "id-off", Key: "off"},
+	}
+
+	t.Run("uses IDs when the distribution does", func(t *testing.T) {
+		got, err := ResolveVariation(variations, []api.Distribution{{Variation: "id-off", Percentage: 1}}, "on")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != "id-on" {
+			t.Errorf("expected id-on, got %s", got)
+		}
+	})
+
+	t.Run("uses keys when the distribution does", func(t *testing.T) {
+		got, err := ResolveVariation(variations, []api.Distribution{{Variation: "off", Percentage: 1}}, "id-on")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != "on" {
+			t.Errorf("expected on, got %s", got)
+		}
+	})
+
+	t.Run("unknown variation", func(t *testing.T) {
+		if _, err := ResolveVariation(variations, nil, "missing"); err == nil {
+			t.Error("expected error for unknown variation")
+		}
+	})
+}
+
+func TestResolveServe(t *testing.T) {
+	variations := []api.Variation{
+		{ID: "id-on", Key: "on"},
+		{ID: "id-off", Key: "off"},
+	}
+	cfg := &api.EnvironmentConfig{Targets: []api.Target{{Distribution: []api.Distribution{{Variation: "id-off", Percentage: 1}}}}}
+
+	got, err := ResolveServe("on:25,off:75", cfg, variations)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []api.Distribution{{Variation: "id-on", Percentage: 0.25}, {Variation: "id-off", Percentage: 0.75}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	if _, err := ResolveServe("on:50,beta:50", cfg, variations); err == nil {
+		t.Error("expected error for unknown variation")
+	}
+}
@@ -0,0 +1,390 @@
+// Package templates renders feature definitions from parameterized
+// templates, so that features with the same structure, such as kill
+// switches or A/B tests, are created the same way every time.
+//
+// A template is a YAML file of two documents. The first describes the
+// template and its parameters; the second is a text/template that renders
+// a feature definition, as accepted by 'features create --from-file', with
+// the parameter values as its data.
+package templates
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/135yshr/devcycle-cli/pkg/api"
+	"gopkg.in/yaml.v3"
+)
+
+// SourceBuiltin is the source of the templates that come with dvcx.
+const SourceBuiltin = "built-in"
+
+//go:embed builtin/*.yaml
+var builtinFS embed.FS
+
+// Parameter is a value a template needs.
+type Parameter struct {
+	Name        string `yaml:"name" json:"name"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	Required    bool   `yaml:"required,omitempty" json:"required,omitempty"`
+	// Default is a template rendered with the parameters declared before
+	// this one, as in "{{ .key }}".
+	Default string `yaml:"default,omitempty" json:"default,omitempty"`
+	// Pattern is a regular expression that values must match.
+	Pattern string `yaml:"pattern,omitempty" json:"pattern,omitempty"`
+}
+
+// Template is a parameterized feature definition.
+type Template struct {
+	Name        string      `yaml:"name" json:"name"`
+	Description string      `yaml:"description,omitempty" json:"description,omitempty"`
+	Parameters  []Parameter `yaml:"parameters,omitempty" json:"parameters,omitempty"`
+	// Source is SourceBuiltin or the path of the template file.
+	Source string `yaml:"-" json:"source"`
+	// Body is the text/template of the feature definition.
+	Body string `yaml:"-" json:"body"`
+	// Raw is the content of the template file.
+	Raw []byte `yaml:"-" json:"-"`
+}
+
+// separator ends the document that describes a template.
+var separator = regexp.MustCompile(`(?m)^---[ \t]*\r?\n`)
+
+// Parse parses a template file. source names the file in errors.
+func Parse(data []byte, source string) (*Template, error) {
+	loc := separator.FindIndex(data)
+	if loc == nil || loc[0] == 0 {
+		return nil, fmt.Errorf("template %s: expected a description and a feature separated by ---", source)
+	}
+
+	var t Template
+	if err := yaml.Unmarshal(data[:loc[0]], &t); err != nil {
+		return nil, fmt.Errorf("template %s: %w", source, err)
+	}
+	if t.Name == "" {
+		return nil, fmt.Errorf("template %s: name is required", source)
+	}
+	seen := make(map[string]bool, len(t.Parameters))
+	for _, p := range t.Parameters {
+		if p.Name == "" {
+			return nil, fmt.Errorf("template %s: parameter name is required", source)
+		}
+		if seen[p.Name] {
+			return nil, fmt.Errorf("template %s: duplicate parameter %q", source, p.Name)
+		}
+		seen[p.Name] = true
+		if p.Pattern != "" {
+			if _, err := regexp.Compile(p.Pattern); err != nil {
+				return nil, fmt.Errorf("template %s: invalid pattern for parameter %q: %w", source, p.Name, err)
+			}
+		}
+	}
+	t.Source = source
+	t.Body = string(data[loc[1]:])
+	t.Raw = data
+	if _, err := t.parseBody(); err != nil {
+		return nil, fmt.Errorf("template %s: %w", source, err)
+	}
+	return &t, nil
+}
+
+// Builtin returns the templates that come with dvcx, sorted by name.
+func Builtin() []*Template {
+	entries, _ := fs.ReadDir(builtinFS, "builtin")
+	var list []*Template
+	for _, e := range entries {
+		data, err := builtinFS.ReadFile("builtin/" + e.Name())
+		if err != nil {
+			panic(err)
+		}
+		t, err := Parse(data, SourceBuiltin)
+		if err != nil {
+			panic(err)
+		}
+		list = append(list, t)
+	}
+	sortTemplates(list)
+	return list
+}
+
+// Load returns the built-in templates and the templates in dir, sorted by
+// name. A template in dir replaces the built-in template of the same
+// name. A missing dir holds no templates.
+func Load(dir string) ([]*Template, error) {
+	byName := make(map[string]*Template)
+	for _, t := range Builtin() {
+		byName[t.Name] = t
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.y*ml"))
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		if ext := filepath.Ext(path); ext != ".yaml" && ext != ".yml" {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template: %w", err)
+		}
+		t, err := Parse(data, path)
+		if err != nil {
+			return nil, err
+		}
+		if prev, ok := byName[t.Name]; ok && prev.Source != SourceBuiltin {
+			return nil, fmt.Errorf("template %q is defined by both %s and %s", t.Name, prev.Source, path)
+		}
+		byName[t.Name] = t
+	}
+
+	list := make([]*Template, 0, len(byName))
+	for _, t := range byName {
+		list = append(list, t)
+	}
+	sortTemplates(list)
+	return list, nil
+}
+
+// Find returns the template called name from Load(dir).
+func Find(dir, name string) (*Template, error) {
+	list, err := Load(dir)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(list))
+	for i, t := range list {
+		if t.Name == name {
+			return t, nil
+		}
+		names[i] = t.Name
+	}
+	return nil, fmt.Errorf("unknown template %q (available: %s)", name, strings.Join(names, ", "))
+}
+
+func sortTemplates(list []*Template) {
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+}
+
+// Skeleton returns the content of a new template file called name.
+func Skeleton(name string) []byte {
+	return []byte(`name: ` + name + `
+description: Describe when to use this template
+parameters:
+  - name: key
+    description: Feature key
+    required: true
+    pattern: ^[a-z0-9._-]+$
+  - name: name
+    description: Feature name
+    default: '{{ title .key }}'
+---
+name: {{ quote .name }}
+key: {{ quote .key }}
+type: release
+variables:
+  - key: {{ quote .key }}
+    type: Boolean
+variations:
+  - key: "on"
+    name: "On"
+    variables: { {{ quote .key }}: true }
+  - key: "off"
+    name: "Off"
+    variables: { {{ quote .key }}: false }
+`)
+}
+
+// ParseValues parses name=value pairs, as given with --set.
+func ParseValues(pairs []string) (map[string]string, error) {
+	values := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		name, value, ok := strings.Cut(pair, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid parameter %q (expected name=value)", pair)
+		}
+		values[name] = value
+	}
+	return values, nil
+}
+
+// MissingError is returned by Execute when required parameters have no
+// value.
+type MissingError struct {
+	Template   string
+	Parameters []Parameter
+}
+
+func (e *MissingError) Error() string {
+	names := make([]string, len(e.Parameters))
+	for i, p := range e.Parameters {
+		names[i] = p.Name
+		if p.Description != "" {
+			names[i] += " (" + p.Description + ")"
+		}
+	}
+	return fmt.Sprintf("template %s: missing required parameters: %s", e.Template, strings.Join(names, ", "))
+}
+
+// Values returns the value of every parameter of t: the value given in
+// values, or its default. It fails when values names a parameter t does
+// not have, when a value does not match its pattern, and with a
+// *MissingError when required parameters have no value.
+func (t *Template) Values(values map[string]string) (map[string]string, error) {
+	declared := make(map[string]bool, len(t.Parameters))
+	for _, p := range t.Parameters {
+		declared[p.Name] = true
+	}
+	var unknown []string
+	for name := range values {
+		if !declared[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("template %s has no parameter %s", t.Name, strings.Join(unknown, ", "))
+	}
+
+	var missing []Parameter
+	for _, p := range t.Parameters {
+		if p.Required && p.Default == "" && values[p.Name] == "" {
+			missing = append(missing, p)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, &MissingError{Template: t.Name, Parameters: missing}
+	}
+
+	resolved := make(map[string]string, len(t.Parameters))
+	for _, p := range t.Parameters {
+		value, ok := values[p.Name]
+		if !ok && p.Default != "" {
+			def, err := t.execute("default of "+p.Name, p.Default, resolved)
+			if err != nil {
+				return nil, err
+			}
+			value = def
+		}
+		if value == "" && p.Required {
+			return nil, &MissingError{Template: t.Name, Parameters: []Parameter{p}}
+		}
+		if value != "" && p.Pattern != "" && !regexp.MustCompile(p.Pattern).MatchString(value) {
+			return nil, fmt.Errorf("template %s: parameter %s: %q does not match the pattern %s", t.Name, p.Name, value, p.Pattern)
+		}
+		resolved[p.Name] = value
+	}
+	return resolved, nil
+}
+
+// Execute renders the feature definition of t with values.
+func (t *Template) Execute(values map[string]string) ([]byte, error) {
+	resolved, err := t.Values(values)
+	if err != nil {
+		return nil, err
+	}
+	body, err := t.parseBody()
+	if err != nil {
+		return nil, fmt.Errorf("template %s: %w", t.Name, err)
+	}
+	var buf bytes.Buffer
+	if err := body.Execute(&buf, resolved); err != nil {
+		return nil, fmt.Errorf("template %s: %w", t.Name, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Render renders the feature definition of t with values and decodes it.
+func (t *Template) Render(values map[string]string) (*api.CreateFeatureV2Request, error) {
+	data, err := t.Execute(values)
+	if err != nil {
+		return nil, err
+	}
+	var req api.CreateFeatureV2Request
+	if err := api.DecodeInput(data, "template "+t.Name, &req); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+func (t *Template) parseBody() (*template.Template, error) {
+	return template.New(t.Name).Funcs(funcs).Option("missingkey=error").Parse(t.Body)
+}
+
+func (t *Template) execute(name, text string, data map[string]string) (string, error) {
+	tmpl, err := template.New(name).Funcs(funcs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("template %s: %s: %w", t.Name, name, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("template %s: %w", t.Name, err)
+	}
+	return buf.String(), nil
+}
+
+// funcs are the functions available to templates.
+var funcs = template.FuncMap{
+	"quote": quote,
+	"title": title,
+	"snake": func(s string) string { return strings.Join(words(s), "_") },
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"sub":   sub,
+}
+
+// quote returns s as a double-quoted string, which is valid in YAML and
+// JSON.
+func quote(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
+
+// title turns a key such as checkout-v2 into a name such as Checkout V2.
+func title(s string) string {
+	w := words(s)
+	for i, word := range w {
+		w[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(w, " ")
+}
+
+func words(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return r == '-' || r == '_' || r == '.' || r == ' '
+	})
+}
+
+// sub returns a - b for numbers given as numbers or strings.
+func sub(a, b any) (string, error) {
+	x, err := number(a)
+	if err != nil {
+		return "", err
+	}
+	y, err := number(b)
+	if err != nil {
+		return "", err
+	}
+	diff := math.Round((x-y)*1e9) / 1e9
+	return strconv.FormatFloat(diff, 'f', -1, 64), nil
+}
+
+func number(v any) (float64, error) {
+	f, err := strconv.ParseFloat(strings.TrimSpace(fmt.Sprint(v)), 64)
+	if err != nil {
+		return 0, fmt.Errorf("not a number: %v", v)
+	}
+	return f, nil
+}
@@ -0,0 +1,147 @@
+package templates
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+func TestBuiltin(t *testing.T) {
+	list := Builtin()
+	var names []string
+	for _, tmpl := range list {
+		names = append(names, tmpl.Name)
+		if tmpl.Source != SourceBuiltin || tmpl.Description == "" {
+			t.Errorf("%s: unexpected template %+v", tmpl.Name, tmpl)
+		}
+		req, err := tmpl.Render(map[string]string{"key": "checkout-v2"})
+		if err != nil {
+			t.Errorf("%s: failed to render: %v", tmpl.Name, err)
+			continue
+		}
+		if err := api.ValidateFeatureRequest(req); err != nil {
+			t.Errorf("%s: rendered an invalid feature: %v", tmpl.Name, err)
+		}
+	}
+	if got := strings.Join(names, ","); got != "ab-test,kill-switch,percentage-release" {
+		t.Errorf("unexpected built-in templates %s", got)
+	}
+}
+
+func TestRender(t *testing.T) {
+	tmpl, err := Find(t.TempDir(), "ab-test")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req, err := tmpl.Render(map[string]string{"key": "checkout-v2", "variable": "checkout_v2_enabled", "split": "0.2"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.Key != "checkout-v2" || req.Name != "Checkout V2" || req.Type != "experiment" {
+		t.Errorf("unexpected feature: %+v", req)
+	}
+	if len(req.Variables) != 1 || req.Variables[0].Key != "checkout_v2_enabled" {
+		t.Errorf("unexpected variables: %+v", req.Variables)
+	}
+	if req.Variations[1].Variables["checkout_v2_enabled"] != true {
+		t.Errorf("unexpected variations: %+v", req.Variations)
+	}
+	dist := req.Configurations["production"].Targets[0].Distribution
+	if len(dist) != 2 || dist[0].Percentage != 0.8 || dist[1].Percentage != 0.2 {
+		t.Errorf("unexpected distribution: %+v", dist)
+	}
+
+	req, err = tmpl.Render(map[string]string{"key": "a", "name": `Say "hi": now`})
+	if err != nil {
+		t.Fatalf("expected values to be quoted, got %v", err)
+	}
+	if req.Name != `Say "hi": now` {
+		t.Errorf("unexpected name %q", req.Name)
+	}
+}
+
+func TestValues(t *testing.T) {
+	tmpl, _ := Find("", "percentage-release")
+
+	_, err := tmpl.Values(nil)
+	var missing *MissingError
+	if !errors.As(err, &missing) || len(missing.Parameters) != 1 || missing.Parameters[0].Name != "key" {
+		t.Errorf("expected a missing key error, got %v", err)
+	}
+
+	if _, err := tmpl.Values(map[string]string{"key": "a", "colour": "red"}); err == nil || !strings.Contains(err.Error(), "no parameter colour") {
+		t.Errorf("expected an unknown parameter error, got %v", err)
+	}
+	if _, err := tmpl.Values(map[string]string{"key": "Dark Mode"}); err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Errorf("expected a pattern error, got %v", err)
+	}
+	if _, err := tmpl.Values(map[string]string{"key": "a", "percentage": "50"}); err == nil {
+		t.Error("expected an error for a percentage above 1")
+	}
+
+	values, err := tmpl.Values(map[string]string{"key": "new-checkout"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if values["name"] != "New Checkout" || values["variable"] != "new-checkout" || values["percentage"] != "0.1" {
+		t.Errorf("unexpected defaults: %v", values)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "ab.yaml"), Skeleton("ab-test"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "team.yml"), Skeleton("team-flag"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a template"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := Load(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(list) != 4 {
+		t.Fatalf("expected 4 templates, got %d", len(list))
+	}
+	tmpl, err := Find(dir, "ab-test")
+	if err != nil || tmpl.Source != filepath.Join(dir, "ab.yaml") {
+		t.Errorf("expected the user template to replace the built-in one, got %+v, %v", tmpl, err)
+	}
+	req, err := tmpl.Render(map[string]string{"key": "x"})
+	if err != nil || req.Name != "X" || len(req.Variations) != 2 {
+		t.Errorf("unexpected skeleton rendering: %+v, %v", req, err)
+	}
+
+	if _, err := Find(dir, "nope"); err == nil || !strings.Contains(err.Error(), "team-flag") {
+		t.Errorf("expected an error listing the templates, got %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "broken.yaml"), []byte("name: broken\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "broken.yaml") {
+		t.Errorf("expected an error naming the broken template, got %v", err)
+	}
+}
+
+func TestParseValues(t *testing.T) {
+	values, err := ParseValues([]string{"variable=checkout_v2_enabled", "name=A=B", "description="})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if values["variable"] != "checkout_v2_enabled" || values["name"] != "A=B" || values["description"] != "" {
+		t.Errorf("unexpected values: %v", values)
+	}
+	if _, err := ParseValues([]string{"variable"}); err == nil {
+		t.Error("expected an error without =")
+	}
+}
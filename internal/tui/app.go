@@ -0,0 +1,101 @@
+// Package tui is a full-screen terminal UI for browsing projects and
+// features and changing their status per environment.
+package tui
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+)
+
+// Run shows m on t until the user quits or ctx is done. Keys are read from
+// the terminal, commands run in the background, and the current screen is
+// refreshed every interval when interval is positive.
+func Run(ctx context.Context, t *Terminal, m *Model, interval time.Duration) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	msgs := make(chan Msg, 16)
+	run := func(cmd Cmd) {
+		if cmd == nil {
+			return
+		}
+		go func() {
+			msg := cmd(ctx)
+			select {
+			case msgs <- msg:
+			case <-ctx.Done():
+			}
+		}()
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		buf := make([]byte, 256)
+		for {
+			n, err := t.in.Read(buf)
+			if err != nil {
+				errs <- err
+				return
+			}
+			for _, k := range ParseKeys(buf[:n]) {
+				select {
+				case msgs <- k:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	resize := make(chan os.Signal, 1)
+	notifyResize(resize)
+	defer signal.Stop(resize)
+
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	w := bufio.NewWriter(t.out)
+	m.Resize(t.Size())
+	run(m.Init())
+	for {
+		draw(w, m.View())
+		if err := w.Flush(); err != nil {
+			return err
+		}
+
+		var msg Msg
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-errs:
+			return err
+		case <-resize:
+			m.Resize(t.Size())
+			continue
+		case <-tick:
+			msg = tickMsg{}
+		case msg = <-msgs:
+		}
+
+		run(m.Update(msg))
+		if m.Done() {
+			return nil
+		}
+	}
+}
+
+// draw writes lines over the whole screen, clearing what remains of each
+// line.
+func draw(w *bufio.Writer, lines []string) {
+	w.WriteString("\x1b[H")
+	w.WriteString(strings.Join(lines, "\x1b[K\r\n"))
+	w.WriteString("\x1b[K\x1b[J")
+}
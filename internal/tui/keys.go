@@ -0,0 +1,108 @@
+package tui
+
+import "unicode/utf8"
+
+// KeyCode identifies a key that is not a printable character.
+type KeyCode int
+
+// Keys recognized by ParseKeys. KeyRune is a printable character.
+const (
+	KeyRune KeyCode = iota
+	KeyEnter
+	KeyEsc
+	KeyBackspace
+	KeyTab
+	KeyUp
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeyHome
+	KeyEnd
+	KeyPgUp
+	KeyPgDown
+	KeyCtrlC
+	KeyCtrlU
+)
+
+// Key is a key press.
+type Key struct {
+	Code KeyCode
+	// Rune is the character typed when Code is KeyRune.
+	Rune rune
+}
+
+// csiKeys maps the final byte of "ESC [ x" and "ESC O x" sequences.
+var csiKeys = map[byte]KeyCode{
+	'A': KeyUp,
+	'B': KeyDown,
+	'C': KeyRight,
+	'D': KeyLeft,
+	'H': KeyHome,
+	'F': KeyEnd,
+}
+
+// tildeKeys maps the number of "ESC [ n ~" sequences.
+var tildeKeys = map[string]KeyCode{
+	"1": KeyHome,
+	"7": KeyHome,
+	"4": KeyEnd,
+	"8": KeyEnd,
+	"5": KeyPgUp,
+	"6": KeyPgDown,
+}
+
+// ParseKeys decodes the keys in b, as read from a terminal in raw mode. An
+// escape at the end of b is the escape key; unknown sequences are dropped.
+func ParseKeys(b []byte) []Key {
+	var keys []Key
+	for len(b) > 0 {
+		switch c := b[0]; {
+		case c == 0x1b:
+			if len(b) == 1 || (b[1] != '[' && b[1] != 'O') {
+				keys = append(keys, Key{Code: KeyEsc})
+				b = b[1:]
+				continue
+			}
+			// Skip parameters up to the final byte of the sequence
+			i := 2
+			for i < len(b) && (b[i] < 0x40 || b[i] > 0x7e) {
+				i++
+			}
+			if i == len(b) {
+				return keys
+			}
+			if b[i] == '~' {
+				if code, ok := tildeKeys[string(b[2:i])]; ok {
+					keys = append(keys, Key{Code: code})
+				}
+			} else if code, ok := csiKeys[b[i]]; ok {
+				keys = append(keys, Key{Code: code})
+			}
+			b = b[i+1:]
+		case c == '\r' || c == '\n':
+			keys = append(keys, Key{Code: KeyEnter})
+			b = b[1:]
+		case c == 0x7f || c == 0x08:
+			keys = append(keys, Key{Code: KeyBackspace})
+			b = b[1:]
+		case c == '\t':
+			keys = append(keys, Key{Code: KeyTab})
+			b = b[1:]
+		case c == 0x03:
+			keys = append(keys, Key{Code: KeyCtrlC})
+			b = b[1:]
+		case c == 0x15:
+			keys = append(keys, Key{Code: KeyCtrlU})
+			b = b[1:]
+		case c < 0x20:
+			b = b[1:]
+		default:
+			r, size := utf8.DecodeRune(b)
+			if r != utf8.RuneError {
+				keys = append(keys, Key{Code: KeyRune, Rune: r})
+			}
+			b = b[size:]
+		}
+	}
+	return keys
+}
@@ -0,0 +1,30 @@
+package tui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []Key
+	}{
+		{"runes", "aé", []Key{{Code: KeyRune, Rune: 'a'}, {Code: KeyRune, Rune: 'é'}}},
+		{"arrows", "\x1b[A\x1b[B\x1bOC\x1b[D", []Key{{Code: KeyUp}, {Code: KeyDown}, {Code: KeyRight}, {Code: KeyLeft}}},
+		{"paging", "\x1b[5~\x1b[6~\x1b[1~\x1b[F", []Key{{Code: KeyPgUp}, {Code: KeyPgDown}, {Code: KeyHome}, {Code: KeyEnd}}},
+		{"escape", "\x1b", []Key{{Code: KeyEsc}}},
+		{"escape then rune", "\x1bq", []Key{{Code: KeyEsc}, {Code: KeyRune, Rune: 'q'}}},
+		{"controls", "\r\x7f\t\x03\x15\x01", []Key{{Code: KeyEnter}, {Code: KeyBackspace}, {Code: KeyTab}, {Code: KeyCtrlC}, {Code: KeyCtrlU}}},
+		{"unknown sequences", "\x1b[1;5A\x1b[3~x", []Key{{Code: KeyUp}, {Code: KeyRune, Rune: 'x'}}},
+		{"truncated sequence", "x\x1b[1", []Key{{Code: KeyRune, Rune: 'x'}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseKeys([]byte(tt.in)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseKeys(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
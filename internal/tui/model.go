@@ -0,0 +1,642 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/135yshr/devcycle-cli/internal/targeting"
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+// Source is the part of the API client used by the terminal UI.
+type Source interface {
+	Projects(ctx context.Context) ([]api.Project, error)
+	Features(ctx context.Context, projectKey string) ([]api.Feature, error)
+	Environments(ctx context.Context, projectKey string) ([]api.Environment, error)
+	FeatureV2(ctx context.Context, projectKey, featureKey string) (*api.FeatureV2, error)
+	Variations(ctx context.Context, projectKey, featureKey string) ([]api.Variation, error)
+	FeatureConfigurations(ctx context.Context, projectKey, featureKey string) (map[string]*api.EnvironmentConfig, error)
+	UpdateFeatureConfigurations(ctx context.Context, projectKey, featureKey string, req *api.UpdateFeatureConfigurationsRequest) (map[string]*api.EnvironmentConfig, error)
+	EnableFeature(ctx context.Context, projectKey, featureKey, environmentKey string) error
+	DisableFeature(ctx context.Context, projectKey, featureKey, environmentKey string) error
+	FeatureAuditLogs(ctx context.Context, projectKey, featureKey string) ([]api.AuditLog, error)
+}
+
+// requestTimeout bounds each API call made by a command.
+const requestTimeout = 30 * time.Second
+
+// Msg is an event handled by Model.Update: a Key, or the result of a Cmd.
+type Msg any
+
+// Cmd runs outside of the model, usually calling the API, and returns the
+// message that carries its result.
+type Cmd func(ctx context.Context) Msg
+
+type screen int
+
+const (
+	screenProjects screen = iota
+	screenFeatures
+	screenDetail
+	screenAudit
+)
+
+type tickMsg struct{}
+
+type projectsLoaded struct {
+	projects []api.Project
+	err      error
+}
+
+type featuresLoaded struct {
+	project      string
+	features     []api.Feature
+	environments []api.Environment
+	err          error
+}
+
+type detailLoaded struct {
+	project    string
+	feature    *api.FeatureV2
+	variations []api.Variation
+	err        error
+}
+
+type auditLoaded struct {
+	feature string
+	logs    []api.AuditLog
+	err     error
+}
+
+type actionDone struct {
+	message string
+	err     error
+}
+
+// dialog asks to confirm an action.
+type dialog struct {
+	question string
+	action   Cmd
+}
+
+// input edits a single line of text.
+type input struct {
+	label  string
+	value  string
+	err    string
+	submit func(value string) error
+}
+
+// Model is the state of the terminal UI. Keys and the results of commands
+// are given to Update, and View renders the state.
+type Model struct {
+	src    Source
+	screen screen
+	width  int
+	height int
+
+	projects      []api.Project
+	projectCursor int
+	project       string
+
+	features      []api.Feature
+	environments  []api.Environment
+	query         string
+	searching     bool
+	featureCursor int
+
+	featureKey string
+	feature    *api.FeatureV2
+	variations []api.Variation
+	envCursor  int
+
+	audit       []api.AuditLog
+	auditOffset int
+
+	dialog *dialog
+	input  *input
+
+	loading bool
+	message string
+	err     error
+	updated time.Time
+	done    bool
+	now     func() time.Time
+}
+
+// New returns a model that starts with the features of project, or with
+// the list of projects when project is empty.
+func New(src Source, project string) *Model {
+	m := &Model{src: src, width: 80, height: 24, now: time.Now}
+	if project != "" {
+		m.project = project
+		m.screen = screenFeatures
+	}
+	return m
+}
+
+// Init returns the command that loads the first screen.
+func (m *Model) Init() Cmd {
+	return m.reload()
+}
+
+// Done reports whether the user asked to quit.
+func (m *Model) Done() bool {
+	return m.done
+}
+
+// Resize sets the size of the screen.
+func (m *Model) Resize(width, height int) {
+	m.width, m.height = width, height
+}
+
+// reload returns the command that loads the data of the current screen.
+func (m *Model) reload() Cmd {
+	m.loading = true
+	switch m.screen {
+	case screenProjects:
+		return m.loadProjects()
+	case screenFeatures:
+		return m.loadFeatures()
+	case screenDetail:
+		return m.loadDetail()
+	default:
+		return m.loadAudit()
+	}
+}
+
+func (m *Model) loadProjects() Cmd {
+	return func(ctx context.Context) Msg {
+		ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+		defer cancel()
+		projects, err := m.src.Projects(ctx)
+		return projectsLoaded{projects: projects, err: err}
+	}
+}
+
+func (m *Model) loadFeatures() Cmd {
+	project := m.project
+	return func(ctx context.Context) Msg {
+		ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+		defer cancel()
+		features, err := m.src.Features(ctx, project)
+		if err != nil {
+			return featuresLoaded{project: project, err: err}
+		}
+		environments, err := m.src.Environments(ctx, project)
+		return featuresLoaded{project: project, features: features, environments: environments, err: err}
+	}
+}
+
+func (m *Model) loadDetail() Cmd {
+	project, key := m.project, m.featureKey
+	return func(ctx context.Context) Msg {
+		ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+		defer cancel()
+		feature, err := m.src.FeatureV2(ctx, project, key)
+		if err != nil {
+			return detailLoaded{project: project, err: err}
+		}
+		variations, err := m.src.Variations(ctx, project, key)
+		return detailLoaded{project: project, feature: feature, variations: variations, err: err}
+	}
+}
+
+func (m *Model) loadAudit() Cmd {
+	project, key := m.project, m.featureKey
+	return func(ctx context.Context) Msg {
+		ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+		defer cancel()
+		logs, err := m.src.FeatureAuditLogs(ctx, project, key)
+		return auditLoaded{feature: key, logs: logs, err: err}
+	}
+}
+
+// Update applies msg to the model and returns the command to run next, if
+// any.
+func (m *Model) Update(msg Msg) Cmd {
+	switch msg := msg.(type) {
+	case Key:
+		return m.handleKey(msg)
+	case tickMsg:
+		// Refresh in the background unless a load is already running
+		if m.loading || m.dialog != nil || m.input != nil {
+			return nil
+		}
+		return m.reload()
+	case projectsLoaded:
+		m.loaded(msg.err)
+		if msg.err == nil {
+			m.projects = msg.projects
+			m.projectCursor = clamp(m.projectCursor, len(m.projects))
+		}
+	case featuresLoaded:
+		if msg.project != m.project {
+			return nil
+		}
+		m.loaded(msg.err)
+		if msg.err == nil {
+			key := m.selectedFeatureKey()
+			m.features, m.environments = msg.features, msg.environments
+			m.featureCursor = m.indexOfFeature(key)
+		}
+	case detailLoaded:
+		if msg.project != m.project || (msg.feature != nil && msg.feature.Key != m.featureKey) {
+			return nil
+		}
+		m.loaded(msg.err)
+		if msg.err == nil {
+			m.feature, m.variations = msg.feature, msg.variations
+		}
+	case auditLoaded:
+		if msg.feature != m.featureKey {
+			return nil
+		}
+		m.loaded(msg.err)
+		if msg.err == nil {
+			m.audit = msg.logs
+			slices.SortStableFunc(m.audit, func(a, b api.AuditLog) int { return b.CreatedAt.Compare(a.CreatedAt) })
+		}
+	case actionDone:
+		m.loading = false
+		m.err = msg.err
+		if msg.err == nil {
+			m.message = msg.message
+			return m.reload()
+		}
+	}
+	return nil
+}
+
+// loaded records the end of a load.
+func (m *Model) loaded(err error) {
+	m.loading = false
+	m.err = err
+	if err == nil {
+		m.updated = m.now()
+	}
+}
+
+func (m *Model) handleKey(k Key) Cmd {
+	if k.Code == KeyCtrlC {
+		m.done = true
+		return nil
+	}
+	if m.dialog != nil {
+		return m.handleDialogKey(k)
+	}
+	if m.input != nil {
+		m.handleInputKey(k)
+		return nil
+	}
+	if m.searching {
+		m.handleSearchKey(k)
+		return nil
+	}
+
+	m.message = ""
+	if k.Code == KeyRune {
+		switch k.Rune {
+		case 'q':
+			m.done = true
+			return nil
+		case 'r':
+			return m.reload()
+		}
+	}
+
+	switch m.screen {
+	case screenProjects:
+		return m.handleProjectsKey(k)
+	case screenFeatures:
+		return m.handleFeaturesKey(k)
+	case screenDetail:
+		return m.handleDetailKey(k)
+	default:
+		return m.handleAuditKey(k)
+	}
+}
+
+func (m *Model) handleDialogKey(k Key) Cmd {
+	switch {
+	case k.Code == KeyRune && (k.Rune == 'y' || k.Rune == 'Y'):
+		action := m.dialog.action
+		m.dialog = nil
+		m.loading = true
+		return action
+	case k.Code == KeyEsc || k.Code == KeyRune && (k.Rune == 'n' || k.Rune == 'N'):
+		m.dialog = nil
+		m.message = "Cancelled"
+	}
+	return nil
+}
+
+func (m *Model) handleInputKey(k Key) {
+	in := m.input
+	switch k.Code {
+	case KeyRune:
+		in.value += string(k.Rune)
+	case KeyBackspace:
+		if r := []rune(in.value); len(r) > 0 {
+			in.value = string(r[:len(r)-1])
+		}
+	case KeyCtrlU:
+		in.value = ""
+	case KeyEsc:
+		m.input = nil
+		m.message = "Cancelled"
+	case KeyEnter:
+		if err := in.submit(strings.TrimSpace(in.value)); err != nil {
+			in.err = err.Error()
+			return
+		}
+		if m.input == in {
+			m.input = nil
+		}
+	}
+}
+
+func (m *Model) handleSearchKey(k Key) {
+	switch k.Code {
+	case KeyRune:
+		m.query += string(k.Rune)
+	case KeyBackspace:
+		if r := []rune(m.query); len(r) > 0 {
+			m.query = string(r[:len(r)-1])
+		}
+	case KeyCtrlU:
+		m.query = ""
+	case KeyEsc:
+		m.query = ""
+		m.searching = false
+	case KeyEnter, KeyUp, KeyDown:
+		m.searching = false
+	}
+	m.featureCursor = 0
+}
+
+// move returns cursor moved by the navigation key k within n items, and
+// whether k is a navigation key.
+func (m *Model) move(k Key, cursor, n int) (int, bool) {
+	page := max(m.height-6, 1)
+	switch {
+	case k.Code == KeyUp || k.Code == KeyRune && k.Rune == 'k':
+		cursor--
+	case k.Code == KeyDown || k.Code == KeyRune && k.Rune == 'j':
+		cursor++
+	case k.Code == KeyPgUp:
+		cursor -= page
+	case k.Code == KeyPgDown:
+		cursor += page
+	case k.Code == KeyHome || k.Code == KeyRune && k.Rune == 'g':
+		cursor = 0
+	case k.Code == KeyEnd || k.Code == KeyRune && k.Rune == 'G':
+		cursor = n - 1
+	default:
+		return cursor, false
+	}
+	return clamp(cursor, n), true
+}
+
+func (m *Model) handleProjectsKey(k Key) Cmd {
+	if c, ok := m.move(k, m.projectCursor, len(m.projects)); ok {
+		m.projectCursor = c
+		return nil
+	}
+	if k.Code == KeyEnter && len(m.projects) > 0 {
+		key := m.projects[m.projectCursor].Key
+		if key != m.project {
+			m.project = key
+			m.features, m.environments = nil, nil
+			m.query, m.featureCursor = "", 0
+		}
+		m.screen = screenFeatures
+		return m.reload()
+	}
+	return nil
+}
+
+func (m *Model) handleFeaturesKey(k Key) Cmd {
+	visible := m.visibleFeatures()
+	if c, ok := m.move(k, m.featureCursor, len(visible)); ok {
+		m.featureCursor = c
+		return nil
+	}
+	switch {
+	case k.Code == KeyRune && k.Rune == '/':
+		m.searching = true
+	case k.Code == KeyEnter && len(visible) > 0:
+		key := visible[m.featureCursor].Key
+		if key != m.featureKey {
+			m.featureKey = key
+			m.feature, m.variations = nil, nil
+			m.envCursor = 0
+		}
+		m.screen = screenDetail
+		return m.reload()
+	case k.Code == KeyEsc && m.query != "":
+		m.query, m.featureCursor = "", 0
+	case k.Code == KeyEsc || k.Code == KeyLeft:
+		m.screen = screenProjects
+		if m.projects == nil {
+			return m.reload()
+		}
+		m.projectCursor = max(slices.IndexFunc(m.projects, func(p api.Project) bool { return p.Key == m.project }), 0)
+	}
+	return nil
+}
+
+func (m *Model) handleDetailKey(k Key) Cmd {
+	if c, ok := m.move(k, m.envCursor, len(m.environments)); ok {
+		m.envCursor = c
+		return nil
+	}
+	switch {
+	case k.Code == KeyEsc || k.Code == KeyLeft:
+		m.screen = screenFeatures
+		return m.reload()
+	case k.Code == KeyRune && k.Rune == 'a':
+		m.screen = screenAudit
+		m.audit, m.auditOffset = nil, 0
+		return m.reload()
+	case k.Code == KeyRune && (k.Rune == ' ' || k.Rune == 't'):
+		m.confirmToggle()
+	case k.Code == KeyRune && k.Rune == 'd':
+		m.editDefault()
+	}
+	return nil
+}
+
+func (m *Model) handleAuditKey(k Key) Cmd {
+	if c, ok := m.move(k, m.auditOffset, len(m.audit)); ok {
+		m.auditOffset = c
+		return nil
+	}
+	if k.Code == KeyEsc || k.Code == KeyLeft {
+		m.screen = screenDetail
+		return m.reload()
+	}
+	return nil
+}
+
+// selectedEnvironment returns the environment under the cursor of the
+// detail screen and its configuration, which may be nil.
+func (m *Model) selectedEnvironment() (*api.Environment, *api.EnvironmentConfig) {
+	if m.feature == nil || len(m.environments) == 0 {
+		return nil, nil
+	}
+	env := &m.environments[m.envCursor]
+	return env, m.feature.Configurations[env.Key]
+}
+
+// confirmToggle asks to enable or disable the feature in the selected
+// environment.
+func (m *Model) confirmToggle() {
+	env, cfg := m.selectedEnvironment()
+	if env == nil {
+		return
+	}
+	project, feature, envKey := m.project, m.featureKey, env.Key
+	enable := cfg == nil || cfg.Status != "active"
+	verb := "Disable"
+	if enable {
+		verb = "Enable"
+	}
+	m.dialog = &dialog{
+		question: fmt.Sprintf("%s '%s' in %s?", verb, feature, envKey),
+		action: func(ctx context.Context) Msg {
+			ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+			defer cancel()
+			var err error
+			if enable {
+				err = m.src.EnableFeature(ctx, project, feature, envKey)
+			} else {
+				err = m.src.DisableFeature(ctx, project, feature, envKey)
+			}
+			return actionDone{message: fmt.Sprintf("Feature '%s' %sd in %s", feature, strings.ToLower(verb), envKey), err: err}
+		},
+	}
+}
+
+// editDefault asks for the distribution served to users who match no other
+// rule in the selected environment, then for confirmation.
+func (m *Model) editDefault() {
+	env, cfg := m.selectedEnvironment()
+	if env == nil {
+		return
+	}
+	keys := make([]string, len(m.variations))
+	for i, v := range m.variations {
+		keys[i] = v.Key
+	}
+	value := ""
+	if cfg != nil {
+		for i := len(cfg.Targets) - 1; i >= 0; i-- {
+			if targeting.IsCatchAll(cfg.Targets[i]) {
+				value = targeting.FormatServe(m.variationKeys(cfg.Targets[i].Distribution))
+				break
+			}
+		}
+	}
+
+	project, feature, envKey, variations := m.project, m.featureKey, env.Key, m.variations
+	m.input = &input{
+		label: fmt.Sprintf("Default in %s (%s)", envKey, strings.Join(keys, ", ")),
+		value: value,
+		submit: func(serve string) error {
+			dist, err := targeting.ParseServe(serve)
+			if err != nil {
+				return err
+			}
+			for _, d := range dist {
+				if !slices.Contains(keys, d.Variation) {
+					return fmt.Errorf("unknown variation %q", d.Variation)
+				}
+			}
+			m.dialog = &dialog{
+				question: fmt.Sprintf("Serve %s by default in %s?", targeting.FormatDistribution(dist), envKey),
+				action: func(ctx context.Context) Msg {
+					err := m.setDefault(ctx, project, feature, envKey, serve, variations)
+					return actionDone{message: fmt.Sprintf("Default rule updated in %s", envKey), err: err}
+				},
+			}
+			return nil
+		},
+	}
+}
+
+// setDefault updates the default distribution of the feature in envKey
+// from its current configuration.
+func (m *Model) setDefault(ctx context.Context, project, feature, envKey, serve string, variations []api.Variation) error {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	configs, err := m.src.FeatureConfigurations(ctx, project, feature)
+	if err != nil {
+		return err
+	}
+	cfg := configs[envKey]
+	if cfg == nil {
+		cfg = &api.EnvironmentConfig{Status: "inactive"}
+	}
+	dist, err := targeting.ResolveServe(serve, cfg, variations)
+	if err != nil {
+		return err
+	}
+	targeting.SetDefault(cfg, dist)
+	_, err = m.src.UpdateFeatureConfigurations(ctx, project, feature, &api.UpdateFeatureConfigurationsRequest{
+		Configurations: map[string]*api.EnvironmentConfig{envKey: cfg},
+	})
+	return err
+}
+
+// variationKeys returns dist with variation IDs replaced by keys.
+func (m *Model) variationKeys(dist []api.Distribution) []api.Distribution {
+	out := make([]api.Distribution, len(dist))
+	for i, d := range dist {
+		out[i] = d
+		for _, v := range m.variations {
+			if v.ID != "" && v.ID == d.Variation {
+				out[i].Variation = v.Key
+			}
+		}
+	}
+	return out
+}
+
+// visibleFeatures returns the features that match the search query.
+func (m *Model) visibleFeatures() []api.Feature {
+	if m.query == "" {
+		return m.features
+	}
+	q := strings.ToLower(m.query)
+	var out []api.Feature
+	for _, f := range m.features {
+		if strings.Contains(strings.ToLower(f.Key), q) || strings.Contains(strings.ToLower(f.Name), q) ||
+			slices.ContainsFunc(f.Tags, func(t string) bool { return strings.Contains(strings.ToLower(t), q) }) {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+func (m *Model) selectedFeatureKey() string {
+	visible := m.visibleFeatures()
+	if m.featureCursor < len(visible) {
+		return visible[m.featureCursor].Key
+	}
+	return ""
+}
+
+// indexOfFeature returns the position of key among the visible features,
+// or 0, so that the cursor stays on the same feature after a refresh.
+func (m *Model) indexOfFeature(key string) int {
+	return max(slices.IndexFunc(m.visibleFeatures(), func(f api.Feature) bool { return f.Key == key }), 0)
+}
+
+// clamp limits cursor to the positions of n items.
+func clamp(cursor, n int) int {
+	return max(min(cursor, n-1), 0)
+}
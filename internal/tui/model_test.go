@@ -0,0 +1,266 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+type fakeSource struct {
+	features []api.Feature
+	configs  map[string]*api.EnvironmentConfig
+	calls    []string
+	updated  *api.UpdateFeatureConfigurationsRequest
+}
+
+func newFakeSource() *fakeSource {
+	return &fakeSource{
+		features: []api.Feature{
+			{Key: "dark-mode", Name: "Dark Mode", Type: "release", Tags: []string{"ui"}},
+			{Key: "new-checkout", Name: "New Checkout", Type: "experiment"},
+			{Key: "search-v2", Name: "Search", Type: "release", Tags: []string{"search"}},
+		},
+		configs: map[string]*api.EnvironmentConfig{
+			"development": {Status: "active", Targets: []api.Target{{
+				Name:         "All Users",
+				Audience:     api.Audience{Filters: api.Filters{Operator: "and", Filters: []api.Filter{{Type: "all"}}}},
+				Distribution: []api.Distribution{{Variation: "id-on", Percentage: 1}},
+			}}},
+			"production": {Status: "inactive"},
+		},
+	}
+}
+
+func (s *fakeSource) Projects(ctx context.Context) ([]api.Project, error) {
+	return []api.Project{{Key: "web", Name: "Web"}, {Key: "mobile", Name: "Mobile"}}, nil
+}
+
+func (s *fakeSource) Features(ctx context.Context, projectKey string) ([]api.Feature, error) {
+	if projectKey == "mobile" {
+		return nil, nil
+	}
+	return s.features, nil
+}
+
+func (s *fakeSource) Environments(ctx context.Context, projectKey string) ([]api.Environment, error) {
+	return []api.Environment{{Key: "development"}, {Key: "production"}}, nil
+}
+
+func (s *fakeSource) FeatureV2(ctx context.Context, projectKey, featureKey string) (*api.FeatureV2, error) {
+	return &api.FeatureV2{
+		Key:  featureKey,
+		Name: "Dark Mode",
+		Type: "release",
+		Variables: []api.VariableDefinition{
+			{Key: "dark-mode", Type: "Boolean"},
+		},
+		Variations: []api.VariationDefinition{
+			{Key: "on", Name: "On", Variables: map[string]any{"dark-mode": true}},
+			{Key: "off", Name: "Off", Variables: map[string]any{"dark-mode": false}},
+		},
+		Configurations: s.configs,
+	}, nil
+}
+
+func (s *fakeSource) Variations(ctx context.Context, projectKey, featureKey string) ([]api.Variation, error) {
+	return []api.Variation{{ID: "id-on", Key: "on"}, {ID: "id-off", Key: "off"}}, nil
+}
+
+func (s *fakeSource) FeatureConfigurations(ctx context.Context, projectKey, featureKey string) (map[string]*api.EnvironmentConfig, error) {
+	return s.configs, nil
+}
+
+func (s *fakeSource) UpdateFeatureConfigurations(ctx context.Context, projectKey, featureKey string, req *api.UpdateFeatureConfigurationsRequest) (map[string]*api.EnvironmentConfig, error) {
+	s.updated = req
+	return req.Configurations, nil
+}
+
+func (s *fakeSource) EnableFeature(ctx context.Context, projectKey, featureKey, environmentKey string) error {
+	s.calls = append(s.calls, "enable "+projectKey+"/"+featureKey+"/"+environmentKey)
+	return nil
+}
+
+func (s *fakeSource) DisableFeature(ctx context.Context, projectKey, featureKey, environmentKey string) error {
+	s.calls = append(s.calls, "disable "+projectKey+"/"+featureKey+"/"+environmentKey)
+	return nil
+}
+
+func (s *fakeSource) FeatureAuditLogs(ctx context.Context, projectKey, featureKey string) ([]api.AuditLog, error) {
+	return []api.AuditLog{
+		{Type: "featureUpdated", User: api.AuditUser{Name: "Ann"}, CreatedAt: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{Type: "featureCreated", User: api.AuditUser{Email: "bob@example.com"}, CreatedAt: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{Type: "featureEnabled", User: api.AuditUser{Name: "Cy"}, CreatedAt: time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)},
+	}, nil
+}
+
+// run runs cmd and the commands that follow from its result.
+func run(m *Model, cmd Cmd) {
+	for cmd != nil {
+		cmd = m.Update(cmd(context.Background()))
+	}
+}
+
+// press sends the keys typed as s, running the commands they start.
+func press(m *Model, s string) {
+	for _, k := range ParseKeys([]byte(s)) {
+		run(m, m.Update(k))
+	}
+}
+
+func screenText(m *Model) string {
+	return strings.Join(m.View(), "\n")
+}
+
+func TestModelNavigation(t *testing.T) {
+	m := New(newFakeSource(), "")
+	m.Resize(100, 20)
+	run(m, m.Init())
+
+	if text := screenText(m); !strings.Contains(text, "dvcx ui › projects") || !strings.Contains(text, "mobile") {
+		t.Fatalf("expected the projects, got\n%s", text)
+	}
+
+	press(m, "\r")
+	if m.project != "web" || len(m.features) != 3 {
+		t.Fatalf("expected the features of web, got %q %v", m.project, m.features)
+	}
+
+	press(m, "/ui")
+	if got := m.visibleFeatures(); len(got) != 1 || got[0].Key != "dark-mode" {
+		t.Errorf("expected the search to match tags, got %v", got)
+	}
+	if text := screenText(m); !strings.Contains(text, "/ui█") || strings.Contains(text, "new-checkout") {
+		t.Errorf("expected the filtered list, got\n%s", text)
+	}
+	press(m, "\x1b\x1b[B\r")
+	if m.screen != screenDetail || m.featureKey != "new-checkout" {
+		t.Fatalf("expected the detail of new-checkout, got %v %q", m.screen, m.featureKey)
+	}
+
+	text := screenText(m)
+	for _, want := range []string{"development  on      1      on 100%", "production   off     0      -", "on  On  dark-mode=true", "dark-mode  Boolean"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in\n%s", want, text)
+		}
+	}
+
+	press(m, "a")
+	text = screenText(m)
+	if m.screen != screenAudit || strings.Index(text, "featureEnabled") > strings.Index(text, "featureCreated") {
+		t.Errorf("expected audit entries newest first, got\n%s", text)
+	}
+	if !strings.Contains(text, "bob@example.com") {
+		t.Errorf("expected the email of users without a name, got\n%s", text)
+	}
+
+	press(m, "\x1b\x1b\x1b")
+	if m.screen != screenProjects || m.projectCursor != 0 {
+		t.Errorf("expected to be back on the projects, got %v", m.screen)
+	}
+	press(m, "q")
+	if !m.Done() {
+		t.Error("expected q to quit")
+	}
+}
+
+func TestModelToggle(t *testing.T) {
+	src := newFakeSource()
+	m := New(src, "web")
+	run(m, m.Init())
+	press(m, "\r")
+
+	press(m, "j ")
+	if m.dialog == nil || m.dialog.question != "Enable 'dark-mode' in production?" {
+		t.Fatalf("expected a confirmation, got %+v", m.dialog)
+	}
+	press(m, "n")
+	if len(src.calls) != 0 || m.message != "Cancelled" {
+		t.Errorf("expected no change, got %v", src.calls)
+	}
+
+	press(m, "k y")
+	if !reflect.DeepEqual(src.calls, []string{"disable web/dark-mode/development"}) {
+		t.Errorf("unexpected calls %v", src.calls)
+	}
+	if m.message != "Feature 'dark-mode' disabled in development" {
+		t.Errorf("unexpected message %q", m.message)
+	}
+}
+
+func TestModelEditDefault(t *testing.T) {
+	src := newFakeSource()
+	m := New(src, "web")
+	run(m, m.Init())
+	press(m, "\rd")
+
+	if m.input == nil || m.input.value != "on" {
+		t.Fatalf("expected the current default, got %+v", m.input)
+	}
+	press(m, "\x15on:50,beta:50\r")
+	if m.input == nil || m.input.err != `unknown variation "beta"` {
+		t.Fatalf("expected an unknown variation error, got %+v", m.input)
+	}
+	press(m, "\x7f\x7f\x7f\x7f\x7f\x7f\x7foff:50\r")
+	if m.input != nil || m.dialog == nil || m.dialog.question != "Serve on 50%, off 50% by default in development?" {
+		t.Fatalf("expected a confirmation, got %+v %+v", m.input, m.dialog)
+	}
+	press(m, "y")
+
+	if src.updated == nil {
+		t.Fatal("expected the configuration to be updated")
+	}
+	dist := src.updated.Configurations["development"].Targets[0].Distribution
+	want := []api.Distribution{{Variation: "id-on", Percentage: 0.5}, {Variation: "id-off", Percentage: 0.5}}
+	if !reflect.DeepEqual(dist, want) {
+		t.Errorf("expected %v, got %v", want, dist)
+	}
+}
+
+func TestModelRefresh(t *testing.T) {
+	src := newFakeSource()
+	m := New(src, "web")
+	run(m, m.Init())
+	press(m, "jj")
+
+	// The cursor follows its feature when the list changes
+	src.features = append([]api.Feature{{Key: "a-new-flag"}}, src.features...)
+	run(m, m.Update(tickMsg{}))
+	if got := m.selectedFeatureKey(); got != "search-v2" {
+		t.Errorf("expected the cursor to stay on search-v2, got %s", got)
+	}
+
+	// Results for another project are dropped
+	m.Update(featuresLoaded{project: "mobile"})
+	if len(m.features) != 4 {
+		t.Errorf("expected a stale result to be ignored, got %v", m.features)
+	}
+
+	m.Update(featuresLoaded{project: "web", err: errors.New("boom")})
+	if text := screenText(m); !strings.Contains(text, "Error: boom") || !strings.Contains(text, "search-v2") {
+		t.Errorf("expected the error and the previous features, got\n%s", text)
+	}
+}
+
+func TestViewFitsScreen(t *testing.T) {
+	m := New(newFakeSource(), "web")
+	m.Resize(30, 6)
+	run(m, m.Init())
+	lines := m.View()
+	if len(lines) != 6 {
+		t.Fatalf("expected 6 lines, got %d", len(lines))
+	}
+	for _, line := range lines {
+		plain := line
+		for _, code := range []string{styleReset, styleBold, styleDim, styleReverse, styleRed, styleGreen} {
+			plain = strings.ReplaceAll(plain, code, "")
+		}
+		if n := len([]rune(plain)); n != 30 {
+			t.Errorf("expected 30 columns, got %d in %q", n, plain)
+		}
+	}
+}
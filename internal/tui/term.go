@@ -0,0 +1,45 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ErrUnsupported is returned by Open on platforms without terminal support.
+var ErrUnsupported = errors.New("the terminal UI is not supported on this platform")
+
+// Terminal is a terminal switched to raw mode and to its alternate screen.
+type Terminal struct {
+	in      *os.File
+	out     io.Writer
+	restore func() error
+}
+
+// Open switches the terminal of in to raw mode, so that keys are read as
+// they are pressed, and out to the alternate screen. Close restores both.
+func Open(in, out *os.File) (*Terminal, error) {
+	restore, err := makeRaw(int(in.Fd()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up the terminal: %w", err)
+	}
+	t := &Terminal{in: in, out: out, restore: restore}
+	fmt.Fprint(out, "\x1b[?1049h\x1b[?25l")
+	return t, nil
+}
+
+// Size returns the width and height of the terminal.
+func (t *Terminal) Size() (int, int) {
+	w, h, err := windowSize(int(t.in.Fd()))
+	if err != nil || w <= 0 || h <= 0 {
+		return 80, 24
+	}
+	return w, h
+}
+
+// Close leaves the alternate screen and restores the terminal mode.
+func (t *Terminal) Close() error {
+	fmt.Fprint(t.out, "\x1b[?25h\x1b[?1049l")
+	return t.restore()
+}
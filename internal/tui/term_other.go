@@ -0,0 +1,15 @@
+//go:build !linux && !darwin && !dragonfly && !freebsd && !netbsd && !openbsd
+
+package tui
+
+import "os"
+
+func makeRaw(fd int) (func() error, error) {
+	return nil, ErrUnsupported
+}
+
+func windowSize(fd int) (int, int, error) {
+	return 0, 0, ErrUnsupported
+}
+
+func notifyResize(c chan<- os.Signal) {}
@@ -0,0 +1,314 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/135yshr/devcycle-cli/internal/targeting"
+)
+
+const (
+	styleReset   = "\x1b[0m"
+	styleBold    = "\x1b[1m"
+	styleDim     = "\x1b[2m"
+	styleReverse = "\x1b[7m"
+	styleRed     = "\x1b[31m"
+	styleGreen   = "\x1b[32m"
+)
+
+// row is a line of the body, styled as a whole once fitted to the width.
+type row struct {
+	text  string
+	style string
+}
+
+// View renders the model as one line per row of the screen.
+func (m *Model) View() []string {
+	bodyHeight := max(m.height-3, 0)
+	var body []row
+	switch m.screen {
+	case screenProjects:
+		body = m.viewProjects(bodyHeight)
+	case screenFeatures:
+		body = m.viewFeatures(bodyHeight)
+	case screenDetail:
+		body = m.viewDetail(bodyHeight)
+	default:
+		body = m.viewAudit(bodyHeight)
+	}
+
+	lines := make([]string, 0, m.height)
+	lines = append(lines, style(m.titleBar(), styleReverse))
+	for i := range bodyHeight {
+		r := row{}
+		if i < len(body) {
+			r = body[i]
+		}
+		lines = append(lines, style(fit(" "+r.text, m.width), r.style))
+	}
+	lines = append(lines, m.statusLine(), style(fit(" "+m.help(), m.width), styleDim))
+	return lines[:min(len(lines), m.height)]
+}
+
+func (m *Model) titleBar() string {
+	crumbs := []string{"dvcx ui"}
+	switch m.screen {
+	case screenProjects:
+		crumbs = append(crumbs, "projects")
+	case screenFeatures:
+		crumbs = append(crumbs, m.project)
+	case screenDetail:
+		crumbs = append(crumbs, m.project, m.featureKey)
+	case screenAudit:
+		crumbs = append(crumbs, m.project, m.featureKey, "audit")
+	}
+	left := " " + strings.Join(crumbs, " › ")
+
+	right := ""
+	if m.loading {
+		right = "loading… "
+	} else if !m.updated.IsZero() {
+		right = "updated " + m.updated.Format("15:04:05") + " "
+	}
+	gap := m.width - utf8.RuneCountInString(left) - utf8.RuneCountInString(right)
+	if gap < 1 {
+		return fit(left, m.width)
+	}
+	return left + strings.Repeat(" ", gap) + right
+}
+
+func (m *Model) statusLine() string {
+	switch {
+	case m.dialog != nil:
+		return style(fit(" "+m.dialog.question+" [y/N]", m.width), styleBold)
+	case m.input != nil:
+		line := " " + m.input.label + ": " + m.input.value + "█"
+		if m.input.err != "" {
+			return fit(line, m.width-utf8.RuneCountInString(m.input.err)-2) + "  " + style(m.input.err, styleRed)
+		}
+		return fit(line, m.width)
+	case m.searching || m.query != "" && m.screen == screenFeatures:
+		cursor := ""
+		if m.searching {
+			cursor = "█"
+		}
+		return fit(" /"+m.query+cursor, m.width)
+	case m.err != nil:
+		return style(fit(" Error: "+m.err.Error(), m.width), styleRed)
+	case m.message != "":
+		return style(fit(" "+m.message, m.width), styleGreen)
+	}
+	return fit("", m.width)
+}
+
+func (m *Model) help() string {
+	switch {
+	case m.dialog != nil:
+		return "y confirm · n cancel"
+	case m.input != nil:
+		return "enter apply · esc cancel · ctrl-u clear"
+	case m.searching:
+		return "type to search · enter done · esc clear"
+	}
+	switch m.screen {
+	case screenProjects:
+		return "↑↓ move · enter open · r refresh · q quit"
+	case screenFeatures:
+		return "↑↓ move · enter open · / search · esc projects · r refresh · q quit"
+	case screenDetail:
+		return "↑↓ environment · space enable/disable · d default · a audit · esc back · r refresh · q quit"
+	}
+	return "↑↓ scroll · esc back · r refresh · q quit"
+}
+
+func (m *Model) viewProjects(height int) []row {
+	if len(m.projects) == 0 {
+		return []row{{text: m.placeholder("No projects")}}
+	}
+	rows := make([][]string, len(m.projects))
+	for i, p := range m.projects {
+		rows[i] = []string{p.Key, p.Name, p.Description}
+	}
+	return list([]string{"KEY", "NAME", "DESCRIPTION"}, rows, m.projectCursor, height)
+}
+
+func (m *Model) viewFeatures(height int) []row {
+	visible := m.visibleFeatures()
+	if len(visible) == 0 {
+		if m.query != "" && len(m.features) > 0 {
+			return []row{{text: fmt.Sprintf("No features match %q", m.query)}}
+		}
+		return []row{{text: m.placeholder("No features")}}
+	}
+	rows := make([][]string, len(visible))
+	for i, f := range visible {
+		rows[i] = []string{f.Key, f.Name, f.Type, f.Status, strings.Join(f.Tags, ",")}
+	}
+	return list([]string{"KEY", "NAME", "TYPE", "STATUS", "TAGS"}, rows, m.featureCursor, height)
+}
+
+func (m *Model) viewDetail(height int) []row {
+	f := m.feature
+	if f == nil {
+		return []row{{text: m.placeholder("Feature not found")}}
+	}
+	out := []row{{text: fmt.Sprintf("%s (%s)", f.Name, f.Key), style: styleBold}}
+	summary := []string{f.Type}
+	if f.Status != "" {
+		summary = append(summary, f.Status)
+	}
+	if f.Description != "" {
+		summary = append(summary, f.Description)
+	}
+	out = append(out, row{text: strings.Join(summary, " · ")}, row{})
+
+	var envRows [][]string
+	for _, env := range m.environments {
+		cfg := f.Configurations[env.Key]
+		status, rules, serve := "off", "0", "-"
+		if cfg != nil {
+			if cfg.Status == "active" {
+				status = "on"
+			}
+			rules = strconv.Itoa(len(cfg.Targets))
+			for i := len(cfg.Targets) - 1; i >= 0; i-- {
+				if targeting.IsCatchAll(cfg.Targets[i]) {
+					serve = targeting.FormatDistribution(m.variationKeys(cfg.Targets[i].Distribution))
+					break
+				}
+			}
+		}
+		envRows = append(envRows, []string{env.Key, status, rules, serve})
+	}
+	out = append(out, list([]string{"ENVIRONMENT", "STATUS", "RULES", "DEFAULT"}, envRows, m.envCursor, len(envRows)+1)...)
+
+	out = append(out, row{}, row{text: "VARIATIONS", style: styleBold})
+	for _, v := range f.Variations {
+		out = append(out, row{text: "  " + v.Key + "  " + v.Name + "  " + formatValues(v.Variables)})
+	}
+	out = append(out, row{}, row{text: "VARIABLES", style: styleBold})
+	for _, v := range f.Variables {
+		out = append(out, row{text: "  " + v.Key + "  " + v.Type})
+	}
+	return out[:min(len(out), height)]
+}
+
+func (m *Model) viewAudit(height int) []row {
+	if len(m.audit) == 0 {
+		return []row{{text: m.placeholder("No audit entries")}}
+	}
+	rows := make([][]string, len(m.audit))
+	for i, log := range m.audit {
+		user := log.User.Name
+		if user == "" {
+			user = log.User.Email
+		}
+		var changes []string
+		for _, c := range log.Changes {
+			changes = append(changes, c.Type)
+		}
+		rows[i] = []string{log.CreatedAt.Local().Format("2006-01-02 15:04"), user, log.Type, strings.Join(changes, ", ")}
+	}
+	header, lines := columns([]string{"TIME", "USER", "TYPE", "CHANGES"}, rows)
+	out := []row{{text: header, style: styleBold}}
+	end := min(m.auditOffset+height-1, len(lines))
+	for _, line := range lines[m.auditOffset:end] {
+		out = append(out, row{text: line})
+	}
+	return out
+}
+
+// placeholder is shown instead of an empty list.
+func (m *Model) placeholder(empty string) string {
+	if m.loading {
+		return "Loading…"
+	}
+	if m.err != nil {
+		return ""
+	}
+	return empty
+}
+
+// list renders rows as a table with a header, scrolled to keep the row at
+// cursor visible and highlighted, in at most height lines.
+func list(headers []string, rows [][]string, cursor, height int) []row {
+	header, lines := columns(headers, rows)
+	out := []row{{text: header, style: styleBold}}
+	n := max(height-1, 1)
+	start := min(max(cursor-n+1, 0), max(len(lines)-n, 0))
+	for i := start; i < min(start+n, len(lines)); i++ {
+		r := row{text: lines[i]}
+		if i == cursor {
+			r.style = styleReverse
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// columns aligns the cells of headers and rows in columns.
+func columns(headers []string, rows [][]string) (string, []string) {
+	widths := make([]int, len(headers))
+	for i, h := range headers {
+		widths[i] = utf8.RuneCountInString(h)
+	}
+	for _, r := range rows {
+		for i, cell := range r {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+	format := func(cells []string) string {
+		var b strings.Builder
+		for i, cell := range cells {
+			if i == len(cells)-1 {
+				b.WriteString(cell)
+				break
+			}
+			b.WriteString(fit(cell, widths[i]+2))
+		}
+		return strings.TrimRight(b.String(), " ")
+	}
+	lines := make([]string, len(rows))
+	for i, r := range rows {
+		lines[i] = format(r)
+	}
+	return format(headers), lines
+}
+
+// formatValues renders variable values as "key=value" pairs sorted by key.
+func formatValues(values map[string]any) string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		b, _ := json.Marshal(values[k])
+		parts[i] = k + "=" + string(b)
+	}
+	return strings.Join(parts, " ")
+}
+
+// fit truncates or pads s to exactly width characters.
+func fit(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	n := utf8.RuneCountInString(s)
+	if n > width {
+		return string([]rune(s)[:width-1]) + "…"
+	}
+	return s + strings.Repeat(" ", width-n)
+}
+
+func style(s, code string) string {
+	if code == "" {
+		return s
+	}
+	return code + s + styleReset
+}
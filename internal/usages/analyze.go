@@ -0,0 +1,49 @@
+package usages
+
+import "sort"
+
+// Key statuses reported by Analyze.
+const (
+	StatusOK      = "ok"
+	StatusMissing = "missing"
+	StatusUnused  = "unused"
+)
+
+// KeyReport describes one variable key: whether it is both defined and
+// referenced, and where it is referenced.
+type KeyReport struct {
+	Key       string  `json:"key"`
+	Status    string  `json:"status"`
+	Locations []Usage `json:"locations,omitempty"`
+}
+
+// Analyze cross-references usages with the keys defined in the project.
+// Keys referenced in code but not defined are reported as missing; keys
+// defined but never referenced are reported as unused. The result is
+// sorted by key.
+func Analyze(usages []Usage, defined []string) []KeyReport {
+	byKey := make(map[string][]Usage)
+	for _, u := range usages {
+		byKey[u.Key] = append(byKey[u.Key], u)
+	}
+	isDefined := make(map[string]bool, len(defined))
+	for _, key := range defined {
+		isDefined[key] = true
+	}
+
+	var reports []KeyReport
+	for key, locs := range byKey {
+		status := StatusOK
+		if !isDefined[key] {
+			status = StatusMissing
+		}
+		reports = append(reports, KeyReport{Key: key, Status: status, Locations: locs})
+	}
+	for key := range isDefined {
+		if _, ok := byKey[key]; !ok {
+			reports = append(reports, KeyReport{Key: key, Status: StatusUnused})
+		}
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].Key < reports[j].Key })
+	return reports
+}
@@ -0,0 +1,94 @@
+package usages
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Annotation severities.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityNotice  = "notice"
+)
+
+// Annotation is a message attached to a line of a file, for display in a
+// pull or merge request.
+type Annotation struct {
+	Severity string
+	Check    string
+	File     string
+	Line     int
+	Message  string
+}
+
+// WriteGitHubAnnotations writes annotations as GitHub Actions workflow
+// commands, one per line.
+func WriteGitHubAnnotations(w io.Writer, annotations []Annotation) error {
+	for _, a := range annotations {
+		params := fmt.Sprintf("file=%s,line=%d", escapeGitHubProperty(a.File), a.Line)
+		if a.Check != "" {
+			params += ",title=" + escapeGitHubProperty(a.Check)
+		}
+		if _, err := fmt.Fprintf(w, "::%s %s::%s\n", a.Severity, params, escapeGitHubData(a.Message)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func escapeGitHubData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+func escapeGitHubProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+// gitLabIssue is an entry of a GitLab Code Quality report.
+type gitLabIssue struct {
+	Description string         `json:"description"`
+	CheckName   string         `json:"check_name"`
+	Fingerprint string         `json:"fingerprint"`
+	Severity    string         `json:"severity"`
+	Location    gitLabLocation `json:"location"`
+}
+
+type gitLabLocation struct {
+	Path  string      `json:"path"`
+	Lines gitLabLines `json:"lines"`
+}
+
+type gitLabLines struct {
+	Begin int `json:"begin"`
+}
+
+var gitLabSeverities = map[string]string{
+	SeverityError:   "major",
+	SeverityWarning: "minor",
+	SeverityNotice:  "info",
+}
+
+// WriteGitLabReport writes annotations as a GitLab Code Quality report,
+// which merge requests show inline when it is uploaded as a codequality
+// artifact.
+func WriteGitLabReport(w io.Writer, annotations []Annotation) error {
+	issues := make([]gitLabIssue, len(annotations))
+	for i, a := range annotations {
+		sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%s\x00%d\x00%s", a.Check, a.File, a.Line, a.Message)))
+		issues[i] = gitLabIssue{
+			Description: a.Message,
+			CheckName:   a.Check,
+			Fingerprint: hex.EncodeToString(sum[:16]),
+			Severity:    gitLabSeverities[a.Severity],
+			Location:    gitLabLocation{Path: a.File, Lines: gitLabLines{Begin: a.Line}},
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(issues)
+}
@@ -0,0 +1,209 @@
+package usages
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Changes between the two sides of a diff.
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+)
+
+// KeyChange is a variable key that is referenced on only one side of a
+// diff, with the locations where it was added or removed.
+type KeyChange struct {
+	Key       string  `json:"key"`
+	Change    string  `json:"change"`
+	Locations []Usage `json:"locations"`
+}
+
+// Compare returns the keys referenced in after but not in before (added)
+// and those referenced in before but not in after (removed), sorted by key.
+// Keys referenced on both sides, such as calls that were only moved, are
+// not reported.
+func Compare(before, after []Usage) []KeyChange {
+	beforeKeys := groupByKey(before)
+	afterKeys := groupByKey(after)
+
+	var changes []KeyChange
+	for key, locs := range afterKeys {
+		if _, ok := beforeKeys[key]; !ok {
+			changes = append(changes, KeyChange{Key: key, Change: ChangeAdded, Locations: locs})
+		}
+	}
+	for key, locs := range beforeKeys {
+		if _, ok := afterKeys[key]; !ok {
+			changes = append(changes, KeyChange{Key: key, Change: ChangeRemoved, Locations: locs})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes
+}
+
+func groupByKey(usages []Usage) map[string][]Usage {
+	m := make(map[string][]Usage)
+	for _, u := range usages {
+		m[u.Key] = append(m[u.Key], u)
+	}
+	return m
+}
+
+// ScanDiff returns the usages on removed and added lines of a unified diff,
+// such as the output of git diff. Consecutive changed lines are scanned
+// together, so calls that span several lines are found. Removed usages are
+// located in the old file and added usages in the new file. Generated files
+// and files that Scan would skip are ignored.
+func (s *Scanner) ScanDiff(r io.Reader) (removed, added []Usage, err error) {
+	var (
+		oldFile, newFile string
+		oldLine, newLine int
+		oldLeft, newLeft int
+		block            strings.Builder
+		blockKind        byte
+		blockStart       int
+	)
+	flush := func() {
+		if blockKind == 0 {
+			return
+		}
+		file, dst := newFile, &added
+		if blockKind == '-' {
+			file, dst = oldFile, &removed
+		}
+		if file != "" && !s.skipped(file) {
+			for _, u := range s.ScanContent(file, []byte(block.String())) {
+				u.Line += blockStart - 1
+				*dst = append(*dst, u)
+			}
+		}
+		block.Reset()
+		blockKind = 0
+	}
+	appendLine := func(kind byte, text string, line int) {
+		if blockKind != kind {
+			flush()
+			blockKind = kind
+			blockStart = line
+		}
+		block.WriteString(text)
+		block.WriteByte('\n')
+	}
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), maxFileSize)
+	for sc.Scan() {
+		line := sc.Text()
+		if oldLeft == 0 && newLeft == 0 {
+			flush()
+			switch {
+			case strings.HasPrefix(line, "diff "):
+				oldFile, newFile = "", ""
+			case strings.HasPrefix(line, "--- "):
+				oldFile = diffPath(line[4:])
+			case strings.HasPrefix(line, "+++ "):
+				newFile = diffPath(line[4:])
+			case strings.HasPrefix(line, "@@"):
+				h, ok := parseHunkHeader(line)
+				if !ok {
+					return nil, nil, fmt.Errorf("invalid hunk header %q", line)
+				}
+				oldLine, oldLeft, newLine, newLeft = h[0], h[1], h[2], h[3]
+			}
+			continue
+		}
+		switch {
+		case strings.HasPrefix(line, "+"):
+			appendLine('+', line[1:], newLine)
+			newLine++
+			newLeft--
+		case strings.HasPrefix(line, "-"):
+			appendLine('-', line[1:], oldLine)
+			oldLine++
+			oldLeft--
+		case strings.HasPrefix(line, `\`):
+			// "\ No newline at end of file"
+		default:
+			// Context line; an empty line is a context line whose leading
+			// space was stripped.
+			flush()
+			oldLine++
+			newLine++
+			oldLeft--
+			newLeft--
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read diff: %w", err)
+	}
+	flush()
+	return removed, added, nil
+}
+
+// skipped reports whether a file named in a diff would not have been
+// scanned by Scan: it is generated, excluded, or inside a skipped or
+// excluded directory.
+func (s *Scanner) skipped(file string) bool {
+	file = filepath.Clean(file)
+	if s.generated[file] {
+		return true
+	}
+	for dir := filepath.Dir(file); dir != "." && dir != string(filepath.Separator); dir = filepath.Dir(dir) {
+		if skipDirs[filepath.Base(dir)] || s.excluded(dir) {
+			return true
+		}
+	}
+	return s.excluded(file)
+}
+
+// diffPath returns the path from a ---/+++ line, or "" for /dev/null.
+func diffPath(s string) string {
+	if i := strings.IndexByte(s, '\t'); i >= 0 {
+		s = s[:i]
+	}
+	if unq, err := strconv.Unquote(s); err == nil {
+		s = unq
+	}
+	if s == "/dev/null" {
+		return ""
+	}
+	if strings.HasPrefix(s, "a/") || strings.HasPrefix(s, "b/") {
+		s = s[2:]
+	}
+	return s
+}
+
+// parseHunkHeader returns the old start line, old line count, new start
+// line and new line count of a hunk header such as "@@ -12,3 +14,5 @@".
+func parseHunkHeader(line string) (h [4]int, ok bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 3 || !strings.HasPrefix(fields[1], "-") || !strings.HasPrefix(fields[2], "+") {
+		return h, false
+	}
+	for i, field := range fields[1:3] {
+		start, count, hasCount := strings.Cut(field[1:], ",")
+		n, err := strconv.Atoi(start)
+		if err != nil {
+			return h, false
+		}
+		c := 1
+		if hasCount {
+			if c, err = strconv.Atoi(count); err != nil {
+				return h, false
+			}
+		}
+		// An empty range ("-0,0") starts at line 0, but there is nothing
+		// to locate in it.
+		if n == 0 {
+			n = 1
+		}
+		h[2*i], h[2*i+1] = n, c
+	}
+	return h, true
+}
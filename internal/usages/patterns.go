@@ -0,0 +1,136 @@
+// Package usages finds references to DevCycle variable keys in source code.
+//
+// Files are matched against per-language regular expressions for SDK calls
+// such as variableValue(user, "key", default). Files written by
+// dvcx generate are recognized, and references to their constants and
+// accessors are reported as usages of the underlying keys.
+package usages
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Languages maps language names to the file extensions they cover.
+var Languages = map[string][]string{
+	"go":         {".go"},
+	"typescript": {".ts", ".tsx", ".js", ".jsx", ".mjs", ".cjs"},
+	"python":     {".py"},
+	"java":       {".java"},
+	"kotlin":     {".kt", ".kts"},
+	"swift":      {".swift"},
+}
+
+// languageAliases maps alternative names accepted in patterns.
+var languageAliases = map[string]string{
+	"ts":         "typescript",
+	"js":         "typescript",
+	"javascript": "typescript",
+	"py":         "python",
+	"kt":         "kotlin",
+}
+
+// arg matches a single call argument: anything up to the next comma,
+// including simple nested calls.
+const arg = `(?:[^,()'"` + "`" + `]|\([^()]*\))+`
+
+// defaultPatterns are the built-in SDK call patterns. The first capture
+// group of each is the variable key.
+var defaultPatterns = map[string][]string{
+	"go": {
+		`\.Variable(?:Value)?\(\s*` + arg + `,\s*"([^"]+)"`,
+	},
+	"typescript": {
+		`\b(?:variable|variableValue|useVariable|useVariableValue)\(\s*(?:` + arg + `,\s*)?['"` + "`" + `]([^'"` + "`" + `]+)['"` + "`" + `]`,
+	},
+	"python": {
+		`\.variable(?:_value)?\(\s*` + arg + `,\s*['"]([^'"]+)['"]`,
+	},
+	"java": {
+		`\.variable(?:Value)?\(\s*(?:` + arg + `,\s*)?"([^"]+)"`,
+	},
+	"kotlin": {
+		`\.variable(?:Value)?\(\s*(?:` + arg + `,\s*)?"([^"]+)"`,
+	},
+	"swift": {
+		`\.variable(?:Value)?\(\s*key:\s*"([^"]+)"`,
+	},
+}
+
+// Pattern is a regular expression that finds variable keys in files of one
+// language.
+type Pattern struct {
+	Lang   string
+	Regexp *regexp.Regexp
+}
+
+// NewPattern compiles expr for lang. The expression must have at least one
+// capture group; the first one is the variable key.
+func NewPattern(lang, expr string) (Pattern, error) {
+	name, err := LanguageName(lang)
+	if err != nil {
+		return Pattern{}, err
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return Pattern{}, fmt.Errorf("invalid pattern for %s: %w", name, err)
+	}
+	if re.NumSubexp() < 1 {
+		return Pattern{}, fmt.Errorf("pattern %q for %s must have a capture group for the key", expr, name)
+	}
+	return Pattern{Lang: name, Regexp: re}, nil
+}
+
+// ParsePattern parses a "lang=regexp" pattern as given on the command line.
+func ParsePattern(s string) (Pattern, error) {
+	lang, expr, ok := strings.Cut(s, "=")
+	if !ok || expr == "" {
+		return Pattern{}, fmt.Errorf("invalid pattern %q (expected lang=regexp)", s)
+	}
+	return NewPattern(strings.TrimSpace(lang), expr)
+}
+
+// DefaultPatterns returns the built-in patterns for every language.
+func DefaultPatterns() []Pattern {
+	langs := make([]string, 0, len(defaultPatterns))
+	for lang := range defaultPatterns {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+
+	var patterns []Pattern
+	for _, lang := range langs {
+		for _, expr := range defaultPatterns[lang] {
+			patterns = append(patterns, Pattern{Lang: lang, Regexp: regexp.MustCompile(expr)})
+		}
+	}
+	return patterns
+}
+
+// LanguageName returns the canonical name of lang.
+func LanguageName(lang string) (string, error) {
+	name := strings.ToLower(lang)
+	if alias, ok := languageAliases[name]; ok {
+		name = alias
+	}
+	if _, ok := Languages[name]; !ok {
+		return "", fmt.Errorf("unknown language %q", lang)
+	}
+	return name, nil
+}
+
+// languageOf returns the language of a file from its extension, or "".
+func languageOf(path string) string {
+	ext := strings.ToLower(filepath.Ext(path))
+	for lang, exts := range Languages {
+		for _, e := range exts {
+			if e == ext {
+				return lang
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,356 @@
+package usages
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/135yshr/devcycle-cli/internal/codegen"
+)
+
+// maxFileSize is the size above which files are skipped. Larger files are
+// almost always bundled or minified output.
+const maxFileSize = 1 << 20
+
+// skipDirs are directories that are never scanned.
+var skipDirs = map[string]bool{".git": true, "node_modules": true, "vendor": true}
+
+// Usage is a reference to a variable key in a source file.
+type Usage struct {
+	Key    string `json:"key"`
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+	Lang   string `json:"lang"`
+}
+
+// Location returns the position of u as file:line.
+func (u Usage) Location() string {
+	return fmt.Sprintf("%s:%d", u.File, u.Line)
+}
+
+// Scanner finds variable keys in source files.
+type Scanner struct {
+	patterns  map[string][]*regexp.Regexp
+	exclude   []string
+	aliases   map[string]*aliasSet
+	generated map[string]bool
+}
+
+// NewScanner returns a scanner that uses patterns and skips paths matching
+// any of the exclude globs. Globs are matched against both the base name
+// and the slash-separated path.
+func NewScanner(patterns []Pattern, exclude []string) *Scanner {
+	s := &Scanner{
+		patterns:  make(map[string][]*regexp.Regexp),
+		exclude:   exclude,
+		aliases:   make(map[string]*aliasSet),
+		generated: make(map[string]bool),
+	}
+	for _, p := range patterns {
+		s.patterns[p.Lang] = append(s.patterns[p.Lang], p.Regexp)
+	}
+	return s
+}
+
+// Files returns the source files under paths, sorted. Paths may be files or
+// directories; only files of a known language are returned.
+func (s *Scanner) Files(paths []string) ([]string, error) {
+	var files []string
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if path != root && (skipDirs[d.Name()] || s.excluded(path)) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !d.Type().IsRegular() || languageOf(path) == "" || s.excluded(path) {
+				return nil
+			}
+			files = append(files, path)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func (s *Scanner) excluded(path string) bool {
+	slash := filepath.ToSlash(filepath.Clean(path))
+	for _, pattern := range s.exclude {
+		if ok, _ := filepath.Match(pattern, filepath.Base(path)); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(pattern, slash); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Scan returns the usages in the source files under paths. Files written by
+// dvcx generate are read first, so that references to their constants and
+// accessors are found in every other file.
+func (s *Scanner) Scan(paths []string) ([]Usage, error) {
+	files, err := s.Files(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	type source struct {
+		name    string
+		content []byte
+	}
+	var sources []source
+	for _, name := range files {
+		content, err := readSource(name)
+		if err != nil {
+			return nil, err
+		}
+		if content == nil || s.AddGenerated(name, content) {
+			continue
+		}
+		sources = append(sources, source{name, content})
+	}
+
+	var usages []Usage
+	for _, src := range sources {
+		usages = append(usages, s.ScanContent(src.name, src.content)...)
+	}
+	return usages, nil
+}
+
+// LoadGenerated records the files written by dvcx generate under paths
+// without scanning anything else. It is used before ScanDiff, whose input
+// usually does not contain the generated files themselves.
+func (s *Scanner) LoadGenerated(paths []string) error {
+	files, err := s.Files(paths)
+	if err != nil {
+		return err
+	}
+	for _, name := range files {
+		content, err := readSource(name)
+		if err != nil {
+			return err
+		}
+		s.AddGenerated(name, content)
+	}
+	return nil
+}
+
+// readSource returns the content of a source file, or nil if the file is
+// too large to scan.
+func readSource(name string) ([]byte, error) {
+	info, err := os.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > maxFileSize {
+		return nil, nil
+	}
+	content, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return content, nil
+}
+
+// ScanContent returns the usages in content, which is the content of the
+// file name. The language is taken from the file extension.
+func (s *Scanner) ScanContent(name string, content []byte) []Usage {
+	lang := languageOf(name)
+	if lang == "" {
+		return nil
+	}
+	lines := newLineIndex(content)
+	var usages []Usage
+	add := func(key string, offset int) {
+		line, col := lines.position(offset)
+		usages = append(usages, Usage{Key: key, File: name, Line: line, Column: col, Lang: lang})
+	}
+
+	for _, re := range s.patterns[lang] {
+		for _, m := range re.FindAllSubmatchIndex(content, -1) {
+			if m[2] < 0 {
+				continue
+			}
+			add(string(content[m[2]:m[3]]), m[2])
+		}
+	}
+	if a := s.aliases[lang]; a != nil {
+		a.find(content, add)
+	}
+
+	sort.SliceStable(usages, func(i, j int) bool {
+		if usages[i].Line != usages[j].Line {
+			return usages[i].Line < usages[j].Line
+		}
+		return usages[i].Column < usages[j].Column
+	})
+	return usages
+}
+
+// AddGenerated records the constants and accessors declared in content if
+// it is a file written by dvcx generate, and reports whether it was.
+func (s *Scanner) AddGenerated(name string, content []byte) bool {
+	if !bytes.HasPrefix(content, []byte("// "+codegen.Header)) {
+		return false
+	}
+	lang := languageOf(name)
+	var consts, funcs map[string]string
+	switch lang {
+	case "go":
+		consts, funcs = goAliases(content)
+	case "typescript":
+		consts, funcs = tsAliases(content)
+	default:
+		return false
+	}
+	s.generated[filepath.Clean(name)] = true
+	a := s.aliases[lang]
+	if a == nil {
+		a = &aliasSet{lang: lang, consts: map[string]string{}, funcs: map[string]string{}}
+		s.aliases[lang] = a
+	}
+	for id, key := range consts {
+		a.consts[id] = key
+	}
+	for id, key := range funcs {
+		a.funcs[id] = key
+	}
+	a.compile()
+	return true
+}
+
+var (
+	goConstRe = regexp.MustCompile(`(?m)^\t(\w+)Key = ("(?:[^"\\]|\\.)*")$`)
+	goFuncRe  = regexp.MustCompile(`(?m)^func (\w+)\[U any\]\(c Client\[U\]`)
+	tsConstRe = regexp.MustCompile(`(?m)^  (\w+): ('(?:[^'\\]|\\.)*'),$`)
+	tsFuncRe  = regexp.MustCompile(`(?m)^export function (\w+)<U>\([^)]*\)[^{]*\{\n\s*return client\.variableValue\(user, VariableKeys\.(\w+),`)
+)
+
+// goAliases returns the key constants (XKey) and accessor functions (X) of
+// a generated Go file, keyed by identifier.
+func goAliases(content []byte) (consts, funcs map[string]string) {
+	consts = make(map[string]string)
+	funcs = make(map[string]string)
+	ids := make(map[string]string)
+	for _, m := range goConstRe.FindAllSubmatch(content, -1) {
+		key, err := strconv.Unquote(string(m[2]))
+		if err != nil {
+			continue
+		}
+		ids[string(m[1])] = key
+		consts[string(m[1])+"Key"] = key
+	}
+	for _, m := range goFuncRe.FindAllSubmatch(content, -1) {
+		if key, ok := ids[string(m[1])]; ok {
+			funcs[string(m[1])] = key
+		}
+	}
+	return consts, funcs
+}
+
+// tsAliases returns the VariableKeys members and accessor functions of a
+// generated TypeScript file, keyed by identifier.
+func tsAliases(content []byte) (consts, funcs map[string]string) {
+	consts = make(map[string]string)
+	funcs = make(map[string]string)
+	for _, m := range tsConstRe.FindAllSubmatch(content, -1) {
+		lit := string(m[2])
+		key := strings.NewReplacer(`\'`, `'`, `\\`, `\`).Replace(lit[1 : len(lit)-1])
+		consts[string(m[1])] = key
+	}
+	for _, m := range tsFuncRe.FindAllSubmatch(content, -1) {
+		if key, ok := consts[string(m[2])]; ok {
+			funcs[string(m[1])] = key
+		}
+	}
+	return consts, funcs
+}
+
+// aliasSet holds the identifiers declared by generated files of one
+// language.
+type aliasSet struct {
+	lang    string
+	consts  map[string]string
+	funcs   map[string]string
+	constRe *regexp.Regexp
+	funcRe  *regexp.Regexp
+}
+
+func (a *aliasSet) compile() {
+	a.constRe, a.funcRe = nil, nil
+	if len(a.consts) > 0 {
+		prefix := `\b`
+		if a.lang == "typescript" {
+			prefix = `\bVariableKeys\.`
+		}
+		a.constRe = regexp.MustCompile(prefix + `(` + alternation(a.consts) + `)\b`)
+	}
+	if len(a.funcs) > 0 {
+		// Go accessors may be instantiated explicitly (X[T](...)) and
+		// TypeScript ones may be given a type argument (x<T>(...)).
+		a.funcRe = regexp.MustCompile(`\b(` + alternation(a.funcs) + `)\s*(?:\(|\[|<[^>]*>\s*\()`)
+	}
+}
+
+func (a *aliasSet) find(content []byte, add func(key string, offset int)) {
+	if a.constRe != nil {
+		for _, m := range a.constRe.FindAllSubmatchIndex(content, -1) {
+			add(a.consts[string(content[m[2]:m[3]])], m[0])
+		}
+	}
+	if a.funcRe != nil {
+		for _, m := range a.funcRe.FindAllSubmatchIndex(content, -1) {
+			add(a.funcs[string(content[m[2]:m[3]])], m[0])
+		}
+	}
+}
+
+// alternation returns a regular expression alternation of the keys of ids,
+// longest first so that no identifier shadows a longer one.
+func alternation(ids map[string]string) string {
+	names := make([]string, 0, len(ids))
+	for id := range ids {
+		names = append(names, regexp.QuoteMeta(id))
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if len(names[i]) != len(names[j]) {
+			return len(names[i]) > len(names[j])
+		}
+		return names[i] < names[j]
+	})
+	return strings.Join(names, "|")
+}
+
+// lineIndex converts byte offsets to 1-based line and column numbers.
+type lineIndex []int
+
+func newLineIndex(content []byte) lineIndex {
+	starts := lineIndex{0}
+	for i, c := range content {
+		if c == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return starts
+}
+
+func (l lineIndex) position(offset int) (line, col int) {
+	i := sort.Search(len(l), func(i int) bool { return l[i] > offset }) - 1
+	return i + 1, offset - l[i] + 1
+}
@@ -0,0 +1,301 @@
+package usages
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/135yshr/devcycle-cli/internal/codegen"
+)
+
+func keysOf(usages []Usage) []string {
+	var keys []string
+	for _, u := range usages {
+		keys = append(keys, u.Key)
+	}
+	return keys
+}
+
+func TestScanContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name: "main.go",
+			content: `package main
+
+func run() {
+	on, _ := client.Variable(user, "new-checkout", false)
+	theme, _ := client.VariableValue(getUser(ctx), "theme", "light")
+	other("not-a-flag")
+}
+`,
+			want: []string{"new-checkout", "theme"},
+		},
+		{
+			name: "app.tsx",
+			content: `const enabled = useVariableValue('dark-mode', false)
+const v = devcycle.variable(user, "banner", "")
+const w = client.variableValue(user, ` + "`limits`" + `, {})
+`,
+			want: []string{"dark-mode", "banner", "limits"},
+		},
+		{
+			name:    "app.py",
+			content: "enabled = client.variable_value(user, 'py-flag', False)\n",
+			want:    []string{"py-flag"},
+		},
+		{
+			name:    "App.java",
+			content: "Boolean on = client.variableValue(user, \"java-flag\", false);\n",
+			want:    []string{"java-flag"},
+		},
+		{
+			name:    "App.kt",
+			content: "val on = client.variable(\"kotlin-flag\", false)\n",
+			want:    []string{"kotlin-flag"},
+		},
+		{
+			name:    "App.swift",
+			content: "let on = client.variableValue(key: \"swift-flag\", defaultValue: false)\n",
+			want:    []string{"swift-flag"},
+		},
+		{
+			name:    "README.md",
+			content: "client.variableValue(user, \"ignored\", false)\n",
+		},
+	}
+
+	s := NewScanner(DefaultPatterns(), nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := keysOf(s.ScanContent(tt.name, []byte(tt.content)))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestScanContentPosition(t *testing.T) {
+	s := NewScanner(DefaultPatterns(), nil)
+	usages := s.ScanContent("main.go", []byte("package main\n\nvar x, _ = c.Variable(u, \"k\", 1)\n"))
+	if len(usages) != 1 {
+		t.Fatalf("expected 1 usage, got %d", len(usages))
+	}
+	u := usages[0]
+	if u.Line != 3 || u.Column != 27 || u.Location() != "main.go:3" {
+		t.Errorf("unexpected position: %+v", u)
+	}
+}
+
+func TestCustomPattern(t *testing.T) {
+	p, err := ParsePattern(`go=flags\.Get\("([^"]+)"\)`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s := NewScanner([]Pattern{p}, nil)
+	got := keysOf(s.ScanContent("x.go", []byte(`flags.Get("custom")`)))
+	if !reflect.DeepEqual(got, []string{"custom"}) {
+		t.Errorf("unexpected keys: %v", got)
+	}
+
+	for _, bad := range []string{"go", "cobol=x(y)", `go=no-group`, `go=(`} {
+		if _, err := ParsePattern(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestGeneratedAliases(t *testing.T) {
+	vars := []codegen.Variable{
+		{Key: "dark-mode", Type: "Boolean"},
+		{Key: "dark-mode-extra", Type: "Boolean"},
+		{Key: "it's", Type: "String"},
+	}
+	goSrc, err := codegen.Go("flags", vars)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tsSrc, err := codegen.TypeScript(vars)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	s := NewScanner(DefaultPatterns(), nil)
+	if !s.AddGenerated("flags/variables.go", goSrc) || !s.AddGenerated("src/variables.ts", tsSrc) {
+		t.Fatal("expected generated files to be recognized")
+	}
+	if s.AddGenerated("main.go", []byte("package main\n")) {
+		t.Error("expected ordinary file not to be recognized")
+	}
+
+	goUse := `package main
+
+var a = flags.DarkMode(client, user, false)
+var b = flags.DarkModeExtraKey
+var c = flags.ItS[string](client, user, "")
+var d = DarkModeLabel
+`
+	if got, want := keysOf(s.ScanContent("main.go", []byte(goUse))), []string{"dark-mode", "dark-mode-extra", "it's"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("go: expected %v, got %v", want, got)
+	}
+
+	tsUse := `import { darkMode, VariableKeys } from './variables'
+darkMode(client, user, false)
+client.variableValue(user, VariableKeys.DarkModeExtra, false)
+`
+	if got, want := keysOf(s.ScanContent("app.ts", []byte(tsUse))), []string{"dark-mode", "dark-mode-extra"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ts: expected %v, got %v", want, got)
+	}
+}
+
+func TestScan(t *testing.T) {
+	dir := t.TempDir()
+	write := func(rel, content string) {
+		path := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gen, err := codegen.Go("flags", []codegen.Variable{{Key: "generated", Type: "Boolean"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	write("flags/variables.go", string(gen))
+	write("main.go", "package main\nvar _ = flags.Generated(c, u, false)\nvar _, _ = c.Variable(u, \"direct\", 1)\n")
+	write("node_modules/pkg/index.js", "variableValue(user, 'dependency', 1)\n")
+	write("testdata/fixture.go", "c.Variable(u, \"fixture\", 1)\n")
+	write("web/app_test.ts", "variableValue(user, 'test-only', 1)\n")
+
+	s := NewScanner(DefaultPatterns(), []string{"testdata", "*_test.ts"})
+	usages, err := s.Scan([]string{dir})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := keysOf(usages), []string{"generated", "direct"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	usages := []Usage{
+		{Key: "b", File: "a.go", Line: 1},
+		{Key: "missing", File: "a.go", Line: 2},
+		{Key: "b", File: "b.go", Line: 3},
+	}
+	reports := Analyze(usages, []string{"b", "unused"})
+
+	var got [][2]string
+	for _, r := range reports {
+		got = append(got, [2]string{r.Key, r.Status})
+	}
+	want := [][2]string{{"b", StatusOK}, {"missing", StatusMissing}, {"unused", StatusUnused}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if len(reports[0].Locations) != 2 {
+		t.Errorf("expected 2 locations for b, got %d", len(reports[0].Locations))
+	}
+}
+
+const testDiff = `diff --git a/api/cart.go b/api/cart.go
+index 1111111..2222222 100644
+--- a/api/cart.go
++++ b/api/cart.go
+@@ -10,4 +10,5 @@ func cart() {
+ 	a, _ := c.Variable(u, "moved", 1)
+-	b, _ := c.Variable(u, "old-flag", 1)
++	b, _ := c.Variable(u,
++		"new-flag", 1)
+ 	x := 1
+-	m, _ := c.Variable(u, "moved", 1)
++	m, _ := c.Variable(u, "moved", 2)
+diff --git a/vendor/lib/lib.go b/vendor/lib/lib.go
+--- a/vendor/lib/lib.go
++++ b/vendor/lib/lib.go
+@@ -1 +1 @@
+-c.Variable(u, "vendored-old", 1)
++c.Variable(u, "vendored-new", 1)
+diff --git a/web/app.ts b/web/app.ts
+new file mode 100644
+--- /dev/null
++++ b/web/app.ts
+@@ -0,0 +1,2 @@
++// --- not a header
++useVariableValue('web-flag', false)
+`
+
+func TestScanDiff(t *testing.T) {
+	s := NewScanner(DefaultPatterns(), nil)
+	removed, added, err := s.ScanDiff(strings.NewReader(testDiff))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := keysOf(removed), []string{"old-flag", "moved"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("removed: expected %v, got %v", want, got)
+	}
+	if got, want := keysOf(added), []string{"new-flag", "moved", "web-flag"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("added: expected %v, got %v", want, got)
+	}
+	if removed[0].Location() != "api/cart.go:11" || removed[1].Location() != "api/cart.go:13" {
+		t.Errorf("unexpected removed locations: %v, %v", removed[0].Location(), removed[1].Location())
+	}
+	if added[0].Location() != "api/cart.go:12" || added[2].Location() != "web/app.ts:2" {
+		t.Errorf("unexpected added locations: %v, %v", added[0].Location(), added[2].Location())
+	}
+
+	changes := Compare(removed, added)
+	var got [][2]string
+	for _, c := range changes {
+		got = append(got, [2]string{c.Key, c.Change})
+	}
+	want := [][2]string{{"new-flag", ChangeAdded}, {"old-flag", ChangeRemoved}, {"web-flag", ChangeAdded}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	if _, _, err := s.ScanDiff(strings.NewReader("--- a/x.go\n+++ b/x.go\n@@ bad @@\n")); err == nil {
+		t.Error("expected error for invalid hunk header")
+	}
+}
+
+func TestAnnotations(t *testing.T) {
+	annotations := []Annotation{
+		{Severity: SeverityError, Check: "dvcx/missing-variable", File: "a,b.go", Line: 3, Message: "50% missing\nnow"},
+	}
+
+	var gh bytes.Buffer
+	if err := WriteGitHubAnnotations(&gh, annotations); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "::error file=a%2Cb.go,line=3,title=dvcx/missing-variable::50%25 missing%0Anow\n"; gh.String() != want {
+		t.Errorf("expected %q, got %q", want, gh.String())
+	}
+
+	var gl bytes.Buffer
+	if err := WriteGitLabReport(&gl, annotations); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var issues []map[string]any
+	if err := json.Unmarshal(gl.Bytes(), &issues); err != nil {
+		t.Fatalf("invalid report: %v", err)
+	}
+	if len(issues) != 1 || issues[0]["severity"] != "major" || issues[0]["fingerprint"] == "" {
+		t.Errorf("unexpected report: %s", gl.String())
+	}
+	loc := issues[0]["location"].(map[string]any)
+	if loc["path"] != "a,b.go" || loc["lines"].(map[string]any)["begin"] != float64(3) {
+		t.Errorf("unexpected location: %v", loc)
+	}
+}
@@ -0,0 +1,391 @@
+// Package wizard builds create requests by asking for each field in turn,
+// for users who would rather answer questions than learn every flag.
+package wizard
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/135yshr/devcycle-cli/internal/filterexpr"
+	"github.com/135yshr/devcycle-cli/internal/prompt"
+	"github.com/135yshr/devcycle-cli/internal/targeting"
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+var keyPattern = regexp.MustCompile(`^[a-z0-9._-]+$`)
+
+// Kebab derives a key from a name: "Dark Mode v2" becomes "dark-mode-v2".
+func Kebab(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '.' || r == '_':
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		default:
+			dash = true
+		}
+	}
+	return b.String()
+}
+
+// title derives a name from a key: "variation-on" becomes "Variation On".
+func title(key string) string {
+	words := strings.FieldsFunc(key, func(r rune) bool { return r == '-' || r == '_' || r == '.' })
+	for i, w := range words {
+		r := []rune(w)
+		r[0] = unicode.ToUpper(r[0])
+		words[i] = string(r)
+	}
+	return strings.Join(words, " ")
+}
+
+// checkKey rejects keys the API does not accept, and keys in taken.
+func checkKey(taken []string) func(string) error {
+	return func(key string) error {
+		if !keyPattern.MatchString(key) {
+			return errors.New("use lowercase letters, digits, '.', '_' and '-'")
+		}
+		if slices.Contains(taken, key) {
+			return fmt.Errorf("%q is already used", key)
+		}
+		return nil
+	}
+}
+
+// FeatureAnswers are the fields of a feature that can be given before the
+// wizard starts, such as with flags.
+type FeatureAnswers struct {
+	Name        string
+	Key         string
+	Description string
+	Type        string
+}
+
+// Feature asks for a feature: the fields that a is missing, then its
+// variables, variations with their values, and its status in each of
+// environments.
+func Feature(p *prompt.Prompter, a FeatureAnswers, environments []api.Environment) (*api.CreateFeatureV2Request, error) {
+	req := &api.CreateFeatureV2Request{Name: a.Name, Key: a.Key, Description: a.Description, Type: a.Type}
+	var err error
+	if req.Name == "" {
+		if req.Name, err = p.Ask("Name", "", prompt.Required); err != nil {
+			return nil, err
+		}
+	}
+	if req.Key == "" {
+		if req.Key, err = p.Ask("Key", Kebab(req.Name), checkKey(nil)); err != nil {
+			return nil, err
+		}
+	}
+	if req.Description == "" {
+		if req.Description, err = p.Ask("Description (optional)", "", nil); err != nil {
+			return nil, err
+		}
+	}
+	if req.Type == "" {
+		i, err := p.Choose("Type", api.FeatureTypes, 0)
+		if err != nil {
+			return nil, err
+		}
+		req.Type = api.FeatureTypes[i]
+	}
+
+	if req.Variables, err = askVariables(p, req.Key); err != nil {
+		return nil, err
+	}
+	if req.Variations, err = askVariations(p, req.Variables); err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for _, v := range req.Variations {
+		keys = append(keys, v.Key)
+	}
+	if req.Type == "experiment" && len(keys) > 1 {
+		i, err := p.Choose("Control variation", keys, 0)
+		if err != nil {
+			return nil, err
+		}
+		req.ControlVariation = keys[i]
+	}
+
+	if req.Configurations, err = askStatuses(p, environments, keys); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func askVariables(p *prompt.Prompter, featureKey string) ([]api.VariableDefinition, error) {
+	var variables []api.VariableDefinition
+	var taken []string
+	for {
+		label, def, suggested := "Add a variable?", true, featureKey
+		if len(variables) > 0 {
+			label, def, suggested = "Add another variable?", false, ""
+		}
+		add, err := p.Confirm(label, def)
+		if err != nil || !add {
+			return variables, err
+		}
+		key, err := p.Ask("Variable key", suggested, checkKey(taken))
+		if err != nil {
+			return nil, err
+		}
+		i, err := p.Choose("Variable type", api.VariableTypes, slices.Index(api.VariableTypes, "Boolean"))
+		if err != nil {
+			return nil, err
+		}
+		variables = append(variables, api.VariableDefinition{Key: key, Type: api.VariableTypes[i]})
+		taken = append(taken, key)
+	}
+}
+
+func askVariations(p *prompt.Prompter, variables []api.VariableDefinition) ([]api.VariationDefinition, error) {
+	if len(variables) == 0 {
+		return nil, nil
+	}
+	if len(variables) == 1 && variables[0].Type == "Boolean" {
+		v := variables[0].Key
+		simple, err := p.Confirm(fmt.Sprintf("Use variations on (%s = true) and off (%s = false)?", v, v), true)
+		if err != nil {
+			return nil, err
+		}
+		if simple {
+			return []api.VariationDefinition{
+				{Key: "on", Name: "On", Variables: map[string]any{v: true}},
+				{Key: "off", Name: "Off", Variables: map[string]any{v: false}},
+			}, nil
+		}
+	}
+
+	var variations []api.VariationDefinition
+	var taken []string
+	for {
+		if len(variations) >= 2 {
+			more, err := p.Confirm("Add another variation?", false)
+			if err != nil {
+				return nil, err
+			}
+			if !more {
+				return variations, nil
+			}
+		}
+		p.Printf("Variation %d\n", len(variations)+1)
+		key, err := p.Ask("Variation key", "", checkKey(taken))
+		if err != nil {
+			return nil, err
+		}
+		name, err := p.Ask("Variation name", title(key), prompt.Required)
+		if err != nil {
+			return nil, err
+		}
+		values := make(map[string]any, len(variables))
+		for _, v := range variables {
+			answer, err := p.Ask(fmt.Sprintf("Value of %s (%s)", v.Key, v.Type), "", func(s string) error {
+				_, err := ParseValue(s, v.Type)
+				return err
+			})
+			if err != nil {
+				return nil, err
+			}
+			values[v.Key], _ = ParseValue(answer, v.Type)
+		}
+		variations = append(variations, api.VariationDefinition{Key: key, Name: name, Variables: values})
+		taken = append(taken, key)
+	}
+}
+
+// ParseValue parses s as a value of the variable type typ.
+func ParseValue(s, typ string) (any, error) {
+	switch typ {
+	case "Boolean":
+		switch strings.ToLower(s) {
+		case "true", "yes", "y":
+			return true, nil
+		case "false", "no", "n":
+			return false, nil
+		}
+		return nil, errors.New("enter true or false")
+	case "Number":
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, errors.New("enter a number")
+		}
+		return f, nil
+	case "JSON":
+		var obj map[string]any
+		if err := json.Unmarshal([]byte(s), &obj); err != nil || obj == nil {
+			return nil, errors.New(`enter a JSON object, such as {"color": "blue"}`)
+		}
+		return obj, nil
+	}
+	return s, nil
+}
+
+// askStatuses asks, for each environment, whether the feature starts off
+// or serves one of variations to everyone.
+func askStatuses(p *prompt.Prompter, environments []api.Environment, variations []string) (map[string]*api.EnvironmentConfig, error) {
+	if len(environments) == 0 || len(variations) == 0 {
+		return nil, nil
+	}
+	options := []string{"off"}
+	for _, v := range variations {
+		options = append(options, "on, serving "+v+" to everyone")
+	}
+	configs := make(map[string]*api.EnvironmentConfig, len(environments))
+	for _, env := range environments {
+		i, err := p.Choose(fmt.Sprintf("Status in %s", env.Key), options, 0)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			configs[env.Key] = &api.EnvironmentConfig{Status: "inactive"}
+			continue
+		}
+		configs[env.Key] = &api.EnvironmentConfig{
+			Status:  "active",
+			Targets: []api.Target{allUsers(variations[i-1])},
+		}
+	}
+	return configs, nil
+}
+
+func allUsers(variation string) api.Target {
+	return api.Target{
+		Name: targeting.DefaultRuleName,
+		Audience: api.Audience{
+			Name:    targeting.DefaultRuleName,
+			Filters: api.Filters{Operator: "and", Filters: []api.Filter{{Type: "all"}}},
+		},
+		Distribution: []api.Distribution{{Variation: variation, Percentage: 1}},
+	}
+}
+
+// AudienceAnswers are the fields of an audience asked by Audience.
+type AudienceAnswers struct {
+	Name        string
+	Key         string
+	Description string
+	// Where is a filter expression; empty matches all users.
+	Where string
+}
+
+// Audience asks for the fields of an audience that a is missing. The
+// filter is only asked when askWhere is set.
+func Audience(p *prompt.Prompter, a AudienceAnswers, askWhere bool) (AudienceAnswers, error) {
+	var err error
+	if a.Name == "" {
+		if a.Name, err = p.Ask("Name", "", prompt.Required); err != nil {
+			return a, err
+		}
+	}
+	if a.Key == "" {
+		if a.Key, err = p.Ask("Key", Kebab(a.Name), checkKey(nil)); err != nil {
+			return a, err
+		}
+	}
+	if a.Description == "" {
+		if a.Description, err = p.Ask("Description (optional)", "", nil); err != nil {
+			return a, err
+		}
+	}
+	if a.Where == "" && askWhere {
+		p.Printf("Filter users with an expression such as 'country in (\"CA\", \"US\") and customData.tier = \"gold\"'.\n")
+		if a.Where, err = p.Ask("Filter (empty for all users)", "", checkWhere); err != nil {
+			return a, err
+		}
+	}
+	return a, nil
+}
+
+// checkWhere rejects filter expressions that do not parse.
+func checkWhere(expr string) error {
+	if expr == "" {
+		return nil
+	}
+	_, err := filterexpr.Parse(expr)
+	var syntaxErr *filterexpr.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return fmt.Errorf("%w\n  %s", err, strings.ReplaceAll(syntaxErr.Highlight(expr), "\n", "\n  "))
+	}
+	return err
+}
+
+// RuleAnswers are the fields of a targeting rule asked by Rule.
+type RuleAnswers struct {
+	Environment string
+	Name        string
+	// Where is a filter expression; empty matches all users.
+	Where string
+	// Serve is a serve specification such as "on:50,off:50".
+	Serve string
+}
+
+// Rule asks for the fields of a targeting rule that r is missing.
+// environments are offered when the environment is missing, and the keys
+// of variations are listed before asking what to serve.
+func Rule(p *prompt.Prompter, r RuleAnswers, environments []api.Environment, variations []api.Variation) (RuleAnswers, error) {
+	if r.Environment == "" && len(environments) > 0 {
+		keys := make([]string, len(environments))
+		for i, env := range environments {
+			keys[i] = env.Key
+		}
+		i, err := p.Choose("Environment", keys, 0)
+		if err != nil {
+			return r, err
+		}
+		r.Environment = keys[i]
+	}
+
+	var err error
+	if r.Name == "" {
+		if r.Name, err = p.Ask("Rule name", "", prompt.Required); err != nil {
+			return r, err
+		}
+	}
+	if r.Where == "" {
+		if r.Where, err = p.Ask("Filter (empty for all users)", "", checkWhere); err != nil {
+			return r, err
+		}
+	}
+	if r.Serve == "" {
+		keys := make([]string, len(variations))
+		for i, v := range variations {
+			keys[i] = v.Key
+		}
+		if len(keys) > 0 {
+			p.Printf("Variations: %s\n", strings.Join(keys, ", "))
+		}
+		def := ""
+		if len(keys) > 0 {
+			def = keys[0]
+		}
+		r.Serve, err = p.Ask("Serve (variation, or variation:percent,...)", def, func(s string) error {
+			dist, err := targeting.ParseServe(s)
+			if err != nil {
+				return err
+			}
+			for _, d := range dist {
+				if len(keys) > 0 && !slices.Contains(keys, d.Variation) {
+					return fmt.Errorf("unknown variation %q", d.Variation)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return r, err
+		}
+	}
+	return r, nil
+}
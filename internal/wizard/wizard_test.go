@@ -0,0 +1,159 @@
+package wizard
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/135yshr/devcycle-cli/internal/prompt"
+	"github.com/135yshr/devcycle-cli/pkg/api"
+)
+
+func answers(lines ...string) *prompt.Prompter {
+	return prompt.New(strings.NewReader(strings.Join(lines, "\n")+"\n"), &bytes.Buffer{})
+}
+
+func TestKebab(t *testing.T) {
+	tests := map[string]string{
+		"Dark Mode":          "dark-mode",
+		"  New Checkout v2!": "new-checkout-v2",
+		"API_rate.limit":     "api_rate.limit",
+	}
+	for name, want := range tests {
+		if got := Kebab(name); got != want {
+			t.Errorf("Kebab(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestFeature(t *testing.T) {
+	environments := []api.Environment{{Key: "development"}, {Key: "production"}}
+
+	t.Run("boolean", func(t *testing.T) {
+		p := answers(
+			"Dark Mode", "", "Dark theme", "", // name, key, description, type
+			"", "", "", "", // add a variable, its key and type, no other variable
+			"",      // use on and off
+			"2", "", // on in development, off in production
+		)
+		req, err := Feature(p, FeatureAnswers{}, environments)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if req.Key != "dark-mode" || req.Type != "release" || req.Description != "Dark theme" {
+			t.Errorf("unexpected feature: %+v", req)
+		}
+		if len(req.Variables) != 1 || req.Variables[0].Key != "dark-mode" || req.Variables[0].Type != "Boolean" {
+			t.Errorf("unexpected variables: %+v", req.Variables)
+		}
+		if len(req.Variations) != 2 || req.Variations[0].Variables["dark-mode"] != true {
+			t.Errorf("unexpected variations: %+v", req.Variations)
+		}
+		dev, prod := req.Configurations["development"], req.Configurations["production"]
+		if dev.Status != "active" || dev.Targets[0].Distribution[0].Variation != "on" || prod.Status != "inactive" {
+			t.Errorf("unexpected configurations: %+v, %+v", dev, prod)
+		}
+		if err := api.ValidateFeatureRequest(req); err != nil {
+			t.Errorf("expected a valid feature, got %v", err)
+		}
+	})
+
+	t.Run("typed values", func(t *testing.T) {
+		p := answers(
+			"Checkout", "", "", "experiment",
+			"", "layout", "String", "y", "limit", "3", "n",
+			"control", "", "classic", "ten", "10",
+			"grid", "Grid Layout", "grid", "20",
+			"",
+			"grid",
+			"", "",
+		)
+		req, err := Feature(p, FeatureAnswers{}, environments)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(req.Variables) != 2 || req.Variables[1].Type != "Number" {
+			t.Errorf("unexpected variables: %+v", req.Variables)
+		}
+		if len(req.Variations) != 2 || req.Variations[0].Name != "Control" || req.Variations[0].Variables["limit"] != 10.0 || req.Variations[1].Variables["layout"] != "grid" {
+			t.Errorf("unexpected variations: %+v", req.Variations)
+		}
+		if req.ControlVariation != "grid" {
+			t.Errorf("expected the chosen control variation, got %q", req.ControlVariation)
+		}
+		if err := api.ValidateFeatureRequest(req); err != nil {
+			t.Errorf("expected a valid feature, got %v", err)
+		}
+	})
+
+	t.Run("given fields", func(t *testing.T) {
+		p := answers(
+			"",             // description
+			"", "", "", "", // add a variable, its key and type, no other variable
+			"",     // use on and off
+			"", "", // off everywhere
+		)
+		req, err := Feature(p, FeatureAnswers{Name: "Dark Mode", Key: "dark", Type: "ops"}, environments)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if req.Name != "Dark Mode" || req.Key != "dark" || req.Type != "ops" {
+			t.Errorf("expected the given fields to be kept, got %+v", req)
+		}
+		if len(req.Variables) != 1 || req.Variables[0].Key != "dark" {
+			t.Errorf("expected the variable to default to the given key, got %+v", req.Variables)
+		}
+	})
+
+	t.Run("aborted", func(t *testing.T) {
+		if _, err := Feature(answers("Dark Mode"), FeatureAnswers{}, environments); err != prompt.ErrAborted {
+			t.Errorf("expected ErrAborted, got %v", err)
+		}
+	})
+}
+
+func TestParseValue(t *testing.T) {
+	if v, err := ParseValue(`{"bg": "#000"}`, "JSON"); err != nil || v.(map[string]any)["bg"] != "#000" {
+		t.Errorf("unexpected JSON value %v, %v", v, err)
+	}
+	for _, tt := range []struct{ value, typ string }{{"[1]", "JSON"}, {"maybe", "Boolean"}, {"1e", "Number"}} {
+		if _, err := ParseValue(tt.value, tt.typ); err == nil {
+			t.Errorf("expected %q to be rejected as %s", tt.value, tt.typ)
+		}
+	}
+}
+
+func TestAudience(t *testing.T) {
+	p := answers("Beta Users", "", "", `country = "JP" and`, `country = "JP"`)
+	a, err := Audience(p, AudienceAnswers{}, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a.Key != "beta-users" || a.Where != `country = "JP"` {
+		t.Errorf("unexpected answers: %+v", a)
+	}
+
+	a, err = Audience(answers(""), AudienceAnswers{Name: "A", Key: "a"}, false)
+	if err != nil || a.Where != "" {
+		t.Errorf("expected only the description to be asked, got %+v, %v", a, err)
+	}
+}
+
+func TestRule(t *testing.T) {
+	environments := []api.Environment{{Key: "development"}, {Key: "production"}}
+	variations := []api.Variation{{Key: "on"}, {Key: "off"}}
+	p := answers("production", "Staff", "user.email endsWith @acme.com", "blue", "on:50,off:40", "on:50,off:50")
+	r, err := Rule(p, RuleAnswers{}, environments, variations)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := RuleAnswers{Environment: "production", Name: "Staff", Where: "user.email endsWith @acme.com", Serve: "on:50,off:50"}
+	if r != want {
+		t.Errorf("expected %+v, got %+v", want, r)
+	}
+
+	r, err = Rule(answers(""), RuleAnswers{Environment: "development", Name: "All", Where: "all"}, nil, variations)
+	if err != nil || r.Serve != "on" {
+		t.Errorf("expected the first variation by default, got %+v, %v", r, err)
+	}
+}
@@ -0,0 +1,56 @@
+// Package workpool runs requests for many keys with a bounded number of them
+// in flight at a time.
+package workpool
+
+import (
+	"context"
+	"sync"
+)
+
+// Map calls fn for each key, with at most workers calls running at a time,
+// and returns the results by key. The first failure cancels the calls still
+// running and stops new ones from starting; its error is returned.
+func Map[T any](ctx context.Context, keys []string, workers int, fn func(ctx context.Context, key string) (T, error)) (map[string]T, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		results  = make(map[string]T, len(keys))
+	)
+	queue := make(chan string)
+	for range max(workers, 1) {
+		wg.Go(func() {
+			for key := range queue {
+				v, err := fn(ctx, key)
+				mu.Lock()
+				if err != nil && firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				results[key] = v
+				mu.Unlock()
+			}
+		})
+	}
+send:
+	for _, key := range keys {
+		select {
+		case queue <- key:
+		case <-ctx.Done():
+			break send
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
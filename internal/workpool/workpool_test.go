@@ -0,0 +1,63 @@
+package workpool
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMap(t *testing.T) {
+	var running, peak int32
+	results, err := Map(context.Background(), []string{"a", "b", "c", "d", "e", "f"}, 3, func(ctx context.Context, key string) (string, error) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		return strings.ToUpper(key), nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 6 || results["c"] != "C" {
+		t.Errorf("unexpected results %v", results)
+	}
+	if peak > 3 || peak < 2 {
+		t.Errorf("expected at most 3 concurrent calls, got %d", peak)
+	}
+}
+
+func TestMapError(t *testing.T) {
+	var calls int32
+	_, err := Map(context.Background(), []string{"a", "b", "c", "d", "e", "f", "g", "h"}, 1, func(ctx context.Context, key string) (int, error) {
+		atomic.AddInt32(&calls, 1)
+		if key == "b" {
+			return 0, errors.New("boom")
+		}
+		return 1, nil
+	})
+	if err == nil || err.Error() != "boom" {
+		t.Errorf("expected the failure of b, got %v", err)
+	}
+	if calls > 3 {
+		t.Errorf("expected the failure to stop the other calls, got %d calls", calls)
+	}
+}
+
+func TestMapCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := Map(ctx, []string{"a", "b"}, 2, func(ctx context.Context, key string) (int, error) {
+		return 1, nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a cancellation error, got %v", err)
+	}
+}
@@ -25,6 +25,7 @@ const (
 // It handles authentication, request/response serialization, and error handling.
 type Client struct {
 	baseURL    string
+	baseURLV2  string
 	httpClient *http.Client
 	token      string
 }
@@ -41,6 +42,13 @@ func WithBaseURL(url string) ClientOption {
 	}
 }
 
+// WithBaseURLV2 returns a ClientOption that sets a custom base URL for v2 API requests.
+func WithBaseURLV2(url string) ClientOption {
+	return func(c *Client) {
+		c.baseURLV2 = url
+	}
+}
+
 // WithTimeout returns a ClientOption that sets a custom timeout for HTTP requests.
 func WithTimeout(timeout time.Duration) ClientOption {
 	return func(c *Client) {
@@ -60,7 +68,8 @@ func WithToken(token string) ClientOption {
 // By default, it uses DefaultBaseURL and DefaultTimeout.
 func NewClient(opts ...ClientOption) *Client {
 	c := &Client{
-		baseURL: DefaultBaseURL,
+		baseURL:   DefaultBaseURL,
+		baseURLV2: DefaultBaseURLV2,
 		httpClient: &http.Client{
 			Timeout: DefaultTimeout,
 		},
@@ -165,7 +174,7 @@ func (c *Client) doV2(ctx context.Context, method, path string, body any, result
 		bodyReader = bytes.NewReader(jsonBody)
 	}
 
-	url := DefaultBaseURLV2 + path
+	url := c.baseURLV2 + path
 	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
@@ -205,6 +214,12 @@ func (c *Client) doV2(ctx context.Context, method, path string, body any, result
 	return nil
 }
 
+// GetV2 sends a GET request to the v2 API endpoint and unmarshals the
+// response into result.
+func (c *Client) GetV2(ctx context.Context, path string, result any) error {
+	return c.doV2(ctx, http.MethodGet, path, nil, result)
+}
+
 // PostV2 sends a POST request to the v2 API endpoint with the given body
 // and unmarshals the response into result.
 func (c *Client) PostV2(ctx context.Context, path string, body any, result any) error {
@@ -16,6 +16,9 @@ func TestNewClient(t *testing.T) {
 		if client.baseURL != DefaultBaseURL {
 			t.Errorf("expected baseURL %s, got %s", DefaultBaseURL, client.baseURL)
 		}
+		if client.baseURLV2 != DefaultBaseURLV2 {
+			t.Errorf("expected baseURLV2 %s, got %s", DefaultBaseURLV2, client.baseURLV2)
+		}
 		if client.token != "" {
 			t.Errorf("expected empty token, got %s", client.token)
 		}
@@ -27,6 +30,7 @@ func TestNewClient(t *testing.T) {
 
 		client := NewClient(
 			WithBaseURL(customURL),
+			WithBaseURLV2(customURL+"/v2"),
 			WithToken(customToken),
 			WithTimeout(60*time.Second),
 		)
@@ -34,6 +38,9 @@ func TestNewClient(t *testing.T) {
 		if client.baseURL != customURL {
 			t.Errorf("expected baseURL %s, got %s", customURL, client.baseURL)
 		}
+		if client.baseURLV2 != customURL+"/v2" {
+			t.Errorf("expected baseURLV2 %s/v2, got %s", customURL, client.baseURLV2)
+		}
 		if client.token != customToken {
 			t.Errorf("expected token %s, got %s", customToken, client.token)
 		}
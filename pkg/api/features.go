@@ -2,14 +2,12 @@ package api
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
 	"net/url"
-	"os"
+	"slices"
 )
 
-// MaxFileSize is the maximum file size allowed for JSON input files (10MB)
+// MaxFileSize is the maximum file size allowed for input files (10MB)
 const MaxFileSize = 10 * 1024 * 1024
 
 // Features returns all features for a project.
@@ -22,6 +20,40 @@ func (c *Client) Features(ctx context.Context, projectKey string) ([]Feature, er
 	return features, nil
 }
 
+// FeatureListOptions contains options for listing features
+type FeatureListOptions struct {
+	Status    string // active, complete, archived
+	SortBy    string // key, name, createdAt, updatedAt
+	SortOrder string // asc, desc
+}
+
+// FeaturesWithOptions returns the features of a project that match opts.
+func (c *Client) FeaturesWithOptions(ctx context.Context, projectKey string, opts *FeatureListOptions) ([]Feature, error) {
+	var features []Feature
+	path := fmt.Sprintf("/projects/%s/features", url.PathEscape(projectKey))
+
+	if opts != nil {
+		params := url.Values{}
+		if opts.Status != "" {
+			params.Set("status", opts.Status)
+		}
+		if opts.SortBy != "" {
+			params.Set("sortBy", opts.SortBy)
+		}
+		if opts.SortOrder != "" {
+			params.Set("sortOrder", opts.SortOrder)
+		}
+		if len(params) > 0 {
+			path += "?" + params.Encode()
+		}
+	}
+
+	if err := c.Get(ctx, path, &features); err != nil {
+		return nil, fmt.Errorf("failed to list features: %w", err)
+	}
+	return features, nil
+}
+
 // Feature returns a specific feature by its key.
 func (c *Client) Feature(ctx context.Context, projectKey, featureKey string) (*Feature, error) {
 	var feature Feature
@@ -68,6 +100,68 @@ func (c *Client) UpdateFeature(ctx context.Context, projectKey, featureKey strin
 	return &feature, nil
 }
 
+// UpdateFeatureTagsRequest represents the request body for replacing a feature's tags.
+// An empty Tags removes every tag.
+type UpdateFeatureTagsRequest struct {
+	Tags []string `json:"tags"`
+}
+
+// UpdateFeatureTags replaces the tags of a feature.
+func (c *Client) UpdateFeatureTags(ctx context.Context, projectKey, featureKey string, tags []string) (*Feature, error) {
+	if tags == nil {
+		tags = []string{}
+	}
+	var feature Feature
+	path := fmt.Sprintf("/projects/%s/features/%s", url.PathEscape(projectKey), url.PathEscape(featureKey))
+	if err := c.Patch(ctx, path, &UpdateFeatureTagsRequest{Tags: tags}, &feature); err != nil {
+		return nil, fmt.Errorf("failed to update feature tags: %w", err)
+	}
+	return &feature, nil
+}
+
+// Feature status values.
+const (
+	FeatureStatusActive   = "active"
+	FeatureStatusComplete = "complete"
+	FeatureStatusArchived = "archived"
+)
+
+// UpdateFeatureStatusRequest represents the request body for changing a feature's status.
+// StaticVariation is the variation served to everyone once a feature is complete.
+type UpdateFeatureStatusRequest struct {
+	Status          string `json:"status"`
+	StaticVariation string `json:"staticVariation,omitempty"`
+}
+
+// UpdateFeatureStatus changes a feature's status, for example to mark it complete.
+func (c *Client) UpdateFeatureStatus(ctx context.Context, projectKey, featureKey string, req *UpdateFeatureStatusRequest) (*Feature, error) {
+	var feature Feature
+	path := fmt.Sprintf("/projects/%s/features/%s/status", url.PathEscape(projectKey), url.PathEscape(featureKey))
+	if err := c.Patch(ctx, path, req, &feature); err != nil {
+		return nil, fmt.Errorf("failed to update feature status: %w", err)
+	}
+	return &feature, nil
+}
+
+// CompleteFeature marks a feature complete. Once complete, staticVariation
+// is served to every user in every environment.
+func (c *Client) CompleteFeature(ctx context.Context, projectKey, featureKey, staticVariation string) (*Feature, error) {
+	return c.UpdateFeatureStatus(ctx, projectKey, featureKey, &UpdateFeatureStatusRequest{
+		Status:          FeatureStatusComplete,
+		StaticVariation: staticVariation,
+	})
+}
+
+// ArchiveFeature archives a feature.
+func (c *Client) ArchiveFeature(ctx context.Context, projectKey, featureKey string) (*Feature, error) {
+	return c.UpdateFeatureStatus(ctx, projectKey, featureKey, &UpdateFeatureStatusRequest{Status: FeatureStatusArchived})
+}
+
+// UnarchiveFeature restores an archived or complete feature to active.
+func (c *Client) UnarchiveFeature(ctx context.Context, projectKey, featureKey string) (*Feature, error) {
+	return c.UpdateFeatureStatus(ctx, projectKey, featureKey, &UpdateFeatureStatusRequest{Status: FeatureStatusActive})
+}
+
 // DeleteFeature removes a feature from a project.
 // Warning: This action cannot be undone.
 func (c *Client) DeleteFeature(ctx context.Context, projectKey, featureKey string) error {
@@ -80,6 +174,17 @@ func (c *Client) DeleteFeature(ctx context.Context, projectKey, featureKey strin
 
 // v2 API methods
 
+// FeatureV2 returns a feature with its full configuration using the v2 API,
+// including variables, variations, settings and per-environment targeting.
+func (c *Client) FeatureV2(ctx context.Context, projectKey, featureKey string) (*FeatureV2, error) {
+	var feature FeatureV2
+	path := fmt.Sprintf("/projects/%s/features/%s", url.PathEscape(projectKey), url.PathEscape(featureKey))
+	if err := c.GetV2(ctx, path, &feature); err != nil {
+		return nil, fmt.Errorf("failed to get feature: %w", err)
+	}
+	return &feature, nil
+}
+
 // CreateFeatureV2 creates a feature using the v2 API with full configuration support
 // including variables, variations, and targeting rules.
 func (c *Client) CreateFeatureV2(ctx context.Context, projectKey string, req *CreateFeatureV2Request) (*FeatureV2, error) {
@@ -111,49 +216,62 @@ func (c *Client) UpdateFeatureV2(ctx context.Context, projectKey, featureKey str
 	return &feature, nil
 }
 
-// LoadFeatureRequestFromFile loads a CreateFeatureV2Request from a JSON file
-// If filePath is "-", it reads from stdin
+// Request returns the v2 request that describes f, for use as the base of
+// an update.
+func (f *FeatureV2) Request() *CreateFeatureV2Request {
+	return &CreateFeatureV2Request{
+		Name:             f.Name,
+		Key:              f.Key,
+		Description:      f.Description,
+		Type:             f.Type,
+		Tags:             f.Tags,
+		ControlVariation: f.ControlVariation,
+		SDKVisibility:    f.SDKVisibility,
+		Settings:         f.Settings,
+		Variables:        f.Variables,
+		Variations:       f.Variations,
+		Configurations:   f.Configurations,
+	}
+}
+
+// LoadFeatureRequestFromFile loads a CreateFeatureV2Request from a JSON or
+// YAML file. If filePath is "-", it reads from stdin.
 // Maximum file size is limited to MaxFileSize (10MB) to prevent memory exhaustion
 func LoadFeatureRequestFromFile(filePath string) (*CreateFeatureV2Request, error) {
-	var data []byte
-	var err error
-
-	if filePath == "-" {
-		data, err = io.ReadAll(io.LimitReader(os.Stdin, MaxFileSize))
-		if err != nil {
-			return nil, fmt.Errorf("failed to read from stdin: %w", err)
-		}
-	} else {
-		file, err := os.Open(filePath)
-		if err != nil {
-			return nil, fmt.Errorf("failed to open file %s: %w", filePath, err)
-		}
-		defer file.Close()
-
-		info, err := file.Stat()
-		if err != nil {
-			return nil, fmt.Errorf("failed to stat file %s: %w", filePath, err)
-		}
-		if info.Size() > MaxFileSize {
-			return nil, fmt.Errorf("file %s exceeds maximum allowed size (%d bytes)", filePath, MaxFileSize)
-		}
-		data, err = io.ReadAll(file)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read file %s: %w", filePath, err)
-		}
+	data, err := ReadInputFile(filePath)
+	if err != nil {
+		return nil, err
 	}
 
 	var req CreateFeatureV2Request
-	if err := json.Unmarshal(data, &req); err != nil {
-		if filePath == "-" {
-			return nil, fmt.Errorf("failed to parse JSON from stdin: %w", err)
-		}
-		return nil, fmt.Errorf("failed to parse JSON from %s: %w", filePath, err)
+	if err := DecodeInput(data, filePath, &req); err != nil {
+		return nil, err
 	}
-
 	return &req, nil
 }
 
+// LoadFeatureRequestsFromFile loads every feature defined in a JSON or YAML
+// file. A YAML file may define several features as separate documents.
+// If filePath is "-", it reads from stdin.
+func LoadFeatureRequestsFromFile(filePath string) ([]*CreateFeatureV2Request, error) {
+	data, err := ReadInputFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return DecodeInputAll[*CreateFeatureV2Request](data, filePath)
+}
+
+// Values accepted by the API for the type of a feature, the type of a
+// variable, the type of a variable validation schema and the status of an
+// environment configuration.
+var (
+	FeatureTypes          = []string{"release", "experiment", "permission", "ops"}
+	VariableTypes         = []string{"String", "Boolean", "Number", "JSON"}
+	ValidationSchemaTypes = []string{"enum", "regex", "jsonSchema"}
+	ConfigurationStatuses = []string{"active", "inactive"}
+)
+
 // ValidateFeatureRequest validates a CreateFeatureV2Request
 func ValidateFeatureRequest(req *CreateFeatureV2Request) error {
 	if req.Name == "" {
@@ -166,23 +284,11 @@ func ValidateFeatureRequest(req *CreateFeatureV2Request) error {
 		req.Type = "release" // default type
 	}
 
-	validTypes := map[string]bool{
-		"release":    true,
-		"experiment": true,
-		"permission": true,
-		"ops":        true,
-	}
-	if !validTypes[req.Type] {
+	if !slices.Contains(FeatureTypes, req.Type) {
 		return fmt.Errorf("invalid type: %s (must be one of: release, experiment, permission, ops)", req.Type)
 	}
 
 	// Validate variable types
-	validVarTypes := map[string]bool{
-		"String":  true,
-		"Boolean": true,
-		"Number":  true,
-		"JSON":    true,
-	}
 	for _, v := range req.Variables {
 		if v.Key == "" {
 			return fmt.Errorf("variable key is required")
@@ -190,7 +296,7 @@ func ValidateFeatureRequest(req *CreateFeatureV2Request) error {
 		if v.Type == "" {
 			return fmt.Errorf("variable type is required for variable %s", v.Key)
 		}
-		if !validVarTypes[v.Type] {
+		if !slices.Contains(VariableTypes, v.Type) {
 			return fmt.Errorf("invalid variable type for %s: %s (must be one of: String, Boolean, Number, JSON)", v.Key, v.Type)
 		}
 	}
@@ -206,15 +312,11 @@ func ValidateFeatureRequest(req *CreateFeatureV2Request) error {
 	}
 
 	// Validate configurations
-	validStatuses := map[string]bool{
-		"active":   true,
-		"inactive": true,
-	}
 	for envKey, config := range req.Configurations {
 		if config == nil {
 			continue
 		}
-		if config.Status != "" && !validStatuses[config.Status] {
+		if config.Status != "" && !slices.Contains(ConfigurationStatuses, config.Status) {
 			return fmt.Errorf("invalid status for environment %s: %s (must be active or inactive)", envKey, config.Status)
 		}
 		for _, target := range config.Targets {
@@ -3,10 +3,12 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -266,6 +268,180 @@ func TestClient_UpdateFeature(t *testing.T) {
 	})
 }
 
+func TestClient_UpdateFeatureTags(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []string
+		want string
+	}{
+		{name: "set tags", tags: []string{"ui", "web"}, want: `{"tags":["ui","web"]}`},
+		{name: "clear tags", tags: nil, want: `{"tags":[]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPatch {
+					t.Errorf("expected PATCH, got %s", r.Method)
+				}
+				if r.URL.Path != "/projects/my-project/features/feature-one" {
+					t.Errorf("expected /projects/my-project/features/feature-one, got %s", r.URL.Path)
+				}
+				body, _ := io.ReadAll(r.Body)
+				if got := strings.TrimSpace(string(body)); got != tt.want {
+					t.Errorf("expected body %s, got %s", tt.want, got)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(Feature{Key: "feature-one", Tags: tt.tags})
+			}))
+			defer server.Close()
+
+			client := NewClient(WithBaseURL(server.URL), WithToken("test-token"))
+			result, err := client.UpdateFeatureTags(context.Background(), "my-project", "feature-one", tt.tags)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(result.Tags) != len(tt.tags) {
+				t.Errorf("expected tags %v, got %v", tt.tags, result.Tags)
+			}
+		})
+	}
+}
+
+func TestClient_UpdateFeatureStatus(t *testing.T) {
+	t.Run("successful update", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPatch {
+				t.Errorf("expected PATCH, got %s", r.Method)
+			}
+			if r.URL.Path != "/projects/my-project/features/feature-one/status" {
+				t.Errorf("expected /projects/my-project/features/feature-one/status, got %s", r.URL.Path)
+			}
+
+			var req UpdateFeatureStatusRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			if req.Status != FeatureStatusComplete || req.StaticVariation != "on" {
+				t.Errorf("unexpected request: %+v", req)
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(Feature{Key: "feature-one", Status: req.Status})
+		}))
+		defer server.Close()
+
+		client := NewClient(WithBaseURL(server.URL), WithToken("test-token"))
+		result, err := client.UpdateFeatureStatus(context.Background(), "my-project", "feature-one", &UpdateFeatureStatusRequest{
+			Status:          FeatureStatusComplete,
+			StaticVariation: "on",
+		})
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Status != FeatureStatusComplete {
+			t.Errorf("expected complete, got %s", result.Status)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("feature not found"))
+		}))
+		defer server.Close()
+
+		client := NewClient(WithBaseURL(server.URL))
+		_, err := client.UpdateFeatureStatus(context.Background(), "my-project", "non-existent", &UpdateFeatureStatusRequest{
+			Status: FeatureStatusArchived,
+		})
+
+		if !IsNotFound(err) {
+			t.Errorf("expected not found error, got %v", err)
+		}
+	})
+}
+
+func TestClient_FeaturesWithOptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/projects/my-project/features" {
+			t.Errorf("expected /projects/my-project/features, got %s", r.URL.Path)
+		}
+		want := map[string]string{"status": "archived", "sortBy": "createdAt", "sortOrder": "desc"}
+		for param, value := range want {
+			if got := r.URL.Query().Get(param); got != value {
+				t.Errorf("expected %s=%s, got %q", param, value, got)
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]Feature{{Key: "old", Status: "archived"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithToken("test-token"))
+	result, err := client.FeaturesWithOptions(context.Background(), "my-project", &FeatureListOptions{
+		Status:    "archived",
+		SortBy:    "createdAt",
+		SortOrder: "desc",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result) != 1 || result[0].Key != "old" {
+		t.Errorf("unexpected features: %+v", result)
+	}
+}
+
+func TestClient_FeatureLifecycle(t *testing.T) {
+	tests := []struct {
+		name       string
+		call       func(c *Client) (*Feature, error)
+		wantStatus string
+		wantStatic string
+	}{
+		{
+			name:       "complete",
+			call:       func(c *Client) (*Feature, error) { return c.CompleteFeature(context.Background(), "p", "f", "on") },
+			wantStatus: FeatureStatusComplete,
+			wantStatic: "on",
+		},
+		{
+			name:       "archive",
+			call:       func(c *Client) (*Feature, error) { return c.ArchiveFeature(context.Background(), "p", "f") },
+			wantStatus: FeatureStatusArchived,
+		},
+		{
+			name:       "unarchive",
+			call:       func(c *Client) (*Feature, error) { return c.UnarchiveFeature(context.Background(), "p", "f") },
+			wantStatus: FeatureStatusActive,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPatch || r.URL.Path != "/projects/p/features/f/status" {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				var req UpdateFeatureStatusRequest
+				json.NewDecoder(r.Body).Decode(&req)
+				if req.Status != tt.wantStatus || req.StaticVariation != tt.wantStatic {
+					t.Errorf("unexpected request body: %+v", req)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(Feature{Key: "f", Status: req.Status})
+			}))
+			defer server.Close()
+
+			feature, err := tt.call(NewClient(WithBaseURL(server.URL), WithToken("test-token")))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if feature.Status != tt.wantStatus {
+				t.Errorf("expected status %s, got %s", tt.wantStatus, feature.Status)
+			}
+		})
+	}
+}
+
 func TestClient_DeleteFeature(t *testing.T) {
 	t.Run("successful delete", func(t *testing.T) {
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -350,12 +526,7 @@ func TestClient_CreateFeatureV2(t *testing.T) {
 		}))
 		defer server.Close()
 
-		// Override v2 base URL for testing
-		originalV2URL := DefaultBaseURLV2
-		defer func() { _ = originalV2URL }()
-
-		client := NewClient(WithToken("test-token"))
-		// Create a custom test that uses our mock server
+		client := NewClient(WithBaseURLV2(server.URL), WithToken("test-token"))
 		req := &CreateFeatureV2Request{
 			Name: "V2 Feature",
 			Key:  "v2-feature",
@@ -368,20 +539,88 @@ func TestClient_CreateFeatureV2(t *testing.T) {
 				{Key: "on", Name: "On", Variables: map[string]any{"enabled": true}},
 			},
 		}
+		result, err := client.CreateFeatureV2(context.Background(), "my-project", req)
 
-		// We need to test the request structure, not the actual API call
-		// since we can't easily override DefaultBaseURLV2
-		if req.Name != "V2 Feature" {
-			t.Errorf("expected V2 Feature, got %s", req.Name)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
 		}
-		if len(req.Variables) != 1 {
-			t.Errorf("expected 1 variable, got %d", len(req.Variables))
+		if result.Key != "v2-feature" || len(result.Variations) != 2 {
+			t.Errorf("unexpected feature: %+v", result)
+		}
+	})
+}
+
+func TestClient_FeatureV2(t *testing.T) {
+	t.Run("successful get", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet {
+				t.Errorf("expected GET, got %s", r.Method)
+			}
+			if r.URL.Path != "/projects/my-project/features/v2-feature" {
+				t.Errorf("expected /projects/my-project/features/v2-feature, got %s", r.URL.Path)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(FeatureV2{
+				Key:           "v2-feature",
+				SDKVisibility: &SDKVisibility{Client: true, Server: true},
+				Configurations: map[string]*EnvironmentConfig{
+					"production": {Status: "active"},
+				},
+			})
+		}))
+		defer server.Close()
+
+		client := NewClient(WithBaseURLV2(server.URL), WithToken("test-token"))
+		result, err := client.FeatureV2(context.Background(), "my-project", "v2-feature")
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.SDKVisibility == nil || !result.SDKVisibility.Client {
+			t.Errorf("expected SDK visibility, got %+v", result.SDKVisibility)
+		}
+		if result.Configurations["production"].Status != "active" {
+			t.Errorf("expected production configuration, got %+v", result.Configurations)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		client := NewClient(WithBaseURLV2(server.URL), WithToken("test-token"))
+		_, err := client.FeatureV2(context.Background(), "my-project", "missing")
+		if !IsNotFound(err) {
+			t.Errorf("expected not found error, got %v", err)
 		}
-		_ = client
-		_ = server
 	})
 }
 
+func TestFeatureV2_Request(t *testing.T) {
+	feature := &FeatureV2{
+		ID:            "feat-1",
+		Key:           "dark-mode",
+		Name:          "Dark Mode",
+		Type:          "release",
+		Tags:          []string{"ui"},
+		SDKVisibility: &SDKVisibility{Client: true},
+		Variables:     []VariableDefinition{{Key: "enabled", Type: "Boolean"}},
+		CreatedAt:     time.Now(),
+	}
+	req := feature.Request()
+	if req.Key != "dark-mode" || req.Name != "Dark Mode" || req.Type != "release" {
+		t.Errorf("unexpected request: %+v", req)
+	}
+	if len(req.Tags) != 1 || req.SDKVisibility == nil || len(req.Variables) != 1 {
+		t.Errorf("expected tags, SDK visibility and variables to be copied, got %+v", req)
+	}
+	if err := ValidateFeatureRequest(req); err != nil {
+		t.Errorf("expected request to be valid, got %v", err)
+	}
+}
+
 func TestLoadFeatureRequestFromFile(t *testing.T) {
 	t.Run("successful load minimal config", func(t *testing.T) {
 		content := `{
@@ -482,6 +721,37 @@ func TestLoadFeatureRequestFromFile(t *testing.T) {
 		}
 	})
 
+	t.Run("successful load yaml", func(t *testing.T) {
+		content := `name: YAML Feature
+key: yaml-feature
+type: release
+variables:
+  - key: enabled
+    type: Boolean
+variations:
+  - key: "on"
+    name: On
+    variables:
+      enabled: true
+`
+		tmpDir := t.TempDir()
+		filePath := filepath.Join(tmpDir, "feature.yaml")
+		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write test file: %v", err)
+		}
+
+		req, err := LoadFeatureRequestFromFile(filePath)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if req.Key != "yaml-feature" || len(req.Variables) != 1 {
+			t.Errorf("unexpected request: %+v", req)
+		}
+		if len(req.Variations) != 1 || req.Variations[0].Variables["enabled"] != true {
+			t.Errorf("unexpected variations: %+v", req.Variations)
+		}
+	})
+
 	t.Run("file not found", func(t *testing.T) {
 		_, err := LoadFeatureRequestFromFile("/nonexistent/path/file.json")
 		if err == nil {
@@ -503,6 +773,32 @@ func TestLoadFeatureRequestFromFile(t *testing.T) {
 	})
 }
 
+func TestLoadFeatureRequestsFromFile(t *testing.T) {
+	content := `name: First
+key: first
+type: release
+---
+name: Second
+key: second
+type: ops
+`
+	filePath := filepath.Join(t.TempDir(), "features.yaml")
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	reqs, err := LoadFeatureRequestsFromFile(filePath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(reqs) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(reqs))
+	}
+	if reqs[0].Key != "first" || reqs[1].Key != "second" || reqs[1].Type != "ops" {
+		t.Errorf("unexpected requests: %+v, %+v", reqs[0], reqs[1])
+	}
+}
+
 func TestValidateFeatureRequest(t *testing.T) {
 	t.Run("valid minimal request", func(t *testing.T) {
 		req := &CreateFeatureV2Request{
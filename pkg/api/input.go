@@ -0,0 +1,374 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Input formats accepted by DecodeInput. JSON input may contain comments
+// and trailing commas (JSONC).
+const (
+	InputFormatJSON = "JSON"
+	InputFormatYAML = "YAML"
+)
+
+// InputError is an error in an input file. Line and Column are 1-based and
+// zero when the position is not known.
+type InputError struct {
+	Source string // file path, or "stdin"
+	Format string // InputFormatJSON or InputFormatYAML
+	Line   int
+	Column int
+	Err    error
+}
+
+func (e *InputError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "failed to parse %s from %s", e.Format, e.Source)
+	if e.Line > 0 {
+		fmt.Fprintf(&b, " at line %d", e.Line)
+		if e.Column > 0 {
+			fmt.Fprintf(&b, ", column %d", e.Column)
+		}
+	}
+	fmt.Fprintf(&b, ": %v", e.Err)
+	return b.String()
+}
+
+func (e *InputError) Unwrap() error {
+	return e.Err
+}
+
+// ReadInputFile reads an input file, or stdin when path is "-".
+// Input larger than MaxFileSize is rejected.
+func ReadInputFile(path string) ([]byte, error) {
+	if path == "-" {
+		data, err := io.ReadAll(io.LimitReader(os.Stdin, MaxFileSize+1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read from stdin: %w", err)
+		}
+		if len(data) > MaxFileSize {
+			return nil, fmt.Errorf("stdin exceeds maximum allowed size (%d bytes)", MaxFileSize)
+		}
+		return data, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file %s: %w", path, err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat file %s: %w", path, err)
+	}
+	if info.Size() > MaxFileSize {
+		return nil, fmt.Errorf("file %s exceeds maximum allowed size (%d bytes)", path, MaxFileSize)
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", path, err)
+	}
+	return data, nil
+}
+
+// DecodeInput decodes input read from path into v, using the json struct
+// tags of v. The input must hold a single document.
+//
+// Files ending in .yaml or .yml are YAML and files ending in .json or
+// .jsonc are JSON; otherwise input starting with '{' or '[' is JSON and
+// anything else is YAML. JSON may contain // and /* */ comments and
+// trailing commas. References to environment variables, ${NAME} or
+// ${NAME:-default}, are replaced before parsing; $${ stands for a literal ${.
+func DecodeInput(data []byte, path string, v any) error {
+	docs, err := decodeInputDocuments(data, path)
+	if err != nil {
+		return err
+	}
+	if len(docs) != 1 {
+		return docs[0].errorf(0, "expected a single document, found %d", len(docs))
+	}
+	return docs[0].decode(v)
+}
+
+// DecodeInputAll decodes every document of input read from path into a new
+// T. Only YAML input can hold several documents, separated by "---".
+// See DecodeInput for the accepted formats.
+func DecodeInputAll[T any](data []byte, path string) ([]T, error) {
+	docs, err := decodeInputDocuments(data, path)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]T, len(docs))
+	for i, d := range docs {
+		if err := d.decode(&out[i]); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// inputDocument is one document of an input, converted to JSON.
+type inputDocument struct {
+	source string
+	format string
+	// line is the first line of the document in the input.
+	line int
+	data []byte
+	// positions reports whether offsets in data are offsets in the input,
+	// so that decoding errors can be given a line and column.
+	positions bool
+}
+
+func (d *inputDocument) errorf(line int, format string, args ...any) error {
+	return &InputError{Source: d.source, Format: d.format, Line: line, Err: fmt.Errorf(format, args...)}
+}
+
+func (d *inputDocument) decode(v any) error {
+	err := json.Unmarshal(d.data, v)
+	if err == nil {
+		return nil
+	}
+	inputErr := &InputError{Source: d.source, Format: d.format, Err: err}
+	var typeErr *json.UnmarshalTypeError
+	if d.positions && errors.As(err, &typeErr) {
+		inputErr.Line, inputErr.Column = position(d.data, typeErr.Offset)
+	} else if d.line > 1 {
+		inputErr.Line = d.line
+	}
+	return inputErr
+}
+
+func decodeInputDocuments(data []byte, path string) ([]inputDocument, error) {
+	source := path
+	if path == "-" {
+		source = "stdin"
+	}
+	format := detectInputFormat(data, path)
+
+	data, err := interpolateEnv(data)
+	if err != nil {
+		return nil, &InputError{Source: source, Format: format, Line: err.line, Err: err}
+	}
+
+	if format == InputFormatJSON {
+		stripped := stripJSONComments(data)
+		var syntaxErr *json.SyntaxError
+		if err := json.Unmarshal(stripped, new(json.RawMessage)); errors.As(err, &syntaxErr) {
+			line, col := position(stripped, syntaxErr.Offset)
+			return nil, &InputError{Source: source, Format: format, Line: line, Column: col, Err: err}
+		} else if err != nil {
+			return nil, &InputError{Source: source, Format: format, Err: err}
+		}
+		return []inputDocument{{source: source, format: format, line: 1, data: stripped, positions: true}}, nil
+	}
+
+	var docs []inputDocument
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var node yaml.Node
+		err := dec.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, yamlInputError(source, err)
+		}
+		var value any
+		if err := node.Decode(&value); err != nil {
+			return nil, yamlInputError(source, err)
+		}
+		if value == nil {
+			continue // empty document, e.g. after a trailing "---"
+		}
+		converted, err := json.Marshal(jsonCompatible(value))
+		if err != nil {
+			return nil, &InputError{Source: source, Format: format, Line: node.Line, Err: err}
+		}
+		docs = append(docs, inputDocument{source: source, format: format, line: node.Line, data: converted})
+	}
+	if len(docs) == 0 {
+		return nil, &InputError{Source: source, Format: format, Err: errors.New("input is empty")}
+	}
+	return docs, nil
+}
+
+// detectInputFormat returns the format of input read from path.
+func detectInputFormat(data []byte, path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return InputFormatYAML
+	case ".json", ".jsonc":
+		return InputFormatJSON
+	}
+	trimmed := bytes.TrimSpace(stripJSONComments(data))
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		return InputFormatJSON
+	}
+	return InputFormatYAML
+}
+
+var yamlLinePattern = regexp.MustCompile(`^(?:yaml: )?line (\d+): `)
+
+// yamlInputError converts a yaml.v3 error, whose message starts with the
+// line number, to an InputError.
+func yamlInputError(source string, err error) error {
+	msg := err.Error()
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) && len(typeErr.Errors) > 0 {
+		msg = typeErr.Errors[0]
+	}
+	inputErr := &InputError{Source: source, Format: InputFormatYAML, Err: err}
+	if m := yamlLinePattern.FindStringSubmatch(msg); m != nil {
+		inputErr.Line, _ = strconv.Atoi(m[1])
+		inputErr.Err = errors.New(msg[len(m[0]):])
+	}
+	return inputErr
+}
+
+// jsonCompatible converts the maps with non-string keys that YAML allows
+// to maps with string keys.
+func jsonCompatible(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, e := range v {
+			v[k] = jsonCompatible(e)
+		}
+		return v
+	case map[any]any:
+		out := make(map[string]any, len(v))
+		for k, e := range v {
+			out[fmt.Sprint(k)] = jsonCompatible(e)
+		}
+		return out
+	case []any:
+		for i, e := range v {
+			v[i] = jsonCompatible(e)
+		}
+		return v
+	}
+	return v
+}
+
+// stripJSONComments returns a copy of data with comments and trailing
+// commas replaced by spaces, so that offsets are unchanged.
+func stripJSONComments(data []byte) []byte {
+	out := bytes.Clone(data)
+	inString := false
+	for i := 0; i < len(out); i++ {
+		c := out[i]
+		if inString {
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+		switch {
+		case c == '"':
+			inString = true
+		case c == '/' && i+1 < len(out) && out[i+1] == '/':
+			for ; i < len(out) && out[i] != '\n'; i++ {
+				out[i] = ' '
+			}
+		case c == '/' && i+1 < len(out) && out[i+1] == '*':
+			end := bytes.Index(out[i+2:], []byte("*/"))
+			if end < 0 {
+				return out
+			}
+			end += i + 4
+			for ; i < end; i++ {
+				if out[i] != '\n' {
+					out[i] = ' '
+				}
+			}
+			i--
+		}
+	}
+
+	inString = false
+	for i := 0; i < len(out); i++ {
+		c := out[i]
+		if inString {
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+		if c == '"' {
+			inString = true
+		} else if c == ',' {
+			next := bytes.TrimLeft(out[i+1:], " \t\r\n")
+			if len(next) > 0 && (next[0] == '}' || next[0] == ']') {
+				out[i] = ' '
+			}
+		}
+	}
+	return out
+}
+
+var envRefPattern = regexp.MustCompile(`\$\$\{|\$\{([A-Za-z_][A-Za-z0-9_]*)(:-[^}]*)?\}`)
+
+// envError is an undefined environment variable reference.
+type envError struct {
+	name string
+	line int
+}
+
+func (e *envError) Error() string {
+	return fmt.Sprintf("environment variable %s is not set", e.name)
+}
+
+// interpolateEnv replaces environment variable references in data.
+func interpolateEnv(data []byte) ([]byte, *envError) {
+	matches := envRefPattern.FindAllSubmatchIndex(data, -1)
+	if matches == nil {
+		return data, nil
+	}
+	var out bytes.Buffer
+	last := 0
+	for _, m := range matches {
+		out.Write(data[last:m[0]])
+		last = m[1]
+		if m[2] < 0 {
+			out.WriteString("${")
+			continue
+		}
+		name := string(data[m[2]:m[3]])
+		value, ok := os.LookupEnv(name)
+		if m[4] >= 0 && value == "" {
+			value, ok = string(data[m[4]+2:m[5]]), true
+		}
+		if !ok {
+			return nil, &envError{name: name, line: bytes.Count(data[:m[0]], []byte("\n")) + 1}
+		}
+		out.WriteString(value)
+	}
+	out.Write(data[last:])
+	return out.Bytes(), nil
+}
+
+// position returns the 1-based line and column of offset in data.
+func position(data []byte, offset int64) (line, column int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	before := data[:offset]
+	line = bytes.Count(before, []byte("\n")) + 1
+	column = len(before) - bytes.LastIndexByte(before, '\n') - 1
+	return line, max(column, 1)
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDecodeInput(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		data string
+	}{
+		{name: "json by extension", path: "f.json", data: `{"key": "a"}`},
+		{name: "yaml by extension", path: "f.yml", data: "key: a"},
+		{name: "json by content", path: "-", data: `  {"key": "a"}`},
+		{name: "yaml by content", path: "-", data: "# comment\nkey: a"},
+		{name: "jsonc comments and trailing commas", path: "-", data: "// leading\n{\n  /* block\n comment */ \"key\": \"a\", // trailing\n  \"tags\": [\"x\",],\n}"},
+		{name: "comment markers inside strings", path: "f.jsonc", data: `{"key": "a", "url": "http://x/*y*/", "tags": ["a,]"]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v struct {
+				Key string `json:"key"`
+				URL string `json:"url"`
+			}
+			if err := DecodeInput([]byte(tt.data), tt.path, &v); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if v.Key != "a" {
+				t.Errorf("expected key a, got %q", v.Key)
+			}
+			if strings.Contains(tt.data, "http://") && v.URL != "http://x/*y*/" {
+				t.Errorf("expected string contents to be kept, got %q", v.URL)
+			}
+		})
+	}
+}
+
+func TestDecodeInputEnv(t *testing.T) {
+	t.Setenv("DVCX_TEST_NAME", "From Env")
+	t.Setenv("DVCX_TEST_EMPTY", "")
+
+	data := "name: ${DVCX_TEST_NAME}\nkey: ${DVCX_TEST_UNSET:-fallback}\ndescription: ${DVCX_TEST_EMPTY:-default} $${LITERAL}\n"
+	var req CreateFeatureV2Request
+	if err := DecodeInput([]byte(data), "f.yaml", &req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.Name != "From Env" || req.Key != "fallback" || req.Description != "default ${LITERAL}" {
+		t.Errorf("unexpected interpolation: %+v", req)
+	}
+
+	err := DecodeInput([]byte("name: a\nkey: ${DVCX_TEST_UNSET}\n"), "f.yaml", &req)
+	var inputErr *InputError
+	if !errors.As(err, &inputErr) || inputErr.Line != 2 || !strings.Contains(err.Error(), "DVCX_TEST_UNSET is not set") {
+		t.Errorf("expected unset variable error at line 2, got %v", err)
+	}
+}
+
+func TestDecodeInputErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		data       string
+		wantLine   int
+		wantColumn int
+		wantText   string
+	}{
+		{name: "json syntax", path: "f.json", data: "{\n  \"key\": \"a\"\n  \"name\": \"b\"\n}", wantLine: 3, wantColumn: 3, wantText: "JSON from f.json at line 3, column 3"},
+		{name: "json type", path: "f.json", data: "{\n  \"key\": \"a\",\n  \"tags\": \"ui\"\n}", wantLine: 3, wantText: "cannot unmarshal string"},
+		{name: "yaml syntax", path: "-", data: "key: a\nname: b: c\n", wantLine: 2, wantText: "YAML from stdin at line 2: mapping values"},
+		{name: "empty", path: "f.yaml", data: "# nothing\n", wantText: "input is empty"},
+		{name: "several documents", path: "f.yaml", data: "key: a\n---\nkey: b\n", wantText: "expected a single document, found 2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req CreateFeatureV2Request
+			err := DecodeInput([]byte(tt.data), tt.path, &req)
+			var inputErr *InputError
+			if !errors.As(err, &inputErr) {
+				t.Fatalf("expected InputError, got %v", err)
+			}
+			if tt.wantLine > 0 && inputErr.Line != tt.wantLine {
+				t.Errorf("expected line %d, got %d (%v)", tt.wantLine, inputErr.Line, err)
+			}
+			if tt.wantColumn > 0 && inputErr.Column != tt.wantColumn {
+				t.Errorf("expected column %d, got %d (%v)", tt.wantColumn, inputErr.Column, err)
+			}
+			if !strings.Contains(err.Error(), tt.wantText) {
+				t.Errorf("expected error containing %q, got %v", tt.wantText, err)
+			}
+		})
+	}
+}
+
+func TestDecodeInputAll(t *testing.T) {
+	data := "name: One\nkey: one\n---\n# second\nname: Two\nkey: two\n---\n"
+	reqs, err := DecodeInputAll[CreateFeatureV2Request]([]byte(data), "features.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(reqs) != 2 || reqs[0].Key != "one" || reqs[1].Key != "two" {
+		t.Errorf("unexpected documents: %+v", reqs)
+	}
+
+	reqs, err = DecodeInputAll[CreateFeatureV2Request]([]byte(`{"key": "only"}`), "-")
+	if err != nil || len(reqs) != 1 || reqs[0].Key != "only" {
+		t.Errorf("expected a single JSON document, got %+v, %v", reqs, err)
+	}
+
+	_, err = DecodeInputAll[CreateFeatureV2Request]([]byte("key: one\n---\nkey: two: three\n"), "features.yaml")
+	var inputErr *InputError
+	if !errors.As(err, &inputErr) || inputErr.Line != 3 {
+		t.Errorf("expected error at line 3, got %v", err)
+	}
+}
@@ -37,6 +37,7 @@ type Feature struct {
 	Description string    `json:"description,omitempty"`
 	Type        string    `json:"type"`
 	Status      string    `json:"status,omitempty"`
+	Tags        []string  `json:"tags,omitempty"`
 	CreatedAt   time.Time `json:"createdAt"`
 	UpdatedAt   time.Time `json:"updatedAt"`
 }
@@ -45,14 +46,16 @@ type Feature struct {
 // Variables hold typed values (String, Boolean, Number, JSON) that can be
 // served to clients based on targeting rules.
 type Variable struct {
-	ID          string    `json:"_id"`
-	Key         string    `json:"key"`
-	Name        string    `json:"name"`
-	Description string    `json:"description,omitempty"`
-	Type        string    `json:"type"`
-	Status      string    `json:"status,omitempty"`
-	CreatedAt   time.Time `json:"createdAt"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	ID               string                    `json:"_id"`
+	Key              string                    `json:"key"`
+	Name             string                    `json:"name"`
+	Description      string                    `json:"description,omitempty"`
+	Type             string                    `json:"type"`
+	Status           string                    `json:"status,omitempty"`
+	Feature          string                    `json:"_feature,omitempty"`
+	ValidationSchema *VariableValidationSchema `json:"validationSchema,omitempty"`
+	CreatedAt        time.Time                 `json:"createdAt"`
+	UpdatedAt        time.Time                 `json:"updatedAt"`
 }
 
 // Variation represents a feature variation
@@ -105,10 +108,21 @@ type FeatureSettings struct {
 
 // VariableDefinition represents a variable definition for v2 API
 type VariableDefinition struct {
-	Key         string `json:"key"`
-	Name        string `json:"name,omitempty"`
-	Type        string `json:"type"` // String, Boolean, Number, JSON
-	Description string `json:"description,omitempty"`
+	Key              string                    `json:"key"`
+	Name             string                    `json:"name,omitempty"`
+	Type             string                    `json:"type"` // String, Boolean, Number, JSON
+	Description      string                    `json:"description,omitempty"`
+	ValidationSchema *VariableValidationSchema `json:"validationSchema,omitempty"`
+}
+
+// VariableValidationSchema restricts the values of a variable
+type VariableValidationSchema struct {
+	SchemaType   string `json:"schemaType"` // enum, regex, jsonSchema
+	EnumValues   []any  `json:"enumValues,omitempty"`
+	RegexPattern string `json:"regexPattern,omitempty"`
+	JSONSchema   string `json:"jsonSchema,omitempty"` // a JSON Schema document, as a string
+	Description  string `json:"description,omitempty"`
+	ExampleValue any    `json:"exampleValue,omitempty"`
 }
 
 // VariationDefinition represents a variation definition for v2 API
@@ -126,6 +140,7 @@ type EnvironmentConfig struct {
 
 // Target represents a targeting rule
 type Target struct {
+	ID           string         `json:"_id,omitempty"`
 	Name         string         `json:"name,omitempty"`
 	Audience     Audience       `json:"audience"`
 	Distribution []Distribution `json:"distribution"`
@@ -143,12 +158,18 @@ type Filters struct {
 	Filters  []Filter `json:"filters"`
 }
 
-// Filter represents an individual filter
+// Filter represents an individual filter.
+// A filter with Operator and Filters set is a nested group of filters.
 type Filter struct {
-	Type       string `json:"type"` // all, user, audienceMatch, optIn
-	SubType    string `json:"subType,omitempty"`
-	Comparator string `json:"comparator,omitempty"`
-	Values     any    `json:"values,omitempty"`
+	Type        string   `json:"type,omitempty"` // all, user, audienceMatch, optIn
+	SubType     string   `json:"subType,omitempty"`
+	Comparator  string   `json:"comparator,omitempty"`
+	DataKey     string   `json:"dataKey,omitempty"`     // custom data property for subType customData
+	DataKeyType string   `json:"dataKeyType,omitempty"` // String, Boolean, Number
+	Values      any      `json:"values,omitempty"`
+	Audiences   []string `json:"_audiences,omitempty"` // audience IDs for type audienceMatch
+	Operator    string   `json:"operator,omitempty"`   // and, or (nested groups only)
+	Filters     []Filter `json:"filters,omitempty"`    // nested groups only
 }
 
 // Distribution represents variation distribution
@@ -16,6 +16,32 @@ func (c *Client) Variables(ctx context.Context, projectKey string) ([]Variable,
 	return variables, nil
 }
 
+// VariableListOptions contains options for listing variables
+type VariableListOptions struct {
+	Status string // active, archived
+}
+
+// VariablesWithOptions returns the variables of a project that match opts.
+func (c *Client) VariablesWithOptions(ctx context.Context, projectKey string, opts *VariableListOptions) ([]Variable, error) {
+	var variables []Variable
+	path := fmt.Sprintf("/projects/%s/variables", url.PathEscape(projectKey))
+
+	if opts != nil {
+		params := url.Values{}
+		if opts.Status != "" {
+			params.Set("status", opts.Status)
+		}
+		if len(params) > 0 {
+			path += "?" + params.Encode()
+		}
+	}
+
+	if err := c.Get(ctx, path, &variables); err != nil {
+		return nil, fmt.Errorf("failed to list variables: %w", err)
+	}
+	return variables, nil
+}
+
 // Variable returns a specific variable by its key.
 func (c *Client) Variable(ctx context.Context, projectKey, variableKey string) (*Variable, error) {
 	var variable Variable
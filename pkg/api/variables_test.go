@@ -56,6 +56,32 @@ func TestClient_Variables(t *testing.T) {
 	})
 }
 
+func TestClient_VariablesWithOptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/projects/my-project/variables" {
+			t.Errorf("expected /projects/my-project/variables, got %s", r.URL.Path)
+		}
+		want := map[string]string{"status": "active"}
+		for param, value := range want {
+			if got := r.URL.Query().Get(param); got != value {
+				t.Errorf("expected %s=%s, got %q", param, value, got)
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]Variable{{Key: "enable-feature", Type: "Boolean"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithToken("test-token"))
+	result, err := client.VariablesWithOptions(context.Background(), "my-project", &VariableListOptions{Status: "active"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result) != 1 || result[0].Key != "enable-feature" {
+		t.Errorf("unexpected variables: %+v", result)
+	}
+}
+
 func TestClient_Variable(t *testing.T) {
 	t.Run("successful get", func(t *testing.T) {
 		variable := Variable{